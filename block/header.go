@@ -0,0 +1,73 @@
+package block
+
+import (
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+// Header is the header of the block decoded from the bytes written by
+// MarshalHeader. It can be used without the body of the block, for
+// example by the light clients.
+type Header struct {
+	Version                int
+	Height                 int64
+	Timestamp              int64
+	Proposer               module.Address
+	PrevID                 []byte
+	VotesHash              []byte
+	NextValidatorsHash     []byte
+	PatchTransactionsHash  []byte
+	NormalTransactionsHash []byte
+	LogsBloom              []byte
+	Result                 []byte
+
+	id []byte
+}
+
+// ID returns the ID of the block, which is the hash of the header.
+func (h *Header) ID() []byte {
+	return h.id
+}
+
+// NewHeaderFromBytes decodes the header of the block. Only the header of
+// version 2 is supported. The ID is the hash of the bytes, so it's same as
+// the one of the block only if the bytes are written by MarshalHeader.
+func NewHeaderFromBytes(bs []byte) (*Header, error) {
+	var hf blockV2HeaderFormat
+	remain, err := v2Codec.UnmarshalFromBytes(bs, &hf)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidHeader")
+	}
+	if len(remain) > 0 {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidHeader(trailing=%d)", len(remain))
+	}
+	if hf.Version != module.BlockVersion2 {
+		return nil, errors.UnsupportedError.Errorf(
+			"UnsupportedBlockVersion(version=%d)", hf.Version)
+	}
+	var proposer module.Address
+	if len(hf.Proposer) > 0 {
+		addr := new(common.Address)
+		if err := addr.SetBytes(hf.Proposer); err != nil {
+			return nil, errors.IllegalArgumentError.Wrap(err, "InvalidProposer")
+		}
+		proposer = addr
+	}
+	return &Header{
+		Version:                hf.Version,
+		Height:                 hf.Height,
+		Timestamp:              hf.Timestamp,
+		Proposer:               proposer,
+		PrevID:                 hf.PrevID,
+		VotesHash:              hf.VotesHash,
+		NextValidatorsHash:     hf.NextValidatorsHash,
+		PatchTransactionsHash:  hf.PatchTransactionsHash,
+		NormalTransactionsHash: hf.NormalTransactionsHash,
+		LogsBloom:              hf.LogsBloom,
+		Result:                 hf.Result,
+		id:                     crypto.SHA3Sum256(bs),
+	}, nil
+}
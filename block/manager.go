@@ -16,6 +16,7 @@ import (
 	"github.com/icon-project/goloop/service/txresult"
 
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/db"
 
 	"github.com/icon-project/goloop/common"
@@ -1655,3 +1656,35 @@ func GetLastHeightOf(dbase db.Database) int64 {
 	}
 	return height
 }
+
+// GetLastBlockInfoOf returns height, ID and result of the last block stored
+// in the database. It's used to inspect the database without the manager.
+func GetLastBlockInfoOf(dbase db.Database) (int64, []byte, []byte, error) {
+	height := GetLastHeightOf(dbase)
+	hb := newBucket(dbase, db.BlockHeaderHashByHeight, dbCodec)
+	bb := newBucket(dbase, db.BytesByHash, dbCodec)
+	if hb == nil || bb == nil {
+		return 0, nil, nil, errors.InvalidStateError.New("FailToGetBucket")
+	}
+	id, err := hb.getBytes(height)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	headerBytes, err := bb.getBytes(raw(id))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if !bytes.Equal(crypto.SHA3Sum256(headerBytes), id) {
+		return 0, nil, nil, errors.InvalidStateError.Errorf(
+			"InvalidBlockHeader(height=%d,id=%#x)", height, id)
+	}
+	var header blockV2HeaderFormat
+	if _, err := v2Codec.UnmarshalFromBytes(headerBytes, &header); err != nil {
+		return 0, nil, nil, err
+	}
+	if header.Height != height {
+		return 0, nil, nil, errors.InvalidStateError.Errorf(
+			"InvalidBlockHeight(exp=%d,real=%d)", height, header.Height)
+	}
+	return height, id, header.Result, nil
+}
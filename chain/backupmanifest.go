@@ -0,0 +1,249 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/service/transitionresult"
+)
+
+// BackupManifestFile is the name of the entry for the manifest in the backup.
+const BackupManifestFile = "MANIFEST.json"
+
+type BackupFile struct {
+	Name     string          `json:"name"`
+	Size     int64           `json:"size"`
+	Checksum common.HexBytes `json:"checksum"`
+}
+
+// BackupManifest describes the chain data in the backup. Files has all the
+// files of the chain data including the ones stored only in the base backups,
+// so the last manifest is enough to verify the restored data.
+type BackupManifest struct {
+	BackupInfo
+	Files []BackupFile `json:"files"`
+}
+
+// FileOf returns the file of the name. It returns nil if there is no file
+// with the name.
+func (m *BackupManifest) FileOf(name string) *BackupFile {
+	idx := sort.Search(len(m.Files), func(i int) bool {
+		return m.Files[i].Name >= name
+	})
+	if idx < len(m.Files) && m.Files[idx].Name == name {
+		return &m.Files[idx]
+	}
+	return nil
+}
+
+// VerifyFiles checks sizes and checksums of the files under the directory.
+// Files listed in the manifests of the base backups but not in the manifest
+// are removed, because they are removed after the base backups. The other
+// files are kept.
+func (m *BackupManifest) VerifyFiles(dir string, bases ...*BackupManifest) error {
+	for _, f := range m.Files {
+		size, sum, err := checksumOf(path.Join(dir, f.Name))
+		if err != nil {
+			return errors.NotFoundError.Wrapf(err,
+				"FileNotFound(name=%s)", f.Name)
+		}
+		if size != f.Size || !bytes.Equal(sum, f.Checksum) {
+			return errors.InvalidStateError.Errorf(
+				"InvalidChecksum(name=%s,size=%d,exp=%d)", f.Name, size, f.Size)
+		}
+	}
+	for _, base := range bases {
+		if base == nil {
+			continue
+		}
+		for _, f := range base.Files {
+			if m.FileOf(f.Name) != nil {
+				continue
+			}
+			err := os.Remove(path.Join(dir, f.Name))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// VerifyDatabase checks the last block and its state root in the database of
+// the chain directory. dbType and nid are used to open the database.
+func (m *BackupManifest) VerifyDatabase(chainDir, dbType string, nid int) error {
+	if len(m.BlockHash) == 0 {
+		return nil
+	}
+	if dbType == "" {
+		dbType = string(db.GoLevelDBBackend)
+	}
+	dbName := strconv.FormatInt(int64(nid), 16)
+	database, err := db.Open(path.Join(chainDir, DefaultDBDir), dbType, dbName)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	height, id, stateRoot, err := lastBlockInfoOf(database)
+	if err != nil {
+		return err
+	}
+	if height != m.Height || !bytes.Equal(id, m.BlockHash) {
+		return errors.InvalidStateError.Errorf(
+			"InvalidLastBlock(exp=%d:%#x,real=%d:%#x)",
+			m.Height, []byte(m.BlockHash), height, id)
+	}
+	if !bytes.Equal(stateRoot, m.StateRoot) {
+		return errors.InvalidStateError.Errorf(
+			"InvalidStateRoot(exp=%#x,real=%#x)", []byte(m.StateRoot), stateRoot)
+	}
+	if len(stateRoot) > 0 {
+		bk, err := database.GetBucket(db.MerkleTrie)
+		if err != nil {
+			return err
+		}
+		if !bk.Has(stateRoot) {
+			return errors.NotFoundError.Errorf(
+				"StateRootNotFound(root=%#x)", stateRoot)
+		}
+	}
+	return nil
+}
+
+// lastBlockInfoOf returns height, ID and state root of the last block in
+// the database.
+func lastBlockInfoOf(database db.Database) (int64, []byte, []byte, error) {
+	height, id, result, err := block.GetLastBlockInfoOf(database)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if len(result) == 0 {
+		return height, id, nil, nil
+	}
+	stateRoot, err := transitionresult.StateHashOf(result)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return height, id, stateRoot, nil
+}
+
+func checksumOf(p string) (int64, []byte, error) {
+	fd, err := os.Open(p)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer fd.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, fd)
+	if err != nil {
+		return 0, nil, err
+	}
+	return size, h.Sum(nil), nil
+}
+
+// listFiles returns names of regular files under the path in the order of
+// their names.
+func listFiles(p, n string) ([]string, error) {
+	p2 := path.Join(p, n)
+	st, err := os.Stat(p2)
+	if err != nil {
+		return nil, err
+	}
+	if st.Mode().IsRegular() {
+		return []string{n}, nil
+	} else if !st.IsDir() {
+		return nil, nil
+	}
+	fis, err := ioutil.ReadDir(p2)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, fi := range fis {
+		if sub, err := listFiles(p, path.Join(n, fi.Name())); err != nil {
+			return nil, err
+		} else {
+			names = append(names, sub...)
+		}
+	}
+	return names, nil
+}
+
+func writeBackupManifest(zw *zip.Writer, m *BackupManifest) error {
+	bs, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	fw, err := zw.CreateHeader(&zip.FileHeader{
+		Name:   BackupManifestFile,
+		Method: zip.Deflate,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fw.Write(bs)
+	return err
+}
+
+// ReadBackupManifest returns the manifest in the backup. It returns nil
+// for the backups made without manifest.
+func ReadBackupManifest(zr *zip.Reader) (*BackupManifest, error) {
+	for _, f := range zr.File {
+		if f.Name != BackupManifestFile {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		m := new(BackupManifest)
+		if err := json.NewDecoder(rc).Decode(m); err != nil {
+			return nil, err
+		}
+		sort.Slice(m.Files, func(i, j int) bool {
+			return m.Files[i].Name < m.Files[j].Name
+		})
+		return m, nil
+	}
+	return nil, nil
+}
+
+func GetBackupManifestOf(f string) (*BackupManifest, error) {
+	zr, err := zip.OpenReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return ReadBackupManifest(&zr.Reader)
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func writeBackupTestFile(t *testing.T, dir, name, content string) {
+	p := path.Join(dir, name)
+	assert.NoError(t, os.MkdirAll(path.Dir(p), 0700))
+	assert.NoError(t, ioutil.WriteFile(p, []byte(content), 0644))
+}
+
+func backupFileOf(t *testing.T, dir, name string) BackupFile {
+	size, sum, err := checksumOf(path.Join(dir, name))
+	assert.NoError(t, err)
+	return BackupFile{Name: name, Size: size, Checksum: sum}
+}
+
+func TestBackupManifest_FileOf(t *testing.T) {
+	mf := &BackupManifest{
+		Files: []BackupFile{
+			{Name: "contract/a"}, {Name: "db/b"}, {Name: "wal/c"},
+		},
+	}
+	assert.Equal(t, &mf.Files[1], mf.FileOf("db/b"))
+	assert.Equal(t, &mf.Files[2], mf.FileOf("wal/c"))
+	assert.Nil(t, mf.FileOf("db"))
+	assert.Nil(t, mf.FileOf("wal/d"))
+}
+
+func TestBackupManifest_VerifyFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backupmanifest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeBackupTestFile(t, dir, "db/a", "a")
+	writeBackupTestFile(t, dir, "db/b", "b")
+	writeBackupTestFile(t, dir, "db/c", "c")
+	writeBackupTestFile(t, dir, "db/d", "d")
+	writeBackupTestFile(t, dir, "local.json", "local")
+
+	base1 := &BackupManifest{Files: []BackupFile{
+		backupFileOf(t, dir, "db/a"),
+		backupFileOf(t, dir, "db/c"),
+	}}
+	base2 := &BackupManifest{Files: []BackupFile{
+		backupFileOf(t, dir, "db/a"),
+		backupFileOf(t, dir, "db/b"),
+		backupFileOf(t, dir, "db/d"),
+	}}
+	mf := &BackupManifest{Files: []BackupFile{
+		backupFileOf(t, dir, "db/a"),
+		backupFileOf(t, dir, "db/b"),
+	}}
+
+	// files removed since the base backups are removed, but files not in
+	// the base backups are kept.
+	assert.NoError(t, mf.VerifyFiles(dir, base1, nil, base2))
+	for name, exist := range map[string]bool{
+		"db/a": true, "db/b": true, "db/c": false, "db/d": false,
+		"local.json": true,
+	} {
+		_, err := os.Stat(path.Join(dir, name))
+		assert.Equal(t, exist, err == nil, name)
+	}
+
+	writeBackupTestFile(t, dir, "db/b", "B")
+	err = mf.VerifyFiles(dir)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+
+	assert.NoError(t, os.Remove(path.Join(dir, "db/a")))
+	err = mf.VerifyFiles(dir)
+	assert.True(t, errors.NotFoundError.Equals(err))
+}
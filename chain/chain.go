@@ -76,6 +76,7 @@ type singleChain struct {
 	wallet module.Wallet
 
 	database db.Database
+	gcdb     *gcDatabase
 	vld      module.CommitVoteSetDecoder
 	pd       module.PatchDecoder
 	sm       module.ServiceManager
@@ -98,6 +99,8 @@ type singleChain struct {
 	mtx        sync.RWMutex
 	task       chainTask
 	termWaiter *sync.Cond
+	gc         *taskGC
+	backup     *taskBackup
 
 	// monitor
 	metricCtx context.Context
@@ -204,6 +207,37 @@ func (c *singleChain) MaxBlockTxBytes() int {
 	return ConfigDefaultMaxBlockTxBytes
 }
 
+func (c *singleChain) TxPoolOrder() string {
+	if len(c.cfg.TxPoolOrder) > 0 {
+		return c.cfg.TxPoolOrder
+	}
+	return service.TxOrderDefault
+}
+
+func (c *singleChain) EventIndex() bool {
+	return c.cfg.EventIndex
+}
+
+func (c *singleChain) TxIndex() bool {
+	return c.cfg.TxIndex
+}
+
+func (c *singleChain) InternalTx() bool {
+	return c.cfg.InternalTx
+}
+
+func (c *singleChain) StepDetails() bool {
+	return c.cfg.StepDetails
+}
+
+func (c *singleChain) ParallelCheck() bool {
+	return c.cfg.ParallelCheck
+}
+
+func (c *singleChain) QueryCacheSize() int {
+	return c.cfg.QueryCacheSize
+}
+
 func (c *singleChain) DefaultWaitTimeout() time.Duration {
 	if c.cfg.DefWaitTimeout > 0 {
 		return time.Duration(c.cfg.DefWaitTimeout) * time.Millisecond
@@ -233,7 +267,14 @@ func (c *singleChain) State() (string, int64, error) {
 				height = blk.Height()
 			}
 		}
-		return c.task.DetailOf(c.state), height, c.lastErr
+		detail := c.task.DetailOf(c.state)
+		if c.gc != nil {
+			detail += ", " + c.gc.Detail()
+		}
+		if c.backup != nil {
+			detail += ", " + c.backup.Detail()
+		}
+		return detail, height, c.lastErr
 	default:
 		return c.state.String(), c.lastBlockHeight(), c.lastErr
 	}
@@ -370,11 +411,12 @@ func (c *singleChain) prepareDatabase(chainDir string) error {
 		cdb.Close()
 		return errors.Errorf("Unknown cache strategy:%s", c.cfg.NodeCache)
 	}
+	c.gcdb = newGCDatabase(cdb)
 	if mLevel > 0 || fLevel > 0 {
 		cacheDir := path.Join(chainDir, DefaultCacheDir)
-		c.database = cache.AttachManager(cdb, cacheDir, mLevel, fLevel)
+		c.database = cache.AttachManager(c.gcdb, cacheDir, mLevel, fLevel)
 	} else {
-		c.database = cdb
+		c.database = c.gcdb
 	}
 	return nil
 }
@@ -383,6 +425,7 @@ func (c *singleChain) releaseDatabase() {
 	if c.database != nil {
 		c.database.Close()
 		c.database = nil
+		c.gcdb = nil
 	}
 }
 
@@ -543,11 +586,116 @@ func (c *singleChain) Prune(gsfile string, dbtype string, height int64) error {
 	return c._runTask(task, false)
 }
 
-func (c *singleChain) Backup(file string, extra []string) error {
-	task := newTaskBackup(c, file, extra)
+// Backup makes a backup file of the chain. If the chain is started, then
+// it makes the backup from the snapshot of the database along with the
+// consensus.
+func (c *singleChain) Backup(file string, base string, extra []string) error {
+	c.mtx.Lock()
+	if _, ok := c.task.(*taskConsensus); ok && c.state == Started {
+		defer c.mtx.Unlock()
+		return c._startOnlineBackup(file, base, extra)
+	}
+	c.mtx.Unlock()
+
+	task := newTaskBackup(c, file, base, extra, nil)
 	return c._runTask(task, false)
 }
 
+func (c *singleChain) _startOnlineBackup(file string, base string, extra []string) error {
+	if c.backup != nil && c.backup.State() == Started {
+		return errors.InvalidStateError.New("BackupAlreadyRunning")
+	}
+	snapshot, err := db.NewSnapshot(c.database)
+	if err == db.ErrSnapshotUnsupported {
+		return errors.InvalidStateError.Errorf(
+			"OnlineBackupUnsupported(db=%s)", c.cfg.DBType)
+	} else if err != nil {
+		return err
+	}
+	task := newTaskBackup(c, file, base, extra, snapshot)
+	c.logger.Infof("STARTING %s", task.String())
+	if err := task.Start(); err != nil {
+		c.logger.Infof("FAIL to start %s err=%+v", task.String(), err)
+		return err
+	}
+	c.backup = task
+	go func() {
+		err := task.Wait()
+		c.logger.Infof("DONE %s err=%v", task.String(), err)
+	}()
+	return nil
+}
+
+// _stopBackup stops running online backup and waits for it. It should be
+// called before releasing managers.
+func (c *singleChain) _stopBackup() {
+	if c.backup != nil {
+		c.backup.Stop()
+		c.backup.Wait()
+		c.backup = nil
+	}
+}
+
+// StartGC starts pruning of the states older than the last keep blocks.
+// It runs along with the consensus, so the chain should be started.
+func (c *singleChain) StartGC(keep int64) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if _, ok := c.task.(*taskConsensus); !ok || c.state != Started {
+		return errors.InvalidStateError.Errorf(
+			"InvalidStateForGC(state=%s)", c.state.String())
+	}
+	if c.gc != nil {
+		switch c.gc.State() {
+		case Starting, Started, Stopping:
+			return errors.InvalidStateError.New("GCAlreadyRunning")
+		}
+	}
+	task := newTaskGC(c, keep)
+	c.logger.Infof("STARTING %s", task.String())
+	if err := task.Start(); err != nil {
+		c.logger.Infof("FAIL to start %s err=%+v", task.String(), err)
+		return err
+	}
+	c.gc = task
+	go func() {
+		err := task.Wait()
+		c.logger.Infof("DONE %s err=%v", task.String(), err)
+	}()
+	return nil
+}
+
+func (c *singleChain) PauseGC() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.gc == nil {
+		return errors.InvalidStateError.New("NoGCTask")
+	}
+	return c.gc.Pause()
+}
+
+func (c *singleChain) ResumeGC() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.gc == nil {
+		return errors.InvalidStateError.New("NoGCTask")
+	}
+	return c.gc.Resume()
+}
+
+// _stopGC stops running GC task and waits for it. It should be called
+// before releasing managers.
+func (c *singleChain) _stopGC() {
+	if c.gc != nil {
+		c.gc.Stop()
+		c.gc.Wait()
+		c.gc = nil
+	}
+}
+
 func (c *singleChain) _handleTerminateInLock() {
 	if c.state != Terminating {
 		c.logger.Panicf("InvalidStateForTerminate(state=%s)", c.state.String())
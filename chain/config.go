@@ -39,6 +39,13 @@ type Config struct {
 	NormalTxPoolSize int    `json:"normal_tx_pool,omitempty"`
 	PatchTxPoolSize  int    `json:"patch_tx_pool,omitempty"`
 	MaxBlockTxBytes  int    `json:"max_block_tx_bytes,omitempty"`
+	TxPoolOrder      string `json:"tx_pool_order,omitempty"`
+	EventIndex       bool   `json:"event_index,omitempty"`
+	TxIndex          bool   `json:"tx_index,omitempty"`
+	InternalTx       bool   `json:"internal_tx,omitempty"`
+	StepDetails      bool   `json:"step_details,omitempty"`
+	ParallelCheck    bool   `json:"parallel_check,omitempty"`
+	QueryCacheSize   int    `json:"query_cache_size,omitempty"`
 	NodeCache        string `json:"node_cache,omitempty"`
 	AutoStart        bool   `json:"auto_start,omitempty"`
 
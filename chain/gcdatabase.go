@@ -0,0 +1,147 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/common/db"
+)
+
+// gcDatabase is the chain database with write barrier for online pruning.
+// While a GC cycle is running, trie nodes written to the database are
+// marked as live, so that the cycle doesn't delete them.
+type gcDatabase struct {
+	db.Database
+
+	lock   sync.RWMutex
+	marker *gcMarker
+}
+
+func (d *gcDatabase) GetBucket(id db.BucketID) (db.Bucket, error) {
+	bk, err := d.Database.GetBucket(id)
+	if err != nil || id != db.MerkleTrie {
+		return bk, err
+	}
+	return &gcBucket{Bucket: bk, database: d}, nil
+}
+
+func (d *gcDatabase) NewBatch() db.Batch {
+	return &gcBatch{Batch: d.Database.NewBatch(), database: d}
+}
+
+func (d *gcDatabase) NewSnapshot() (db.Snapshot, error) {
+	return db.NewSnapshot(d.Database)
+}
+
+func (d *gcDatabase) Stats(ids []db.BucketID) (*db.Statistics, error) {
+	return db.StatsOf(d.Database, ids...)
+}
+
+func (d *gcDatabase) Compact() error {
+	return db.Compact(d.Database)
+}
+
+// setMarker installs the marker for the write barrier. nil uninstalls it.
+func (d *gcDatabase) setMarker(m *gcMarker) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.marker = m
+}
+
+// deleteIfDead deletes the trie nodes which are not marked as live.
+// Writes are blocked during the deletion, so nodes written concurrently
+// are never deleted. It returns number of deleted nodes.
+func (d *gcDatabase) deleteIfDead(m *gcMarker, keys [][]byte) (int, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	batch := d.Database.NewBatch()
+	for _, key := range keys {
+		if live, err := m.isLive(db.MerkleTrie, key); err != nil {
+			return 0, err
+		} else if !live {
+			batch.Delete(db.MerkleTrie, key)
+		}
+	}
+	if batch.Len() == 0 {
+		return 0, nil
+	}
+	return batch.Len(), batch.Write()
+}
+
+// onWriteInLock marks the keys as live if a GC cycle is running. It should be
+// called with read lock.
+func (d *gcDatabase) onWriteInLock(keys ...[]byte) error {
+	if d.marker == nil {
+		return nil
+	}
+	for _, key := range keys {
+		if err := d.marker.markLive(db.MerkleTrie, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type gcBucket struct {
+	db.Bucket
+	database *gcDatabase
+}
+
+func (bk *gcBucket) Set(key []byte, value []byte) error {
+	bk.database.lock.RLock()
+	defer bk.database.lock.RUnlock()
+
+	if err := bk.database.onWriteInLock(key); err != nil {
+		return err
+	}
+	return bk.Bucket.Set(key, value)
+}
+
+type gcBatch struct {
+	db.Batch
+	database *gcDatabase
+	keys     [][]byte
+}
+
+func (b *gcBatch) Set(id db.BucketID, key []byte, value []byte) {
+	if id == db.MerkleTrie {
+		b.keys = append(b.keys, append([]byte{}, key...))
+	}
+	b.Batch.Set(id, key, value)
+}
+
+func (b *gcBatch) Reset() {
+	b.keys = nil
+	b.Batch.Reset()
+}
+
+func (b *gcBatch) Write() error {
+	b.database.lock.RLock()
+	defer b.database.lock.RUnlock()
+
+	if err := b.database.onWriteInLock(b.keys...); err != nil {
+		return err
+	}
+	return b.Batch.Write()
+}
+
+func newGCDatabase(database db.Database) *gcDatabase {
+	return &gcDatabase{Database: database}
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+)
+
+func TestGCDatabase_WriteBarrier(t *testing.T) {
+	gcdb := newGCDatabase(db.NewMapDB())
+	setGCTestNode(t, gcdb, "a")
+
+	marker := newGCMarker(gcdb, db.NewMapDB())
+	gcdb.setMarker(marker)
+
+	setGCTestNode(t, gcdb, "b")
+	batch := gcdb.NewBatch()
+	batch.Set(db.MerkleTrie, []byte("c"), []byte{})
+	batch.Set(db.BytesByHash, []byte("d"), []byte{})
+	assert.NoError(t, batch.Write())
+
+	// reset batch doesn't mark the keys
+	batch.Set(db.MerkleTrie, []byte("e"), []byte{})
+	batch.Reset()
+	assert.NoError(t, batch.Write())
+
+	for key, exp := range map[string]bool{
+		"a": false, "b": true, "c": true, "e": false,
+	} {
+		live, err := marker.isLive(db.MerkleTrie, []byte(key))
+		assert.NoError(t, err)
+		assert.Equal(t, exp, live, key)
+	}
+	live, err := marker.isLive(db.BytesByHash, []byte("d"))
+	assert.NoError(t, err)
+	assert.False(t, live)
+
+	// only nodes not marked as live are deleted
+	n, err := gcdb.deleteIfDead(marker, [][]byte{[]byte("a"), []byte("b")})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.False(t, hasGCTestNode(t, gcdb, "a"))
+	assert.True(t, hasGCTestNode(t, gcdb, "b"))
+
+	// writes are not marked after the cycle
+	gcdb.setMarker(nil)
+	setGCTestNode(t, gcdb, "f")
+	live, err = marker.isLive(db.MerkleTrie, []byte("f"))
+	assert.NoError(t, err)
+	assert.False(t, live)
+}
+
+func TestGCMarker_Views(t *testing.T) {
+	src := db.NewMapDB()
+	setGCTestNode(t, src, "a")
+	setGCTestNode(t, src, "b", "c")
+	marker := newGCMarker(src, db.NewMapDB())
+
+	live, err := marker.liveView().GetBucket(db.MerkleTrie)
+	assert.NoError(t, err)
+	assert.NoError(t, live.Set([]byte("a"), []byte{}))
+	assert.True(t, live.Has([]byte("a")))
+	assert.False(t, live.Has([]byte("b")))
+
+	// the dead view regards live nodes as visited, so they are skipped
+	dead, err := marker.deadView().GetBucket(db.MerkleTrie)
+	assert.NoError(t, err)
+	assert.True(t, dead.Has([]byte("a")))
+	assert.False(t, dead.Has([]byte("b")))
+	assert.NoError(t, dead.Set([]byte("b"), []byte{}))
+	assert.True(t, dead.Has([]byte("b")))
+	value, err := dead.Get([]byte("b"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("c"), value)
+	assert.False(t, live.Has([]byte("b")))
+
+	iter, err := marker.deadKeys(nil)
+	assert.NoError(t, err)
+	var keys []string
+	for iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	iter.Release()
+	assert.Equal(t, []string{"b"}, keys)
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	// gcDeadBucket keeps trie nodes reachable only from pruned results.
+	gcDeadBucket db.BucketID = "D"
+	// gcLivePrefix and gcVisitPrefix are prefixes of buckets for live
+	// and visited keys of each bucket of the chain database.
+	gcLivePrefix  = "L"
+	gcVisitPrefix = "X"
+)
+
+var gcMark = []byte{1}
+
+// gcMarker keeps marks of keys on a temporary database.
+type gcMarker struct {
+	src   db.Database
+	marks db.Database
+}
+
+func (m *gcMarker) set(id db.BucketID, key []byte) error {
+	bk, err := m.marks.GetBucket(id)
+	if err != nil {
+		return err
+	}
+	return bk.Set(key, gcMark)
+}
+
+func (m *gcMarker) has(id db.BucketID, key []byte) (bool, error) {
+	bk, err := m.marks.GetBucket(id)
+	if err != nil {
+		return false, err
+	}
+	v, err := bk.Get(key)
+	return v != nil, err
+}
+
+func (m *gcMarker) markLive(id db.BucketID, key []byte) error {
+	return m.set(gcLivePrefix+id, key)
+}
+
+func (m *gcMarker) isLive(id db.BucketID, key []byte) (bool, error) {
+	return m.has(gcLivePrefix+id, key)
+}
+
+func (m *gcMarker) markVisited(id db.BucketID, key []byte) error {
+	if id == db.MerkleTrie {
+		return m.set(gcDeadBucket, key)
+	}
+	return m.set(gcVisitPrefix+id, key)
+}
+
+func (m *gcMarker) isVisited(id db.BucketID, key []byte) (bool, error) {
+	if id == db.MerkleTrie {
+		return m.has(gcDeadBucket, key)
+	}
+	return m.has(gcVisitPrefix+id, key)
+}
+
+// liveView returns a database for marking live data. Exporting data to
+// the database marks them as live.
+func (m *gcMarker) liveView() db.Database {
+	return &gcMarkView{marker: m}
+}
+
+// deadView returns a database for marking dead data. Exporting data to
+// the database marks them as visited, and it skips the live data.
+func (m *gcMarker) deadView() db.Database {
+	return &gcMarkView{marker: m, dead: true}
+}
+
+// deadKeys returns an iterator of trie nodes marked as dead.
+func (m *gcMarker) deadKeys(start []byte) (db.Iterator, error) {
+	bk, err := m.marks.GetBucket(gcDeadBucket)
+	if err != nil {
+		return nil, err
+	}
+	return bk.Iterator(nil, start), nil
+}
+
+func (m *gcMarker) Close() error {
+	return m.marks.Close()
+}
+
+func newGCMarker(src, marks db.Database) *gcMarker {
+	return &gcMarker{
+		src:   src,
+		marks: marks,
+	}
+}
+
+// gcMarkView is a database used as a target of exporting data.
+// It doesn't store values, but marks the keys of them. Values of
+// marked keys are read from the source database.
+type gcMarkView struct {
+	marker *gcMarker
+	dead   bool
+}
+
+func (v *gcMarkView) GetBucket(id db.BucketID) (db.Bucket, error) {
+	bk, err := v.marker.src.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	return &gcMarkBucket{view: v, id: id, src: bk}, nil
+}
+
+func (v *gcMarkView) NewBatch() db.Batch {
+	return db.NewBucketBatch(v)
+}
+
+func (v *gcMarkView) Close() error {
+	return nil
+}
+
+type gcMarkBucket struct {
+	view *gcMarkView
+	id   db.BucketID
+	src  db.Bucket
+}
+
+func (bk *gcMarkBucket) marked(key []byte) (bool, error) {
+	if live, err := bk.view.marker.isLive(bk.id, key); err != nil || live {
+		return live, err
+	}
+	if bk.view.dead {
+		return bk.view.marker.isVisited(bk.id, key)
+	}
+	return false, nil
+}
+
+func (bk *gcMarkBucket) Get(key []byte) ([]byte, error) {
+	if marked, err := bk.marked(key); err != nil || !marked {
+		return nil, err
+	}
+	return bk.src.Get(key)
+}
+
+func (bk *gcMarkBucket) Has(key []byte) bool {
+	marked, err := bk.marked(key)
+	return err == nil && marked
+}
+
+func (bk *gcMarkBucket) Set(key []byte, value []byte) error {
+	if bk.view.dead {
+		return bk.view.marker.markVisited(bk.id, key)
+	}
+	return bk.view.marker.markLive(bk.id, key)
+}
+
+func (bk *gcMarkBucket) Delete(key []byte) error {
+	return errors.UnsupportedError.New("GCMarkViewDelete")
+}
+
+func (bk *gcMarkBucket) Iterator(prefix, start []byte) db.Iterator {
+	return db.NewErrorIterator(
+		errors.UnsupportedError.New("GCMarkViewIterator"))
+}
@@ -36,6 +36,15 @@ func (d *writerDatabase) Delete(key []byte) error {
 	panic("unsupported")
 }
 
+func (d *writerDatabase) Iterator(prefix, start []byte) db.Iterator {
+	return db.NewErrorIterator(
+		errors.UnsupportedError.Errorf("GSWriterUnsupportIterator"))
+}
+
+func (d *writerDatabase) NewBatch() db.Batch {
+	return db.NewBucketBatch(d)
+}
+
 func (d *writerDatabase) GetBucket(id db.BucketID) (db.Bucket, error) {
 	if id == db.BytesByHash || id == db.MerkleTrie {
 		return d, nil
@@ -77,6 +86,15 @@ func (d *readerDatabase) Delete(key []byte) error {
 	return errors.UnsupportedError.Errorf("GenesisStorageIsReadOnly")
 }
 
+func (d *readerDatabase) Iterator(prefix, start []byte) db.Iterator {
+	return db.NewErrorIterator(
+		errors.UnsupportedError.Errorf("GSReaderUnsupportIterator"))
+}
+
+func (d *readerDatabase) NewBatch() db.Batch {
+	return db.NewBucketBatch(d)
+}
+
 func (d *readerDatabase) GetBucket(id db.BucketID) (db.Bucket, error) {
 	if id == db.BytesByHash || id == db.MerkleTrie {
 		return d, nil
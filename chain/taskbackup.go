@@ -18,6 +18,7 @@ package chain
 
 import (
 	"archive/zip"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -25,14 +26,22 @@ import (
 	"os"
 	"path"
 	"sort"
+	"sync"
 	"sync/atomic"
 
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 )
 
-const TemporalBackupFile = ".backup"
+const (
+	TemporalBackupFile = ".backup"
+
+	// backupExportChunk is number of entries to write at once on exporting
+	// the snapshot of the database.
+	backupExportChunk = 1024
+)
 
 type BackupInfo struct {
 	NID     common.HexInt32 `json:"nid"`
@@ -40,6 +49,11 @@ type BackupInfo struct {
 	Channel string          `json:"channel"`
 	Height  int64           `json:"height"`
 	Codec   string          `json:"codec"`
+
+	// Base is the name of the base backup of the incremental backup.
+	Base      string          `json:"base,omitempty"`
+	BlockHash common.HexBytes `json:"blockHash,omitempty"`
+	StateRoot common.HexBytes `json:"stateRoot,omitempty"`
 }
 
 var backupStates = map[State]string{
@@ -49,20 +63,46 @@ var backupStates = map[State]string{
 	Finished: "backup done",
 }
 
+// taskBackup makes a backup file of the chain. If it has the snapshot of
+// the database, then it runs along with the consensus (online backup).
+// Online backup exports the snapshot to a new database to store it, and it
+// doesn't store write-ahead logs of the consensus. It can't be incremental.
 type taskBackup struct {
-	chain   *singleChain
-	file    string
-	extra   []string
-	fd      io.WriteCloser
-	zw      *zip.Writer
-	current int32
-	total   int32
-	stop    int32
-	result  resultStore
+	chain    *singleChain
+	file     string
+	base     string
+	extra    []string
+	snapshot db.Snapshot
+	fd       io.WriteCloser
+	zw       *zip.Writer
+	manifest *BackupManifest
+	baseMF   *BackupManifest
+	current  int32
+	total    int32
+	stop     int32
+	result   resultStore
+
+	mtx   sync.Mutex
+	state State
+}
+
+// backupSource is a file to store in the backup. name is the path relative
+// to dir, and it's used for the name in the backup.
+type backupSource struct {
+	dir  string
+	name string
 }
 
 func (t *taskBackup) String() string {
-	return fmt.Sprintf("Backup(file=%s)", path.Base(t.file))
+	var online string
+	if t.snapshot != nil {
+		online = ",online"
+	}
+	if len(t.base) > 0 {
+		return fmt.Sprintf("Backup(file=%s,base=%s%s)",
+			path.Base(t.file), path.Base(t.base), online)
+	}
+	return fmt.Sprintf("Backup(file=%s%s)", path.Base(t.file), online)
 }
 
 func (t *taskBackup) DetailOf(s State) string {
@@ -84,7 +124,88 @@ func (t *taskBackup) DetailOf(s State) string {
 	}
 }
 
+// Detail returns the detail of current state of the task.
+func (t *taskBackup) Detail() string {
+	return t.DetailOf(t.State())
+}
+
+func (t *taskBackup) State() State {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.state
+}
+
+func (t *taskBackup) setState(s State) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.state = s
+}
+
+// prepareManifest makes the manifest for the backup with the last block of
+// the chain. For incremental backup, it checks the base backup.
+func (t *taskBackup) prepareManifest() error {
+	c := t.chain
+	info := BackupInfo{
+		NID:     common.HexInt32{Value: int32(c.NID())},
+		CID:     common.HexInt32{Value: int32(c.CID())},
+		Channel: c.Channel(),
+		Height:  c.lastBlockHeight(),
+		Codec:   codec.BC.Name(),
+	}
+	var database db.Database = c.database
+	if t.snapshot != nil {
+		database = t.snapshot
+	}
+	if height, id, stateRoot, err := lastBlockInfoOf(database); err == nil {
+		info.Height = height
+		info.BlockHash = id
+		info.StateRoot = stateRoot
+	} else if !errors.NotFoundError.Equals(err) {
+		return err
+	}
+
+	if len(t.base) > 0 {
+		// Database files exported from the snapshot never match the ones
+		// in the base backup, so it would store everything anyway.
+		if t.snapshot != nil {
+			return errors.IllegalArgumentError.Errorf(
+				"IncrementalOnlineBackupUnsupported(base=%s)", path.Base(t.base))
+		}
+		mf, err := GetBackupManifestOf(t.base)
+		if err != nil {
+			return errors.IllegalArgumentError.Wrapf(err,
+				"InvalidBaseBackup(base=%s)", path.Base(t.base))
+		}
+		if mf == nil {
+			return errors.IllegalArgumentError.Errorf(
+				"NoManifestInBaseBackup(base=%s)", path.Base(t.base))
+		}
+		if mf.CID != info.CID || mf.NID != info.NID || mf.Codec != info.Codec {
+			return errors.IllegalArgumentError.Errorf(
+				"IncompatibleBaseBackup(base=%s,cid=%s,nid=%s,codec=%s)",
+				path.Base(t.base), mf.CID, mf.NID, mf.Codec)
+		}
+		if mf.Height > info.Height {
+			return errors.IllegalArgumentError.Errorf(
+				"BaseBackupIsNewer(base=%d,height=%d)", mf.Height, info.Height)
+		}
+		info.Base = path.Base(t.base)
+		t.baseMF = mf
+	}
+	t.manifest = &BackupManifest{BackupInfo: info}
+	return nil
+}
+
 func (t *taskBackup) Start() (ret error) {
+	defer func() {
+		if ret != nil && t.snapshot != nil {
+			t.snapshot.Release()
+		}
+	}()
+	if err := t.prepareManifest(); err != nil {
+		return err
+	}
+
 	tmp, err := ioutil.TempFile(path.Dir(t.file), TemporalBackupFile)
 	if err != nil {
 		return errors.Wrap(err, "Fail to make temporal file")
@@ -102,18 +223,15 @@ func (t *taskBackup) Start() (ret error) {
 	t.fd = tmp
 	t.zw = zip.NewWriter(tmp)
 
-	if err := writeBackupInfo(t.zw, &BackupInfo{
-		NID:     common.HexInt32{Value: int32(t.chain.NID())},
-		CID:     common.HexInt32{Value: int32(t.chain.CID())},
-		Channel: t.chain.Channel(),
-		Height:  t.chain.lastBlockHeight(),
-		Codec:   codec.BC.Name(),
-	}); err != nil {
+	if err := writeBackupInfo(t.zw, &t.manifest.BackupInfo); err != nil {
 		return err
 	}
 
-	t.chain.releaseDatabase()
+	if t.snapshot == nil {
+		t.chain.releaseDatabase()
+	}
 
+	t.setState(Started)
 	go func() {
 		err := t._backup()
 		if err == nil {
@@ -122,101 +240,44 @@ func (t *taskBackup) Start() (ret error) {
 		if err != nil {
 			os.Remove(tmp.Name())
 		}
+		if err == nil {
+			t.setState(Finished)
+		} else if errors.InterruptedError.Equals(err) {
+			t.setState(Stopped)
+		} else {
+			t.setState(Failed)
+		}
 		t.result.SetValue(err)
 	}()
 	return nil
 }
 
-func zipWrite(writer *zip.Writer, p, n string, on func(int64) error) error {
+func zipWriteFile(writer *zip.Writer, p, n string) error {
 	p2 := path.Join(p, n)
 	st, err := os.Stat(p2)
 	if err != nil {
 		return errors.Wrap(err, "writeToZip: FAIL on os.State")
 	}
-	if st.Mode().IsRegular() {
-		fd, err := os.Open(p2)
-		defer fd.Close()
-		if err != nil {
-			return errors.Wrapf(err, "writeToZip: fail to open %s", p2)
-		}
-
-		fh, err := zip.FileInfoHeader(st)
-		if err != nil {
-			return errors.Wrapf(err, "writeToZip: fail to make header for %s", p2)
-		}
-		fh.Name = n
-		fh.Method = zip.Deflate
-		zf, err := writer.CreateHeader(fh)
-		if err != nil {
-			return err
-		}
-		if err != nil {
-			return errors.Wrapf(err, "writeToZip: fail to create entry %s", n)
-		}
-		if _, err := io.Copy(zf, fd); err != nil {
-			return errors.Wrap(err, "writeToZip: fail to copy")
-		}
-		if err := on(st.Size()); err != nil {
-			return err
-		}
-		return nil
-	} else if !st.IsDir() {
-		return nil
-	}
-
-	fis, err := ioutil.ReadDir(p2)
+	fd, err := os.Open(p2)
 	if err != nil {
-		return errors.Wrap(err, "writeToZip: FAIL on ReadDir")
+		return errors.Wrapf(err, "writeToZip: fail to open %s", p2)
 	}
-	// make it generate consistent compressed zip file.
-	sort.SliceStable(fis, func(i, j int) bool {
-		return fis[i].Name() < fis[j].Name()
-	})
-	for _, fi := range fis {
-		if err := zipWrite(writer, p, path.Join(n, fi.Name()), on); err != nil {
-			return err
-		}
-	}
-	return nil
-}
+	defer fd.Close()
 
-func countFiles(p string) (int, error) {
-	st, err := os.Stat(p)
+	fh, err := zip.FileInfoHeader(st)
 	if err != nil {
-		return 0, err
+		return errors.Wrapf(err, "writeToZip: fail to make header for %s", p2)
 	}
-	if !st.IsDir() {
-		return 1, nil
-	}
-	fis, err := ioutil.ReadDir(p)
+	fh.Name = n
+	fh.Method = zip.Deflate
+	zf, err := writer.CreateHeader(fh)
 	if err != nil {
-		return 0, err
-	}
-	cnt := 0
-	for _, fi := range fis {
-		if fi.IsDir() {
-			if c, err := countFiles(path.Join(p, fi.Name())); err != nil {
-				return 0, err
-			} else {
-				cnt += c
-			}
-		} else if fi.Mode().IsRegular() {
-			cnt += 1
-		}
+		return errors.Wrapf(err, "writeToZip: fail to create entry %s", n)
 	}
-	return cnt, nil
-}
-
-func (t *taskBackup) _countFiles(chainDir string, names []string) (int, error) {
-	count := 0
-	for _, name := range names {
-		if cnt, err := countFiles(path.Join(chainDir, name)); err != nil {
-			return 0, err
-		} else {
-			count += cnt
-		}
+	if _, err := io.Copy(zf, fd); err != nil {
+		return errors.Wrap(err, "writeToZip: fail to copy")
 	}
-	return count, nil
+	return nil
 }
 
 func (t *taskBackup) _isInterrupted() bool {
@@ -231,29 +292,132 @@ func (t *taskBackup) OnWrite(int64) error {
 	return nil
 }
 
+// exportSnapshot writes all the entries of the snapshot to a new database
+// in the directory.
+func (t *taskBackup) exportSnapshot(dbDir string) error {
+	c := t.chain
+	database, err := c.openDatabase(dbDir, c.cfg.DBType)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	for _, id := range db.AllBucketIDs {
+		bk, err := t.snapshot.GetBucket(id)
+		if err != nil {
+			return err
+		}
+		if err := t.exportBucket(database, bk, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *taskBackup) exportBucket(database db.Database, bk db.Bucket, id db.BucketID) error {
+	iter := bk.Iterator(nil, nil)
+	defer iter.Release()
+
+	batch := database.NewBatch()
+	for iter.Next() {
+		batch.Set(id, iter.Key(), iter.Value())
+		if batch.Len() < backupExportChunk {
+			continue
+		}
+		if t._isInterrupted() {
+			return errors.ErrInterrupted
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	if batch.Len() > 0 {
+		return batch.Write()
+	}
+	return nil
+}
+
 func (t *taskBackup) _backup() error {
-	defer t.chain.ensureDatabase()
+	if t.snapshot != nil {
+		defer t.snapshot.Release()
+	} else {
+		defer t.chain.ensureDatabase()
+	}
 	defer t.fd.Close()
 	defer t.zw.Close()
 
-	names := append([]string{
-		DefaultWALDir, DefaultDBDir, DefaultContractDir,
-	}, t.extra...)
-
 	chainDir := t.chain.cfg.AbsBaseDir()
-	if cnt, err := t._countFiles(chainDir, names); err != nil {
-		return err
+	var sources []backupSource
+	if t.snapshot != nil {
+		tmpDir, err := ioutil.TempDir(path.Dir(t.file), TemporalBackupFile)
+		if err != nil {
+			return errors.Wrap(err, "Fail to make temporal directory")
+		}
+		defer os.RemoveAll(tmpDir)
+
+		if err := t.exportSnapshot(path.Join(tmpDir, DefaultDBDir)); err != nil {
+			return err
+		}
+		sources = append(sources, backupSource{tmpDir, DefaultDBDir})
 	} else {
-		t.total = int32(cnt)
+		sources = append(sources,
+			backupSource{chainDir, DefaultWALDir},
+			backupSource{chainDir, DefaultDBDir},
+		)
+	}
+	sources = append(sources, backupSource{chainDir, DefaultContractDir})
+	for _, name := range t.extra {
+		sources = append(sources, backupSource{chainDir, name})
 	}
 
-	for _, name := range names {
-		if err := zipWrite(t.zw, chainDir, name, t.OnWrite); err != nil {
+	var files []backupSource
+	for _, src := range sources {
+		if sub, err := listFiles(src.dir, src.name); err != nil {
 			return err
+		} else {
+			for _, name := range sub {
+				files = append(files, backupSource{src.dir, name})
+			}
 		}
 	}
+	atomic.StoreInt32(&t.total, int32(len(files)))
 
-	return nil
+	for _, file := range files {
+		name := file.name
+		size, sum, err := checksumOf(path.Join(file.dir, name))
+		if err != nil {
+			return err
+		}
+		t.manifest.Files = append(t.manifest.Files, BackupFile{
+			Name:     name,
+			Size:     size,
+			Checksum: sum,
+		})
+		// files not changed since the base backup are restored from it.
+		if t.baseMF != nil {
+			if f := t.baseMF.FileOf(name); f != nil && f.Size == size &&
+				bytes.Equal(f.Checksum, sum) {
+				if err := t.OnWrite(0); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err := zipWriteFile(t.zw, file.dir, name); err != nil {
+			return err
+		}
+		if err := t.OnWrite(size); err != nil {
+			return err
+		}
+	}
+	sort.Slice(t.manifest.Files, func(i, j int) bool {
+		return t.manifest.Files[i].Name < t.manifest.Files[j].Name
+	})
+	return writeBackupManifest(t.zw, t.manifest)
 }
 
 func (t *taskBackup) Stop() {
@@ -264,11 +428,14 @@ func (t *taskBackup) Wait() error {
 	return t.result.Wait()
 }
 
-func newTaskBackup(chain *singleChain, file string, extra []string) chainTask {
+func newTaskBackup(chain *singleChain, file, base string, extra []string, snapshot db.Snapshot) *taskBackup {
 	return &taskBackup{
-		chain: chain,
-		file:  file,
-		extra: extra,
+		chain:    chain,
+		file:     file,
+		base:     base,
+		extra:    extra,
+		snapshot: snapshot,
+		state:    Starting,
 	}
 }
 
@@ -0,0 +1,228 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+// newBackupTestChain returns the chain with the database and some files
+// in the directory.
+func newBackupTestChain(t *testing.T, dir string) *singleChain {
+	chainDir := path.Join(dir, "chain")
+	c := &singleChain{
+		cfg: Config{
+			NID:     1,
+			DBType:  string(db.GoLevelDBBackend),
+			Channel: "test",
+			BaseDir: chainDir,
+		},
+		logger: log.New(),
+	}
+	assert.NoError(t, c.prepareDatabase(chainDir))
+	writeBackupTestFile(t, chainDir, "wal/w1", "w1")
+	writeBackupTestFile(t, chainDir, "contract/c1", "c1")
+	writeBackupTestFile(t, chainDir, "contract/c2", "c2")
+	writeBackupTestFile(t, chainDir, "config.json", "{}")
+	return c
+}
+
+// readBackupTestFile returns the contents of the entries in the backup
+// except the manifest.
+func readBackupTestFile(t *testing.T, file string) (map[string]string, *BackupManifest) {
+	zr, err := zip.OpenReader(file)
+	assert.NoError(t, err)
+	defer zr.Close()
+
+	entries := make(map[string]string)
+	for _, f := range zr.File {
+		if f.Name == BackupManifestFile {
+			continue
+		}
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		bs, err := ioutil.ReadAll(rc)
+		assert.NoError(t, err)
+		rc.Close()
+		entries[f.Name] = string(bs)
+	}
+	mf, err := ReadBackupManifest(&zr.Reader)
+	assert.NoError(t, err)
+	return entries, mf
+}
+
+func runBackupTest(t *testing.T, c *singleChain, file, base string, snapshot db.Snapshot) *taskBackup {
+	task := newTaskBackup(c, file, base, []string{"config.json"}, snapshot)
+	assert.NoError(t, task.Start())
+	assert.NoError(t, task.Wait())
+	assert.Equal(t, Finished, task.State())
+	assert.Equal(t, "backup done", task.Detail())
+	return task
+}
+
+func TestTaskBackup_Incremental(t *testing.T) {
+	dir, err := ioutil.TempDir("", "taskbackup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c := newBackupTestChain(t, dir)
+	defer c.releaseDatabase()
+
+	base := path.Join(dir, "base.zip")
+	runBackupTest(t, c, base, "", nil)
+	assert.NotNil(t, c.database)
+
+	entries, mf := readBackupTestFile(t, base)
+	assert.Equal(t, "w1", entries["wal/w1"])
+	assert.Equal(t, "c1", entries["contract/c1"])
+	assert.Equal(t, "c2", entries["contract/c2"])
+	assert.Equal(t, "{}", entries["config.json"])
+	assert.Len(t, mf.Files, len(entries))
+	for _, f := range mf.Files {
+		_, ok := entries[f.Name]
+		assert.True(t, ok, f.Name)
+	}
+	assert.EqualValues(t, 1, mf.NID.Value)
+	assert.Equal(t, "test", mf.Channel)
+	assert.Empty(t, mf.Base)
+
+	chainDir := c.cfg.AbsBaseDir()
+	writeBackupTestFile(t, chainDir, "contract/c2", "c2'")
+	writeBackupTestFile(t, chainDir, "contract/c3", "c3")
+	assert.NoError(t, os.Remove(path.Join(chainDir, "contract/c1")))
+
+	inc := path.Join(dir, "inc.zip")
+	runBackupTest(t, c, inc, base, nil)
+
+	entries, mf = readBackupTestFile(t, inc)
+	assert.Equal(t, "base.zip", mf.Base)
+	assert.Equal(t, "c2'", entries["contract/c2"])
+	assert.Equal(t, "c3", entries["contract/c3"])
+	for _, name := range []string{"wal/w1", "contract/c1", "config.json"} {
+		_, ok := entries[name]
+		assert.False(t, ok, name)
+	}
+	assert.NotNil(t, mf.FileOf("wal/w1"))
+	assert.NotNil(t, mf.FileOf("config.json"))
+	assert.NotNil(t, mf.FileOf("contract/c3"))
+	assert.Nil(t, mf.FileOf("contract/c1"))
+}
+
+func TestTaskBackup_Online(t *testing.T) {
+	dir, err := ioutil.TempDir("", "taskbackup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c := newBackupTestChain(t, dir)
+	defer c.releaseDatabase()
+
+	bk, err := c.database.GetBucket(db.ChainProperty)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Set([]byte("k1"), []byte("v1")))
+	snapshot, err := db.NewSnapshot(c.database)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Set([]byte("k2"), []byte("v2")))
+
+	file := path.Join(dir, "online.zip")
+	runBackupTest(t, c, file, "", snapshot)
+
+	// the database is not released
+	assert.NoError(t, bk.Set([]byte("k3"), []byte("v3")))
+
+	// temporary files are removed
+	fis, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	for _, fi := range fis {
+		assert.False(t, strings.HasPrefix(fi.Name(), TemporalBackupFile), fi.Name())
+	}
+
+	entries, mf := readBackupTestFile(t, file)
+	_, ok := entries["wal/w1"]
+	assert.False(t, ok)
+	assert.Nil(t, mf.FileOf("wal/w1"))
+	assert.Equal(t, "c1", entries["contract/c1"])
+	assert.Equal(t, "{}", entries["config.json"])
+
+	// the database in the backup has the entries of the snapshot
+	restored := path.Join(dir, "restored")
+	for name, content := range entries {
+		if strings.HasPrefix(name, DefaultDBDir+"/") {
+			writeBackupTestFile(t, restored, name, content)
+		}
+	}
+	database, err := db.Open(path.Join(restored, DefaultDBDir), c.cfg.DBType, "1")
+	assert.NoError(t, err)
+	defer database.Close()
+	rbk, err := database.GetBucket(db.ChainProperty)
+	assert.NoError(t, err)
+	value, err := rbk.Get([]byte("k1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v1"), value)
+	assert.False(t, rbk.Has([]byte("k2")))
+}
+
+func TestTaskBackup_OnlineIncremental(t *testing.T) {
+	dir, err := ioutil.TempDir("", "taskbackup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c := newBackupTestChain(t, dir)
+	defer c.releaseDatabase()
+
+	base := path.Join(dir, "base.zip")
+	runBackupTest(t, c, base, "", nil)
+
+	snapshot, err := db.NewSnapshot(c.database)
+	assert.NoError(t, err)
+	task := newTaskBackup(c, path.Join(dir, "inc.zip"), base, nil, snapshot)
+	err = task.Start()
+	assert.Error(t, err)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	_, err = os.Stat(path.Join(dir, "inc.zip"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestTaskBackup_OnlineStop(t *testing.T) {
+	dir, err := ioutil.TempDir("", "taskbackup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c := newBackupTestChain(t, dir)
+	defer c.releaseDatabase()
+
+	snapshot, err := db.NewSnapshot(c.database)
+	assert.NoError(t, err)
+	task := newTaskBackup(c, path.Join(dir, "stop.zip"), "", nil, snapshot)
+	task.Stop()
+	assert.NoError(t, task.Start())
+	err = task.Wait()
+	assert.Error(t, err)
+	assert.Equal(t, Stopped, task.State())
+	_, err = os.Stat(path.Join(dir, "stop.zip"))
+	assert.True(t, os.IsNotExist(err))
+}
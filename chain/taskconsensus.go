@@ -71,6 +71,8 @@ func (t *taskConsensus) _start(c *singleChain) error {
 
 func (t *taskConsensus) Stop() {
 	t.chain.srv.RemoveChain(t.chain.cfg.Channel)
+	t.chain._stopGC()
+	t.chain._stopBackup()
 	t.chain.releaseManagers()
 	t.result.SetValue(errors.ErrInterrupted)
 }
@@ -0,0 +1,438 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	DefaultGCDir = "gc"
+
+	keyGCHeight = "chain.gcHeight"
+
+	// gcSweepChunk is number of nodes to delete at once. Writes to
+	// the database are blocked while the chunk is deleted.
+	gcSweepChunk = 1024
+)
+
+type gcPhase int
+
+const (
+	gcMarking gcPhase = iota
+	gcWalking
+	gcSweeping
+)
+
+func (p gcPhase) String() string {
+	switch p {
+	case gcMarking:
+		return "marking"
+	case gcWalking:
+		return "walking"
+	case gcSweeping:
+		return "sweeping"
+	default:
+		return fmt.Sprintf("invalid(%d)", int(p))
+	}
+}
+
+var gcStates = map[State]string{
+	Starting: "gc starting",
+	Stopped:  "gc stopped",
+	Stopping: "gc stopping",
+	Failed:   "gc failed",
+	Finished: "gc done",
+}
+
+// taskGC prunes state of the chain while the chain is running.
+// It keeps the world states and receipts of the last keep blocks, and
+// deletes trie nodes reachable only from the results of older blocks.
+// Receipts and event logs are stored as trie nodes, so they are deleted
+// too. But data in BytesByHash bucket (contract codes, API information and
+// validator lists) are kept, because they are shared by their hashes and
+// blocks refer to validator lists directly.
+// Entries of the transaction index and the event index for the pruned
+// blocks are removed at the end of the cycle.
+// It runs in the background of the consensus task, and can be paused and
+// resumed.
+//
+// A cycle has three phases.
+//
+//	marking  : mark nodes reachable from results of the last keep blocks
+//	walking  : mark nodes reachable from older results, except live ones
+//	sweeping : delete marked nodes of walking phase
+//
+// Nodes written while the cycle is running are marked as live by gcDatabase.
+type taskGC struct {
+	chain  *singleChain
+	keep   int64
+	result resultStore
+
+	database *gcDatabase
+	marker   *gcMarker
+	markDir  string
+
+	from int64
+	to   int64
+	last int64
+
+	mtx     sync.Mutex
+	cond    *sync.Cond
+	state   State
+	paused  bool
+	stopped bool
+	phase   gcPhase
+	current int64
+	total   int64
+	deleted int64
+}
+
+func (t *taskGC) String() string {
+	return fmt.Sprintf("GC(keep=%d)", t.keep)
+}
+
+func (t *taskGC) DetailOf(s State) string {
+	switch s {
+	case Started:
+		t.mtx.Lock()
+		defer t.mtx.Unlock()
+		var detail string
+		if t.phase == gcSweeping {
+			detail = fmt.Sprintf("gc %s deleted=%d", t.phase, t.deleted)
+		} else {
+			detail = fmt.Sprintf("gc %s %d/%d", t.phase, t.current, t.total)
+		}
+		if t.paused {
+			detail += " paused"
+		}
+		return detail
+	case Finished:
+		t.mtx.Lock()
+		defer t.mtx.Unlock()
+		return fmt.Sprintf("gc done deleted=%d", t.deleted)
+	default:
+		if st, ok := gcStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+// Detail returns the detail of current state of the task.
+func (t *taskGC) Detail() string {
+	return t.DetailOf(t.State())
+}
+
+func (t *taskGC) State() State {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.state
+}
+
+func (t *taskGC) setState(s State) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.state = s
+}
+
+func (t *taskGC) Start() (ret error) {
+	c := t.chain
+	if t.keep < 1 {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidKeep(keep=%d)", t.keep)
+	}
+	if c.gcdb == nil {
+		return errors.InvalidStateError.New("NoDatabaseForGC")
+	}
+	chainDir := c.cfg.AbsBaseDir()
+	t.markDir = path.Join(chainDir, DefaultGCDir)
+	if err := os.RemoveAll(t.markDir); err != nil {
+		return err
+	}
+	marks, err := c.openDatabase(t.markDir, c.cfg.DBType)
+	if err != nil {
+		return err
+	}
+	t.database = c.gcdb
+	t.marker = newGCMarker(c.database, marks)
+
+	// The marker is installed before the last block is read. Otherwise nodes
+	// of a block finalized in between are neither marked as written nor
+	// reachable from the live results.
+	t.database.setMarker(t.marker)
+	defer func() {
+		if ret != nil {
+			t.database.setMarker(nil)
+			t.marker.Close()
+			os.RemoveAll(t.markDir)
+		}
+	}()
+
+	blk, err := c.bm.GetLastBlock()
+	if err != nil {
+		return err
+	}
+	t.last = blk.Height()
+	t.to = t.last - t.keep
+	t.from, err = t.startHeight()
+	if err != nil {
+		return err
+	}
+
+	t.setState(Started)
+	go t.doGC()
+	return nil
+}
+
+// startHeight returns the lowest height of the results not pruned yet.
+func (t *taskGC) startHeight() (int64, error) {
+	c := t.chain
+	bk, err := c.database.GetBucket(db.ChainProperty)
+	if err != nil {
+		return 0, err
+	}
+	bs, err := bk.Get([]byte(keyGCHeight))
+	if err != nil {
+		return 0, err
+	}
+	if bs != nil {
+		var height int64
+		if _, err := codec.BC.UnmarshalFromBytes(bs, &height); err != nil {
+			return 0, err
+		}
+		return height, nil
+	}
+	blk, _, err := c.bm.GetGenesisData()
+	if err != nil {
+		return 0, err
+	}
+	if blk != nil {
+		return blk.Height(), nil
+	}
+	return 0, nil
+}
+
+func (t *taskGC) setStartHeight(height int64) error {
+	bk, err := t.chain.database.GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	return bk.Set([]byte(keyGCHeight), codec.BC.MustMarshalToBytes(height))
+}
+
+func (t *taskGC) doGC() {
+	err := t._gc()
+	t.database.setMarker(nil)
+	t.marker.Close()
+	os.RemoveAll(t.markDir)
+	if err == nil {
+		t.setState(Finished)
+	} else if errors.InterruptedError.Equals(err) {
+		t.setState(Stopped)
+	} else {
+		t.chain.logger.Warnf("GC fail err=%+v", err)
+		t.setState(Failed)
+	}
+	t.result.SetValue(err)
+}
+
+// checkPoint waits while the task is paused. It returns error if the task
+// is stopped.
+func (t *taskGC) checkPoint() error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	for t.paused && !t.stopped {
+		t.cond.Wait()
+	}
+	if t.stopped {
+		return errors.ErrInterrupted
+	}
+	return nil
+}
+
+func (t *taskGC) setProgress(phase gcPhase, current, total int64) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.phase = phase
+	t.current = current
+	t.total = total
+}
+
+func (t *taskGC) onDelete(n int) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.deleted += int64(n)
+}
+
+func (t *taskGC) _gc() error {
+	c := t.chain
+	if t.from > t.to {
+		c.logger.Infof("GC skip from=%d to=%d", t.from, t.to)
+		return nil
+	}
+
+	c.logger.Infof("GC mark live results from=%d to=%d", t.to+1, t.last)
+	live := t.marker.liveView()
+	for h := t.to + 1; h <= t.last; h++ {
+		t.setProgress(gcMarking, h-t.to-1, t.keep)
+		if err := t.checkPoint(); err != nil {
+			return err
+		}
+		blk, err := c.bm.GetBlockByHeight(h)
+		if err != nil {
+			return err
+		}
+		if err := c.sm.ExportResult(blk.Result(), blk.NextValidatorsHash(), live); err != nil {
+			return err
+		}
+	}
+
+	c.logger.Infof("GC mark dead results from=%d to=%d", t.from, t.to)
+	dead := t.marker.deadView()
+	for h := t.from; h <= t.to; h++ {
+		t.setProgress(gcWalking, h-t.from, t.to-t.from+1)
+		if err := t.checkPoint(); err != nil {
+			return err
+		}
+		blk, err := c.bm.GetBlockByHeight(h)
+		if err != nil {
+			if errors.NotFoundError.Equals(err) {
+				continue
+			}
+			return err
+		}
+		err = c.sm.ExportResult(blk.Result(), blk.NextValidatorsHash(), dead)
+		if err != nil {
+			if errors.NotFoundError.Equals(err) {
+				// some of data are already removed.
+				c.logger.Debugf("GC skip height=%d err=%v", h, err)
+				continue
+			}
+			return err
+		}
+	}
+
+	c.logger.Infof("GC sweep dead nodes")
+	t.setProgress(gcSweeping, 0, 0)
+	var start []byte
+	for {
+		if err := t.checkPoint(); err != nil {
+			return err
+		}
+		keys, err := t.nextDeadKeys(start)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			break
+		}
+		n, err := t.database.deleteIfDead(t.marker, keys)
+		if err != nil {
+			return err
+		}
+		t.onDelete(n)
+		start = append(keys[len(keys)-1], 0)
+	}
+
+	c.logger.Infof("GC prune transaction index below=%d", t.to+1)
+	if err := c.sm.PruneTransactionIndex(t.to + 1); err != nil {
+		return err
+	}
+	c.logger.Infof("GC prune event index below=%d", t.to+1)
+	if err := c.sm.PruneEventIndex(t.to + 1); err != nil {
+		return err
+	}
+
+	c.logger.Infof("GC done deleted=%d", t.deleted)
+	return t.setStartHeight(t.to + 1)
+}
+
+func (t *taskGC) nextDeadKeys(start []byte) ([][]byte, error) {
+	iter, err := t.marker.deadKeys(start)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Release()
+
+	var keys [][]byte
+	for len(keys) < gcSweepChunk && iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	return keys, iter.Error()
+}
+
+func (t *taskGC) Pause() error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.state != Started {
+		return errors.InvalidStateError.Errorf(
+			"InvalidStateToPause(state=%s)", t.state)
+	}
+	t.paused = true
+	return nil
+}
+
+func (t *taskGC) Resume() error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.state != Started || !t.paused {
+		return errors.InvalidStateError.Errorf(
+			"InvalidStateToResume(state=%s,paused=%v)", t.state, t.paused)
+	}
+	t.paused = false
+	t.cond.Broadcast()
+	return nil
+}
+
+func (t *taskGC) Stop() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.state == Started {
+		t.state = Stopping
+	}
+	t.stopped = true
+	t.cond.Broadcast()
+}
+
+func (t *taskGC) Wait() error {
+	return t.result.Wait()
+}
+
+func newTaskGC(chain *singleChain, keep int64) *taskGC {
+	t := &taskGC{
+		chain: chain,
+		keep:  keep,
+		state: Starting,
+	}
+	t.cond = sync.NewCond(&t.mtx)
+	return t
+}
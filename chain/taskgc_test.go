@@ -0,0 +1,384 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+// gcTestBlock is a block whose result is the key of the root node.
+type gcTestBlock struct {
+	module.Block
+	height int64
+	result []byte
+}
+
+func (b *gcTestBlock) Height() int64 {
+	return b.height
+}
+
+func (b *gcTestBlock) Result() []byte {
+	return b.result
+}
+
+func (b *gcTestBlock) NextValidatorsHash() []byte {
+	return nil
+}
+
+type gcTestBlockManager struct {
+	module.BlockManager
+	blocks []*gcTestBlock
+}
+
+func (bm *gcTestBlockManager) GetBlockByHeight(height int64) (module.Block, error) {
+	if height < 0 || height >= int64(len(bm.blocks)) {
+		return nil, errors.NotFoundError.Errorf("NoBlock(height=%d)", height)
+	}
+	return bm.blocks[height], nil
+}
+
+func (bm *gcTestBlockManager) GetLastBlock() (module.Block, error) {
+	return bm.blocks[len(bm.blocks)-1], nil
+}
+
+func (bm *gcTestBlockManager) GetGenesisData() (module.Block, module.CommitVoteSet, error) {
+	return bm.blocks[0], nil, nil
+}
+
+// gcTestServiceManager exports trie nodes of the test. Value of a node is
+// the list of the keys of its children separated by comma.
+type gcTestServiceManager struct {
+	module.ServiceManager
+	database db.Database
+
+	lock     sync.Mutex
+	onExport func(result []byte, dst db.Database)
+	exported []string
+	txIndex  int64
+	evIndex  int64
+}
+
+func (sm *gcTestServiceManager) ExportResult(result []byte, vh []byte, dst db.Database) error {
+	sm.lock.Lock()
+	sm.exported = append(sm.exported, string(result))
+	onExport := sm.onExport
+	sm.lock.Unlock()
+
+	if onExport != nil {
+		onExport(result, dst)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	src, err := sm.database.GetBucket(db.MerkleTrie)
+	if err != nil {
+		return err
+	}
+	bk, err := dst.GetBucket(db.MerkleTrie)
+	if err != nil {
+		return err
+	}
+	return exportGCTestNode(src, bk, string(result))
+}
+
+func exportGCTestNode(src, dst db.Bucket, key string) error {
+	if dst.Has([]byte(key)) {
+		return nil
+	}
+	value, err := src.Get([]byte(key))
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return errors.NotFoundError.Errorf("NoNode(key=%s)", key)
+	}
+	if err := dst.Set([]byte(key), value); err != nil {
+		return err
+	}
+	if len(value) == 0 {
+		return nil
+	}
+	for _, child := range strings.Split(string(value), ",") {
+		if err := exportGCTestNode(src, dst, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sm *gcTestServiceManager) PruneTransactionIndex(height int64) error {
+	sm.txIndex = height
+	return nil
+}
+
+func (sm *gcTestServiceManager) PruneEventIndex(height int64) error {
+	sm.evIndex = height
+	return nil
+}
+
+func (sm *gcTestServiceManager) exportedResults() []string {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+	return append([]string{}, sm.exported...)
+}
+
+func setGCTestNode(t *testing.T, database db.Database, key string, children ...string) {
+	bk, err := database.GetBucket(db.MerkleTrie)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Set([]byte(key), []byte(strings.Join(children, ","))))
+}
+
+func hasGCTestNode(t *testing.T, database db.Database, key string) bool {
+	bk, err := database.GetBucket(db.MerkleTrie)
+	assert.NoError(t, err)
+	return bk.Has([]byte(key))
+}
+
+// newGCTestChain returns the chain with the blocks whose results are the
+// roots. Nodes of the roots should be set by the caller, and the base
+// directory of the chain should be removed after use.
+func newGCTestChain(t *testing.T, roots ...string) (*singleChain, *gcTestBlockManager, *gcTestServiceManager) {
+	dir, err := ioutil.TempDir("", "taskgc")
+	assert.NoError(t, err)
+
+	gcdb := newGCDatabase(db.NewMapDB())
+	bm := &gcTestBlockManager{}
+	for _, root := range roots {
+		addGCTestBlock(bm, root)
+	}
+	sm := &gcTestServiceManager{database: gcdb}
+	c := &singleChain{
+		cfg: Config{
+			NID:     1,
+			DBType:  string(db.MapDBBackend),
+			BaseDir: dir,
+		},
+		database: gcdb,
+		gcdb:     gcdb,
+		bm:       bm,
+		sm:       sm,
+		logger:   log.New(),
+	}
+	return c, bm, sm
+}
+
+func addGCTestBlock(bm *gcTestBlockManager, root string) {
+	bm.blocks = append(bm.blocks, &gcTestBlock{
+		height: int64(len(bm.blocks)),
+		result: []byte(root),
+	})
+}
+
+func runGCTest(t *testing.T, c *singleChain, keep int64) *taskGC {
+	task := newTaskGC(c, keep)
+	assert.NoError(t, task.Start())
+	assert.NoError(t, task.Wait())
+	assert.Equal(t, Finished, task.State())
+	return task
+}
+
+func TestTaskGC_MarkAndSweep(t *testing.T) {
+	c, _, sm := newGCTestChain(t, "r0", "r1", "r2", "r3", "r4")
+	defer os.RemoveAll(c.cfg.BaseDir)
+	setGCTestNode(t, c.database, "r0", "a", "s")
+	setGCTestNode(t, c.database, "a")
+	setGCTestNode(t, c.database, "s")
+	setGCTestNode(t, c.database, "r1", "s", "b")
+	setGCTestNode(t, c.database, "b", "a")
+	setGCTestNode(t, c.database, "r2", "s")
+	setGCTestNode(t, c.database, "r3", "s", "c")
+	setGCTestNode(t, c.database, "c", "b")
+	setGCTestNode(t, c.database, "r4", "c", "d")
+	setGCTestNode(t, c.database, "d")
+
+	task := runGCTest(t, c, 2)
+
+	// nodes reachable from the results of the last 2 blocks are kept
+	for _, key := range []string{"r3", "r4", "s", "c", "b", "a", "d"} {
+		assert.True(t, hasGCTestNode(t, c.database, key), key)
+	}
+	for _, key := range []string{"r0", "r1", "r2"} {
+		assert.False(t, hasGCTestNode(t, c.database, key), key)
+	}
+	assert.EqualValues(t, 3, task.deleted)
+	assert.Equal(t, "gc done deleted=3", task.Detail())
+	assert.EqualValues(t, 3, sm.txIndex)
+	assert.EqualValues(t, 3, sm.evIndex)
+	assert.Equal(t, []string{"r3", "r4", "r0", "r1", "r2"}, sm.exportedResults())
+
+	// marks are removed after the cycle
+	_, err := os.Stat(task.markDir)
+	assert.True(t, os.IsNotExist(err))
+	assert.Nil(t, c.gcdb.marker)
+}
+
+func TestTaskGC_WriteBarrier(t *testing.T) {
+	c, _, sm := newGCTestChain(t, "r0", "r1", "r2")
+	defer os.RemoveAll(c.cfg.BaseDir)
+	setGCTestNode(t, c.database, "r0", "a", "b")
+	setGCTestNode(t, c.database, "a")
+	setGCTestNode(t, c.database, "b")
+	setGCTestNode(t, c.database, "r1")
+	setGCTestNode(t, c.database, "r2")
+
+	// a new block written during the cycle refers the old nodes
+	var once sync.Once
+	sm.onExport = func(result []byte, dst db.Database) {
+		once.Do(func() {
+			setGCTestNode(t, c.database, "a")
+			batch := c.database.NewBatch()
+			batch.Set(db.MerkleTrie, []byte("b"), []byte{})
+			assert.NoError(t, batch.Write())
+		})
+	}
+
+	runGCTest(t, c, 1)
+
+	assert.True(t, hasGCTestNode(t, c.database, "a"))
+	assert.True(t, hasGCTestNode(t, c.database, "b"))
+	assert.True(t, hasGCTestNode(t, c.database, "r2"))
+	assert.False(t, hasGCTestNode(t, c.database, "r0"))
+	assert.False(t, hasGCTestNode(t, c.database, "r1"))
+}
+
+func TestTaskGC_PauseResume(t *testing.T) {
+	c, _, sm := newGCTestChain(t, "r0", "r1", "r2")
+	defer os.RemoveAll(c.cfg.BaseDir)
+	setGCTestNode(t, c.database, "r0")
+	setGCTestNode(t, c.database, "r1")
+	setGCTestNode(t, c.database, "r2")
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	sm.onExport = func(result []byte, dst db.Database) {
+		once.Do(func() {
+			close(entered)
+			<-release
+		})
+	}
+
+	task := newTaskGC(c, 1)
+	assert.Error(t, task.Resume())
+	assert.NoError(t, task.Start())
+	<-entered
+	assert.NoError(t, task.Pause())
+	assert.Equal(t, "gc marking 0/1 paused", task.Detail())
+	close(release)
+
+	// it doesn't go further while it's paused
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, []string{"r2"}, sm.exportedResults())
+	assert.Equal(t, Started, task.State())
+
+	assert.NoError(t, task.Resume())
+	assert.Error(t, task.Resume())
+	assert.NoError(t, task.Wait())
+	assert.Equal(t, Finished, task.State())
+	assert.Error(t, task.Pause())
+	assert.False(t, hasGCTestNode(t, c.database, "r0"))
+	assert.False(t, hasGCTestNode(t, c.database, "r1"))
+}
+
+func TestTaskGC_StopWhilePaused(t *testing.T) {
+	c, _, sm := newGCTestChain(t, "r0", "r1", "r2")
+	defer os.RemoveAll(c.cfg.BaseDir)
+	setGCTestNode(t, c.database, "r0")
+	setGCTestNode(t, c.database, "r1")
+	setGCTestNode(t, c.database, "r2")
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	sm.onExport = func(result []byte, dst db.Database) {
+		once.Do(func() {
+			close(entered)
+			<-release
+		})
+	}
+
+	task := newTaskGC(c, 1)
+	assert.NoError(t, task.Start())
+	<-entered
+	assert.NoError(t, task.Pause())
+	close(release)
+	task.Stop()
+
+	err := task.Wait()
+	assert.True(t, errors.InterruptedError.Equals(err))
+	assert.Equal(t, Stopped, task.State())
+	assert.True(t, hasGCTestNode(t, c.database, "r0"))
+
+	// start height isn't changed, so the next cycle starts from genesis
+	height, err := newTaskGC(c, 1).startHeight()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, height)
+}
+
+func TestTaskGC_Restart(t *testing.T) {
+	c, bm, sm := newGCTestChain(t, "r0", "r1", "r2")
+	defer os.RemoveAll(c.cfg.BaseDir)
+	setGCTestNode(t, c.database, "r0", "a")
+	setGCTestNode(t, c.database, "a")
+	setGCTestNode(t, c.database, "r1", "b")
+	setGCTestNode(t, c.database, "b")
+	setGCTestNode(t, c.database, "r2", "b")
+
+	runGCTest(t, c, 1)
+	assert.False(t, hasGCTestNode(t, c.database, "r0"))
+	assert.False(t, hasGCTestNode(t, c.database, "a"))
+
+	height, err := newTaskGC(c, 1).startHeight()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, height)
+
+	// the next cycle walks the results from the saved start height, so
+	// results already pruned are not visited again.
+	addGCTestBlock(bm, "r3")
+	addGCTestBlock(bm, "r4")
+	setGCTestNode(t, c.database, "r3", "c")
+	setGCTestNode(t, c.database, "c")
+	setGCTestNode(t, c.database, "r4", "c")
+	sm.exported = nil
+
+	runGCTest(t, c, 1)
+	assert.Equal(t, []string{"r4", "r2", "r3"}, sm.exportedResults())
+	for _, key := range []string{"r1", "r2", "r3", "b"} {
+		assert.False(t, hasGCTestNode(t, c.database, key), key)
+	}
+	assert.True(t, hasGCTestNode(t, c.database, "r4"))
+	assert.True(t, hasGCTestNode(t, c.database, "c"))
+	assert.EqualValues(t, 4, sm.txIndex)
+	assert.EqualValues(t, 4, sm.evIndex)
+
+	// nothing to prune
+	sm.exported = nil
+	runGCTest(t, c, 1)
+	assert.Empty(t, sm.exportedResults())
+}
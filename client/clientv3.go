@@ -135,6 +135,28 @@ type Transaction struct {
 	TxIndex     jsonrpc.HexInt   `json:"txIndex" validate:"required,t_int"`
 }
 
+// TransactionsByAddress is the result of icx_getTransactionsByAddress.
+// Next is the cursor for the next page if there might be more transactions.
+type TransactionsByAddress struct {
+	StartHeight  jsonrpc.HexInt   `json:"startHeight"`
+	Transactions []*Transaction   `json:"transactions"`
+	Next         jsonrpc.HexBytes `json:"next,omitempty"`
+}
+
+// InternalTransactions is the result of icx_getInternalTransactions.
+type InternalTransactions struct {
+	TxHash               jsonrpc.HexBytes       `json:"txHash"`
+	BlockHeight          jsonrpc.HexInt         `json:"blockHeight"`
+	InternalTransactions []*InternalTransaction `json:"internalTransactions"`
+}
+
+type InternalTransaction struct {
+	From  jsonrpc.Address `json:"from"`
+	To    jsonrpc.Address `json:"to"`
+	Value jsonrpc.HexInt  `json:"value"`
+	Depth jsonrpc.HexInt  `json:"depth"`
+}
+
 func (c *ClientV3) GetLastBlock() (*Block, error) {
 	blk := &Block{}
 	_, err := c.Do("icx_getLastBlock", nil, blk)
@@ -225,6 +247,24 @@ func (c *ClientV3) GetTransactionByHash(param *v3.TransactionHashParam) (*Transa
 	return t, nil
 }
 
+func (c *ClientV3) GetTransactionsByAddress(param *v3.TransactionsByAddressParam) (*TransactionsByAddress, error) {
+	result := &TransactionsByAddress{}
+	_, err := c.Do("icx_getTransactionsByAddress", param, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *ClientV3) GetInternalTransactions(param *v3.TransactionHashParam) (*InternalTransactions, error) {
+	result := &InternalTransactions{}
+	_, err := c.Do("icx_getInternalTransactions", param, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 var txSerializeExcludes = map[string]bool{"signature": true}
 
 func (c *ClientV3) SendTransaction(w module.Wallet, param *v3.TransactionParam) (*jsonrpc.HexBytes, error) {
@@ -300,6 +340,24 @@ func (c *ClientV3) GetProofForEvents(param *v3.ProofEventsParam) ([][][]byte, er
 	return result, nil
 }
 
+func (c *ClientV3) GetProofForAccount(param *v3.ProofAccountParam) (*AccountProof, error) {
+	result := &AccountProof{}
+	_, err := c.Do("icx_getProofForAccount", param, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *ClientV3) GetProofForStorage(param *v3.ProofStorageParam) (*StorageProof, error) {
+	result := &StorageProof{}
+	_, err := c.Do("icx_getProofForStorage", param, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *ClientV3) MonitorBlock(param *server.BlockRequest, cb func(v *server.BlockNotification), cancelCh <-chan bool) error {
 	resp := &server.BlockNotification{}
 	return c.Monitor("/block", param, resp, func(v interface{}) {
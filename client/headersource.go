@@ -0,0 +1,37 @@
+package client
+
+import (
+	"encoding/hex"
+
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+)
+
+// HeaderSource provides headers, votes and data of the blocks with the
+// client. It implements lightclient.Source.
+type HeaderSource struct {
+	c *ClientV3
+}
+
+func NewHeaderSource(c *ClientV3) *HeaderSource {
+	return &HeaderSource{c: c}
+}
+
+func (s *HeaderSource) GetBlockHeaderByHeight(height int64) ([]byte, error) {
+	return s.c.GetBlockHeaderByHeight(&v3.BlockHeightParam{
+		Height: jsonrpc.HexInt(intconv.FormatInt(height)),
+	})
+}
+
+func (s *HeaderSource) GetVotesByHeight(height int64) ([]byte, error) {
+	return s.c.GetVotesByHeight(&v3.BlockHeightParam{
+		Height: jsonrpc.HexInt(intconv.FormatInt(height)),
+	})
+}
+
+func (s *HeaderSource) GetDataByHash(hash []byte) ([]byte, error) {
+	return s.c.GetDataByHash(&v3.DataHashParam{
+		Hash: jsonrpc.HexBytes("0x" + hex.EncodeToString(hash)),
+	})
+}
@@ -0,0 +1,271 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package lightclient verifies headers of blocks with commit votes, and
+// verifies receipts, events and states of the verified blocks with the
+// proofs. It doesn't need the database of the chain, so it can be used
+// by bridges and light clients.
+package lightclient
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/client"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transitionresult"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+// entry is a verified header with the validators of the next block.
+type entry struct {
+	header     *block.Header
+	validators module.ValidatorList
+}
+
+// LightClient keeps headers verified from the trusted header. The header of
+// the next block is verified with the commit votes signed by validators of
+// the next block, which is NextValidatorsHash of the last verified header.
+type LightClient struct {
+	lock    sync.Mutex
+	entries map[int64]*entry
+	last    *entry
+}
+
+// New returns a light client trusting the header and the validators of the
+// next block. validators should be the data of NextValidatorsHash of the
+// header.
+func New(header []byte, validators []byte) (*LightClient, error) {
+	h, err := block.NewHeaderFromBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	vl, err := validatorsFromBytes(h.NextValidatorsHash, validators)
+	if err != nil {
+		return nil, err
+	}
+	e := &entry{header: h, validators: vl}
+	return &LightClient{
+		entries: map[int64]*entry{h.Height: e},
+		last:    e,
+	}, nil
+}
+
+func validatorsFromBytes(h []byte, bs []byte) (module.ValidatorList, error) {
+	if len(h) == 0 {
+		return nil, errors.InvalidStateError.New("NoNextValidators")
+	}
+	if !bytes.Equal(crypto.SHA3Sum256(bs), h) {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidValidators(hash=%#x)", h)
+	}
+	database := db.NewMapDB()
+	bk, err := database.GetBucket(db.BytesByHash)
+	if err != nil {
+		return nil, err
+	}
+	if err := bk.Set(h, bs); err != nil {
+		return nil, err
+	}
+	return state.ValidatorSnapshotFromHash(database, h)
+}
+
+// Height returns the height of the last verified header.
+func (c *LightClient) Height() int64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.last.header.Height
+}
+
+// NextValidatorsHash returns hash of validators for the next block of the
+// last verified header. Update requires the data of the hash if the next
+// header has the different one.
+func (c *LightClient) NextValidatorsHash() []byte {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.last.header.NextValidatorsHash
+}
+
+func (c *LightClient) entryOf(height int64) (*entry, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if e, ok := c.entries[height]; ok {
+		return e, nil
+	}
+	return nil, errors.NotFoundError.Errorf("NotVerified(height=%d)", height)
+}
+
+// Header returns the verified header of the height.
+func (c *LightClient) Header(height int64) (*block.Header, error) {
+	e, err := c.entryOf(height)
+	if err != nil {
+		return nil, err
+	}
+	return e.header, nil
+}
+
+// NextValidators returns the validators of the next block of the verified
+// header of the height.
+func (c *LightClient) NextValidators(height int64) (module.ValidatorList, error) {
+	e, err := c.entryOf(height)
+	if err != nil {
+		return nil, err
+	}
+	return e.validators, nil
+}
+
+// Update verifies the header of the next block with its commit votes, and
+// returns the header. validators is the data of NextValidatorsHash of the
+// header. It can be nil if the validators are not changed.
+func (c *LightClient) Update(header, votes, validators []byte) (*block.Header, error) {
+	h, err := block.NewHeaderFromBytes(header)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	last := c.last
+	if h.Height != last.header.Height+1 {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidHeight(exp=%d,real=%d)", last.header.Height+1, h.Height)
+	}
+	if !bytes.Equal(h.PrevID, last.header.ID()) {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidPrevID(exp=%#x,real=%#x)", last.header.ID(), h.PrevID)
+	}
+	if err := consensus.VerifyCommitVotes(votes, h.Height, h.ID(), last.validators); err != nil {
+		return nil, errors.IllegalArgumentError.Wrapf(err,
+			"InvalidVotes(height=%d)", h.Height)
+	}
+
+	vl := last.validators
+	if !bytes.Equal(h.NextValidatorsHash, last.header.NextValidatorsHash) {
+		if vl, err = validatorsFromBytes(h.NextValidatorsHash, validators); err != nil {
+			return nil, err
+		}
+	}
+	e := &entry{header: h, validators: vl}
+	c.entries[h.Height] = e
+	c.last = e
+	return h, nil
+}
+
+// prove calls the function for proving the value. Proving with the invalid
+// proof may panic, so it returns the error for it.
+func prove(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.IllegalArgumentError.Errorf("InvalidProof(err=%v)", r)
+		}
+	}()
+	return f()
+}
+
+// VerifyReceipt verifies the proof of the receipt at the index in the normal
+// transactions of the block at the height, and returns the receipt.
+// The proof can be retrieved by icx_getProofForResult. Note that the
+// receipts of the block are in the result of the next block.
+func (c *LightClient) VerifyReceipt(height int64, idx int, proof [][]byte) (module.Receipt, error) {
+	return c.verifyReceipt(db.NewMapDB(), height, idx, proof)
+}
+
+func (c *LightClient) verifyReceipt(database db.Database, height int64, idx int, proof [][]byte) (module.Receipt, error) {
+	e, err := c.entryOf(height)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := transitionresult.ReceiptHashOf(e.header.Result, module.TransactionGroupNormal)
+	if err != nil {
+		return nil, err
+	}
+	if len(hash) == 0 || len(proof) == 0 {
+		return nil, errors.NotFoundError.Errorf(
+			"NoReceipts(height=%d)", height)
+	}
+	var rct module.Receipt
+	err = prove(func() (err error) {
+		rct, err = txresult.ProveReceipt(database, hash, idx, proof)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rct, nil
+}
+
+// VerifyEvent verifies the proof of the receipt and the proof of the event
+// in the receipt, and returns the event. The proofs can be retrieved by
+// icx_getProofForEvents.
+func (c *LightClient) VerifyEvent(height int64, idx int, proof [][]byte, eventIdx int, eventProof [][]byte) (module.EventLog, error) {
+	database := db.NewMapDB()
+	rct, err := c.verifyReceipt(database, height, idx, proof)
+	if err != nil {
+		return nil, err
+	}
+	if len(eventProof) == 0 {
+		return nil, errors.IllegalArgumentError.New("EmptyProof")
+	}
+	var ev module.EventLog
+	err = prove(func() (err error) {
+		ev, err = txresult.ProveEvent(rct, eventIdx, eventProof)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+func (c *LightClient) stateHashOf(height int64) ([]byte, error) {
+	e, err := c.entryOf(height)
+	if err != nil {
+		return nil, err
+	}
+	return transitionresult.StateHashOf(e.header.Result)
+}
+
+// VerifyAccount verifies the proof of the account in the world state, and
+// returns the account. The proof can be retrieved by icx_getProofForAccount.
+// Note that the world state of the block is in the result of the next block.
+func (c *LightClient) VerifyAccount(height int64, addr module.Address, proof [][]byte) (*client.Account, error) {
+	stateHash, err := c.stateHashOf(height)
+	if err != nil {
+		return nil, err
+	}
+	return client.VerifyAccountProof(stateHash, addr, proof)
+}
+
+// VerifyStorage verifies the proofs of the account and the value in the
+// storage of the account, and returns the value. The proofs can be
+// retrieved by icx_getProofForStorage.
+func (c *LightClient) VerifyStorage(height int64, addr module.Address, accountProof [][]byte, key []byte, proof [][]byte) ([]byte, error) {
+	account, err := c.VerifyAccount(height, addr, accountProof)
+	if err != nil {
+		return nil, err
+	}
+	return client.VerifyStorageProof(account.StorageHash, key, proof)
+}
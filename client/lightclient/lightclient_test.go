@@ -0,0 +1,233 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lightclient
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transitionresult"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+// testHeader has the same layout as the header of the block version 2.
+type testHeader struct {
+	Version                int
+	Height                 int64
+	Timestamp              int64
+	Proposer               []byte
+	PrevID                 []byte
+	VotesHash              []byte
+	NextValidatorsHash     []byte
+	PatchTransactionsHash  []byte
+	NormalTransactionsHash []byte
+	LogsBloom              []byte
+	Result                 []byte
+}
+
+func (h *testHeader) bytes() []byte {
+	return codec.BC.MustMarshalToBytes(h)
+}
+
+// testVote has the same layout as the vote signed by validators.
+type testVote struct {
+	Height         int64
+	Round          int32
+	Type           byte
+	BlockID        []byte
+	BlockPartSetID *consensus.PartSetID
+	Timestamp      int64
+}
+
+type testCommitVoteItem struct {
+	Timestamp int64
+	Signature common.Signature
+}
+
+type testCommitVoteList struct {
+	Round          int32
+	BlockPartSetID *consensus.PartSetID
+	Items          []testCommitVoteItem
+}
+
+const testVoteTypePrecommit = 1
+
+func votesOf(t *testing.T, height int64, id []byte, wallets ...module.Wallet) []byte {
+	psid := &consensus.PartSetID{Count: 1, Hash: crypto.SHA3Sum256(id)}
+	vl := &testCommitVoteList{BlockPartSetID: psid}
+	for i, w := range wallets {
+		v := &testVote{
+			Height:         height,
+			Type:           testVoteTypePrecommit,
+			BlockID:        id,
+			BlockPartSetID: psid,
+			Timestamp:      int64(i + 1),
+		}
+		sig, err := w.Sign(crypto.SHA3Sum256(codec.BC.MustMarshalToBytes(v)))
+		assert.NoError(t, err)
+		s, err := crypto.ParseSignature(sig)
+		assert.NoError(t, err)
+		vl.Items = append(vl.Items, testCommitVoteItem{
+			Timestamp: v.Timestamp,
+			Signature: common.Signature{Signature: s},
+		})
+	}
+	return codec.BC.MustMarshalToBytes(vl)
+}
+
+// validatorsOf returns the hash and the data of the validators.
+func validatorsOf(t *testing.T, wallets ...module.Wallet) ([]byte, []byte) {
+	var vs []module.Validator
+	for _, w := range wallets {
+		v, err := state.ValidatorFromAddress(w.Address())
+		assert.NoError(t, err)
+		vs = append(vs, v)
+	}
+	vss, err := state.ValidatorSnapshotFromSlice(db.NewMapDB(), vs)
+	assert.NoError(t, err)
+	return vss.Hash(), vss.Bytes()
+}
+
+func newWallets(n int) []module.Wallet {
+	ws := make([]module.Wallet, n)
+	for i := range ws {
+		ws[i] = wallet.New()
+	}
+	return ws
+}
+
+func TestLightClient_Update(t *testing.T) {
+	ws1 := newWallets(4)
+	ws2 := newWallets(3)
+	vh1, vbs1 := validatorsOf(t, ws1...)
+	vh2, vbs2 := validatorsOf(t, ws2...)
+
+	h10 := &testHeader{Version: module.BlockVersion2, Height: 10, NextValidatorsHash: vh1}
+	h10bs := h10.bytes()
+	_, err := New(h10bs, vbs2)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	c, err := New(h10bs, vbs1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 10, c.Height())
+	assert.Equal(t, vh1, c.NextValidatorsHash())
+
+	// the ID is the hash of the header bytes
+	hdr, err := c.Header(10)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.SHA3Sum256(h10bs), hdr.ID())
+	_, err = New(append(h10bs, 0), vbs1)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	// the next validators are changed at the height 11
+	h11 := &testHeader{
+		Version:            module.BlockVersion2,
+		Height:             11,
+		PrevID:             crypto.SHA3Sum256(h10bs),
+		NextValidatorsHash: vh2,
+	}
+	h11bs := h11.bytes()
+	id11 := crypto.SHA3Sum256(h11bs)
+
+	// wrong height, wrong previous ID and not enough votes
+	h12 := &testHeader{Version: module.BlockVersion2, Height: 12, PrevID: id11, NextValidatorsHash: vh2}
+	_, err = c.Update(h12.bytes(), votesOf(t, 12, crypto.SHA3Sum256(h12.bytes()), ws1...), nil)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	bad := *h11
+	bad.PrevID = id11
+	_, err = c.Update(bad.bytes(), votesOf(t, 11, crypto.SHA3Sum256(bad.bytes()), ws1...), vbs2)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	_, err = c.Update(h11bs, votesOf(t, 11, id11, ws1[:2]...), vbs2)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	_, err = c.Update(h11bs, votesOf(t, 11, id11, ws2...), vbs2)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	// the data of the new validators is required
+	_, err = c.Update(h11bs, votesOf(t, 11, id11, ws1[:3]...), nil)
+	assert.Error(t, err)
+	hdr, err = c.Update(h11bs, votesOf(t, 11, id11, ws1[:3]...), vbs2)
+	assert.NoError(t, err)
+	assert.Equal(t, id11, hdr.ID())
+	assert.EqualValues(t, 11, c.Height())
+	assert.Equal(t, vh2, c.NextValidatorsHash())
+	vl, err := c.NextValidators(11)
+	assert.NoError(t, err)
+	assert.Equal(t, len(ws2), vl.Len())
+
+	// the height 12 is signed by the new validators
+	id12 := crypto.SHA3Sum256(h12.bytes())
+	_, err = c.Update(h12.bytes(), votesOf(t, 12, id12, ws1...), nil)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	_, err = c.Update(h12.bytes(), votesOf(t, 12, id12, ws2...), nil)
+	assert.NoError(t, err)
+	vl, err = c.NextValidators(12)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, vl.IndexOf(ws2[0].Address()))
+
+	_, err = c.Header(13)
+	assert.True(t, errors.NotFoundError.Equals(err))
+}
+
+func TestLightClient_Verify(t *testing.T) {
+	ws := newWallets(1)
+	vh, vbs := validatorsOf(t, ws...)
+
+	database := db.NewMapDB()
+	addr := common.NewAddressFromString("cx0000000000000000000000000000000000000001")
+	var rs []txresult.Receipt
+	for i := 0; i < 3; i++ {
+		r := txresult.NewReceipt(database, module.Revision7, addr)
+		r.AddLog(addr, [][]byte{[]byte("Event(int)")}, [][]byte{{byte(i)}})
+		r.SetResult(module.StatusSuccess, big.NewInt(100), big.NewInt(10), nil)
+		rs = append(rs, r)
+	}
+	rl := txresult.NewReceiptListFromSlice(database, rs)
+	assert.NoError(t, rl.Flush())
+	proof, err := rl.GetProof(1)
+	assert.NoError(t, err)
+
+	result := &transitionresult.Result{NormalReceiptHash: rl.Hash()}
+	h1 := &testHeader{Version: module.BlockVersion2, Height: 1, NextValidatorsHash: vh, Result: result.Bytes()}
+	c, err := New(h1.bytes(), vbs)
+	assert.NoError(t, err)
+
+	rct, err := c.VerifyReceipt(1, 1, proof)
+	assert.NoError(t, err)
+	assert.Equal(t, rs[1].Bytes(), rct.Bytes())
+	ev, err := c.VerifyEvent(1, 1, proof, 0, [][]byte{})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	assert.Nil(t, ev)
+
+	_, err = c.VerifyReceipt(1, 2, proof)
+	assert.Error(t, err)
+	_, err = c.VerifyReceipt(2, 1, proof)
+	assert.True(t, errors.NotFoundError.Equals(err))
+	_, err = c.VerifyAccount(2, addr, nil)
+	assert.True(t, errors.NotFoundError.Equals(err))
+	_, err = c.VerifyStorage(2, addr, nil, []byte("key"), nil)
+	assert.True(t, errors.NotFoundError.Equals(err))
+}
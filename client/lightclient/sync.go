@@ -0,0 +1,79 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lightclient
+
+import (
+	"bytes"
+
+	"github.com/icon-project/goloop/block"
+)
+
+// Source provides the data of the blocks for syncing. client.HeaderSource
+// implements it with the JSON-RPC API of the node.
+type Source interface {
+	GetBlockHeaderByHeight(height int64) ([]byte, error)
+	GetVotesByHeight(height int64) ([]byte, error)
+	GetDataByHash(hash []byte) ([]byte, error)
+}
+
+// NewFromSource returns a light client trusting the header of the height
+// returned by the source. The header should be checked by other ways, for
+// example by comparing the ID with the known one.
+func NewFromSource(src Source, height int64) (*LightClient, error) {
+	header, err := src.GetBlockHeaderByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	h, err := block.NewHeaderFromBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	validators, err := src.GetDataByHash(h.NextValidatorsHash)
+	if err != nil {
+		return nil, err
+	}
+	return New(header, validators)
+}
+
+// Sync verifies headers from the source until the height.
+func (c *LightClient) Sync(src Source, height int64) error {
+	for h := c.Height() + 1; h <= height; h++ {
+		header, err := src.GetBlockHeaderByHeight(h)
+		if err != nil {
+			return err
+		}
+		votes, err := src.GetVotesByHeight(h)
+		if err != nil {
+			return err
+		}
+		hdr, err := block.NewHeaderFromBytes(header)
+		if err != nil {
+			return err
+		}
+		var validators []byte
+		if !bytes.Equal(hdr.NextValidatorsHash, c.NextValidatorsHash()) {
+			validators, err = src.GetDataByHash(hdr.NextValidatorsHash)
+			if err != nil {
+				return err
+			}
+		}
+		if _, err := c.Update(header, votes, validators); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/trie/ompt"
+	"github.com/icon-project/goloop/module"
+)
+
+// Account is the part of the account in the world state, which can be
+// verified with the proof of the account.
+type Account struct {
+	Version     int
+	Balance     *big.Int
+	IsContract  bool
+	StorageHash []byte
+}
+
+func (a *Account) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := d.DecodeList()
+	if err != nil {
+		return err
+	}
+	var balance *common.HexInt
+	if _, err := d2.DecodeMulti(
+		&a.Version,
+		&balance,
+		&a.IsContract,
+		&a.StorageHash,
+	); err != nil {
+		return errors.Wrap(err, "Fail to decode account")
+	}
+	if balance != nil {
+		a.Balance = new(big.Int).Set(&balance.Int)
+	} else {
+		a.Balance = new(big.Int)
+	}
+	return nil
+}
+
+// AccountProof is the result of icx_getProofForAccount.
+type AccountProof struct {
+	Height    common.HexInt64   `json:"height"`
+	BlockHash common.HexBytes   `json:"blockHash"`
+	StateHash common.HexBytes   `json:"stateHash"`
+	Address   common.Address    `json:"address"`
+	Account   common.HexBytes   `json:"account"`
+	Proof     []common.HexBytes `json:"proof"`
+}
+
+// Verify verifies the proof with the state hash in the result, and returns
+// the account. Callers should check that the state hash belongs to the
+// block trusted by them.
+func (p *AccountProof) Verify() (*Account, error) {
+	return verifyAccount(p.StateHash, &p.Address, p.Account, p.Proof)
+}
+
+// StorageProof is the result of icx_getProofForStorage.
+type StorageProof struct {
+	Height       common.HexInt64   `json:"height"`
+	BlockHash    common.HexBytes   `json:"blockHash"`
+	StateHash    common.HexBytes   `json:"stateHash"`
+	Address      common.Address    `json:"address"`
+	Account      common.HexBytes   `json:"account"`
+	AccountProof []common.HexBytes `json:"accountProof"`
+	Key          common.HexBytes   `json:"key"`
+	Value        common.HexBytes   `json:"value"`
+	Proof        []common.HexBytes `json:"proof"`
+}
+
+// Verify verifies the proofs of the account and the value with the state
+// hash in the result, and returns the value. Callers should check that
+// the state hash belongs to the block trusted by them.
+func (p *StorageProof) Verify() ([]byte, error) {
+	account, err := verifyAccount(p.StateHash, &p.Address, p.Account, p.AccountProof)
+	if err != nil {
+		return nil, err
+	}
+	value, err := VerifyStorageProof(account.StorageHash, p.Key, toBytesList(p.Proof))
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(value, p.Value) {
+		return nil, errors.InvalidStateError.Errorf(
+			"ValueMismatch(exp=%#x,real=%#x)", value, []byte(p.Value))
+	}
+	return value, nil
+}
+
+func toBytesList(l []common.HexBytes) [][]byte {
+	res := make([][]byte, len(l))
+	for i, v := range l {
+		res[i] = v
+	}
+	return res
+}
+
+func verifyAccount(stateHash []byte, addr module.Address, account []byte, proof []common.HexBytes) (*Account, error) {
+	value, err := proveValue(stateHash, crypto.SHA3Sum256(addr.ID()), toBytesList(proof))
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(value, account) {
+		return nil, errors.InvalidStateError.Errorf(
+			"AccountMismatch(exp=%#x,real=%#x)", value, account)
+	}
+	return decodeAccount(value)
+}
+
+func decodeAccount(bs []byte) (*Account, error) {
+	account := new(Account)
+	if _, err := codec.UnmarshalFromBytes(bs, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// VerifyAccountProof verifies the proof of the account of the address in
+// the world state of the state hash, and returns the account.
+func VerifyAccountProof(stateHash []byte, addr module.Address, proof [][]byte) (*Account, error) {
+	value, err := proveValue(stateHash, crypto.SHA3Sum256(addr.ID()), proof)
+	if err != nil {
+		return nil, err
+	}
+	return decodeAccount(value)
+}
+
+// VerifyStorageProof verifies the proof of the key in the storage of the
+// account, and returns the value. storageHash is StorageHash of the account
+// verified by VerifyAccountProof.
+func VerifyStorageProof(storageHash []byte, key []byte, proof [][]byte) ([]byte, error) {
+	return proveValue(storageHash, key, proof)
+}
+
+func proveValue(root []byte, key []byte, proof [][]byte) (value []byte, err error) {
+	if len(root) == 0 || len(proof) == 0 {
+		return nil, errors.IllegalArgumentError.New("EmptyRootOrProof")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			value = nil
+			err = errors.IllegalArgumentError.Errorf("InvalidProof(err=%v)", r)
+		}
+	}()
+	value, err = ompt.NewMPTForBytes(db.NewMapDB(), root).Prove(key, proof)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidProof")
+	}
+	if value == nil {
+		return nil, errors.NotFoundError.Errorf("NoValueForKey(key=%#x)", key)
+	}
+	return value, nil
+}
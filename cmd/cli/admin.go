@@ -17,8 +17,11 @@ import (
 	"github.com/icon-project/goloop/chain"
 	"github.com/icon-project/goloop/chain/gs"
 	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/node"
+	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/eeproxy"
 )
 
 func AdminPersistentPreRunE(vc *viper.Viper, adminClient *node.UnixDomainSockHttpClient) func(cmd *cobra.Command, args []string) error {
@@ -100,6 +103,13 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 			param.NormalTxPoolSize, _ = fs.GetInt("normal_tx_pool")
 			param.PatchTxPoolSize, _ = fs.GetInt("patch_tx_pool")
 			param.MaxBlockTxBytes, _ = fs.GetInt("max_block_tx_bytes")
+			param.TxPoolOrder, _ = fs.GetString("tx_pool_order")
+			param.EventIndex, _ = fs.GetBool("event_index")
+			param.TxIndex, _ = fs.GetBool("tx_index")
+			param.InternalTx, _ = fs.GetBool("internal_tx")
+			param.StepDetails, _ = fs.GetBool("step_details")
+			param.ParallelCheck, _ = fs.GetBool("parallel_check")
+			param.QueryCacheSize, _ = fs.GetInt("query_cache_size")
 			param.NodeCache, _ = fs.GetString("node_cache")
 			param.Channel, _ = fs.GetString("channel")
 			param.SecureSuites, _ = fs.GetString("secure_suites")
@@ -157,6 +167,13 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.Int("normal_tx_pool", 0, "Size of normal transaction pool")
 	joinFlags.Int("patch_tx_pool", 0, "Size of patch transaction pool")
 	joinFlags.Int("max_block_tx_bytes", 0, "Max size of transactions in a block")
+	joinFlags.String("tx_pool_order", service.TxOrderDefault, "Ordering policy of normal transaction pool (fifo,priority)")
+	joinFlags.Bool("event_index", false, "Enable index of event logs for icx_getLogs")
+	joinFlags.Bool("tx_index", false, "Enable index of transactions by address for icx_getTransactionsByAddress")
+	joinFlags.Bool("internal_tx", false, "Enable recording of internal transactions for icx_getInternalTransactions")
+	joinFlags.Bool("step_details", false, "Enable recording of step usage details for icx_getTransactionResult")
+	joinFlags.Bool("parallel_check", false, "Compare results of parallel execution with sequential execution")
+	joinFlags.Int("query_cache_size", 0, "Memory budget of query result cache for icx_call in bytes (0: disable)")
 	joinFlags.String("node_cache", chain.NodeCacheDefault, "Node cache (none,small,large)")
 	joinFlags.String("channel", "", "Channel")
 	joinFlags.String("secure_suites", "none,tls,ecdhe",
@@ -317,9 +334,13 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 		Short: "Start to backup the channel",
 		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &node.ChainBackupParam{}
+			param.Base, _ = fs.GetString("base")
+
 			var v string
 			reqUrl := node.UrlChain + "/" + args[0] + "/backup"
-			_, err := adminClient.PostWithJson(reqUrl, nil, &v)
+			_, err := adminClient.PostWithJson(reqUrl, param, &v)
 			if err != nil {
 				return err
 			}
@@ -328,6 +349,78 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 		},
 	}
 	rootCmd.AddCommand(backupCmd)
+	backupFlags := backupCmd.Flags()
+	backupFlags.String("base", "", "Name of the base backup for incremental backup (only for the stopped chain)")
+
+	dbStatsCmd := &cobra.Command{
+		Use:   "dbstats CID",
+		Short: "Get statistics of the chain database",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := new(db.Statistics)
+			params := &url.Values{}
+			if keys, err := cmd.Flags().GetBool("keys"); keys && err == nil {
+				params.Add("keys", strconv.FormatBool(keys))
+			}
+			reqUrl := node.UrlChain + "/" + args[0] + "/dbstats"
+			resp, err := adminClient.Get(reqUrl, v, params)
+			if err != nil {
+				return err
+			}
+			if err = JsonPrettyPrintln(os.Stdout, v); err != nil {
+				return errors.Errorf("failed JsonIntend resp=%+v, err=%+v", resp, err)
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(dbStatsCmd)
+	dbStatsCmd.Flags().Bool("keys", false,
+		"Count keys by scanning all entries, which may take long for goleveldb and badger")
+
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "compact CID",
+		Short: "Compact the chain database",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE:  opFunc("compact"),
+	})
+
+	gcCmd := &cobra.Command{
+		Use:   "gc CID",
+		Short: "Start to prune old states while the chain is running",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &node.ChainGCParam{}
+			param.Keep, _ = fs.GetInt64("keep")
+
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/gc"
+			_, err := adminClient.PostWithJson(reqUrl, param, &v)
+			if err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(gcCmd)
+	gcFlags := gcCmd.Flags()
+	gcFlags.Int64("keep", 0, "Number of recent blocks to keep states")
+	MarkAnnotationRequired(gcFlags, "keep")
+
+	rootCmd.AddCommand(
+		&cobra.Command{
+			Use:   "gcpause CID",
+			Short: "Pause running state pruning",
+			Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+			RunE:  opFunc("gc/pause"),
+		},
+		&cobra.Command{
+			Use:   "gcresume CID",
+			Short: "Resume paused state pruning",
+			Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+			RunE:  opFunc("gc/resume"),
+		})
 
 	genesisCmd := &cobra.Command{
 		Use:   "genesis CID FILE",
@@ -462,6 +555,38 @@ func NewSystemCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comma
 	}
 	rootCmd.AddCommand(configCmd)
 
+	eeCmd := &cobra.Command{
+		Use:   "ee",
+		Short: "Get health of execution engines",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := cmd.Flag("format").Value.String()
+			var v interface{}
+			params := &url.Values{}
+			if format == "" {
+				v = new(eeproxy.Health)
+			} else {
+				v = new(string)
+				params.Add("format", format)
+			}
+			resp, err := adminClient.Get(node.UrlSystem+"/ee", v, params)
+			if err != nil {
+				return err
+			}
+			if format == "" {
+				if err = JsonPrettyPrintln(os.Stdout, v); err != nil {
+					return errors.Errorf("failed JsonIntend resp=%+v, err=%+v", resp, err)
+				}
+			} else {
+				s := v.(*string)
+				fmt.Println(*s)
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(eeCmd)
+	eeCmd.Flags().StringP("format", "f", "", "Format the output using the given Go template")
+
 	NewBackupCmd(rootCmd, &adminClient)
 	NewRestoreCmd(rootCmd, &adminClient)
 
@@ -162,7 +162,7 @@ func NewServerCmd(parentCmd *cobra.Command, parentVc *viper.Viper, version, buil
 	rootPFlags.String("log_forwarder_level", "info", "LogForwarder level")
 	rootPFlags.String("log_forwarder_name", "", "LogForwarder name")
 	rootPFlags.StringToString("log_forwarder_options", nil, "LogForwarder options, comma-separated 'key=value'")
-	rootPFlags.String("engines", "python", "Execution engines, comma-separated (python,java)")
+	rootPFlags.String("engines", "python", "Execution engines, comma-separated (python,java,go)")
 
 	rootPFlags.String("log_writer_filename", "", "Log filename (rotated files resides in same directory)")
 	rootPFlags.Int("log_writer_maxsize", 100, "Maximum log file size in MiB")
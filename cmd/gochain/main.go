@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -24,6 +25,7 @@ import (
 	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/server"
 	"github.com/icon-project/goloop/server/metric"
+	"github.com/icon-project/goloop/service"
 	"github.com/icon-project/goloop/service/eeproxy"
 )
 
@@ -33,14 +35,15 @@ const (
 
 type GoChainConfig struct {
 	chain.Config
-	P2PAddr       string `json:"p2p"`
-	P2PListenAddr string `json:"p2p_listen"`
-	EESocket      string `json:"ee_socket"`
-	RPCAddr       string `json:"rpc_addr"`
-	RPCDump       bool   `json:"rpc_dump"`
-	RPCDebug      bool   `json:"rpc_debug"`
-	EEInstances   int    `json:"ee_instances"`
-	Engines       string `json:"engines"`
+	P2PAddr         string `json:"p2p"`
+	P2PListenAddr   string `json:"p2p_listen"`
+	EESocket        string `json:"ee_socket"`
+	RPCAddr         string `json:"rpc_addr"`
+	RPCDump         bool   `json:"rpc_dump"`
+	RPCDebug        bool   `json:"rpc_debug"`
+	EEInstances     int    `json:"ee_instances"`
+	EEInvokeTimeout int    `json:"ee_invoke_timeout"`
+	Engines         string `json:"engines"`
 
 	Key          []byte          `json:"key,omitempty"`
 	KeyStoreData json.RawMessage `json:"key_store"`
@@ -125,10 +128,18 @@ func main() {
 	flag.StringVar(&memProfile, "memprofile", "", "Memory Profiling data file")
 	flag.StringVar(&chainDir, "chain_dir", "", "Chain data directory (default: .chain/<address>/<nid>)")
 	flag.IntVar(&cfg.EEInstances, "ee_instances", 1, "Number of execution engines")
+	flag.IntVar(&cfg.EEInvokeTimeout, "ee_invoke_timeout", 5000, "Deadline for the response of execution engines in milliseconds (0:no deadline)")
 	flag.IntVar(&cfg.ConcurrencyLevel, "concurrency", 1, "Maximum number of executors to be used for concurrency")
 	flag.IntVar(&cfg.NormalTxPoolSize, "normal_tx_pool", 0, "Normal transaction pool size")
 	flag.IntVar(&cfg.PatchTxPoolSize, "patch_tx_pool", 0, "Patch transaction pool size")
 	flag.IntVar(&cfg.MaxBlockTxBytes, "max_block_tx_bytes", 0, "Maximum size of transactions in a block")
+	flag.StringVar(&cfg.TxPoolOrder, "tx_pool_order", service.TxOrderDefault, "Ordering policy of normal transaction pool (fifo,priority)")
+	flag.BoolVar(&cfg.EventIndex, "event_index", false, "Enable index of event logs for icx_getLogs")
+	flag.BoolVar(&cfg.TxIndex, "tx_index", false, "Enable index of transactions by address for icx_getTransactionsByAddress")
+	flag.BoolVar(&cfg.InternalTx, "internal_tx", false, "Enable recording of internal transactions for icx_getInternalTransactions")
+	flag.BoolVar(&cfg.StepDetails, "step_details", false, "Enable recording of step usage details for icx_getTransactionResult")
+	flag.BoolVar(&cfg.ParallelCheck, "parallel_check", false, "Compare results of parallel execution with sequential execution")
+	flag.IntVar(&cfg.QueryCacheSize, "query_cache_size", 0, "Memory budget of query result cache for icx_call in bytes (0: disable)")
 	flag.StringVar(&cfg.NodeCache, "node_cache", chain.NodeCacheDefault, "Node cache (none,small,large)")
 	flag.StringVar(&cfg.LogLevel, "log_level", "debug", "Main log level")
 	flag.StringVar(&cfg.ConsoleLevel, "console_level", "trace", "Console log level")
@@ -140,7 +151,7 @@ func main() {
 	flag.StringToString("log_forwarder_options", nil, "LogForwarder options, comma-separated 'key=value'")
 	flag.Int64Var(&cfg.DefWaitTimeout, "default_wait_timeout", 0, "Default wait timeout in milli-second (0: disable)")
 	flag.Int64Var(&cfg.MaxWaitTimeout, "max_wait_timeout", 0, "Max wait timeout in milli-second (0: uses same value of default_wait_timeout)")
-	flag.StringVar(&cfg.Engines, "engines", "python", "Execution engines, comma-separated (python,java)")
+	flag.StringVar(&cfg.Engines, "engines", "python", "Execution engines, comma-separated (python,java,go)")
 	flag.StringVar(&lwCfg.Filename, "log_writer_filename", "", "Log filename")
 	flag.IntVar(&lwCfg.MaxSize, "log_writer_maxsize", 100, "Log file max size")
 	flag.IntVar(&lwCfg.MaxAge, "log_writer_maxage", 0, "Log file max age")
@@ -453,6 +464,7 @@ func Execute(cmd *cobra.Command, args []string) {
 	}
 	go pm.Loop()
 
+	pm.SetInvokeTimeout(time.Duration(cfg.EEInvokeTimeout) * time.Millisecond)
 	pm.SetInstances(cfg.EEInstances, cfg.EEInstances, cfg.EEInstances)
 
 	// TODO : server-chain setting
@@ -1,11 +1,18 @@
 package db
 
 import (
+	"bytes"
+	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/dgraph-io/badger"
 )
 
+// badgerGCDiscardRatio is the ratio of discardable entries for value log
+// files to be rewritten on compaction.
+const badgerGCDiscardRatio = 0.5
+
 func init() {
 	dbCreator := func(name string, dir string) (Database, error) {
 		return NewBadgerDB(name, dir)
@@ -28,7 +35,8 @@ func NewBadgerDB(name string, dir string) (*BadgerDB, error) {
 	}
 
 	database := &BadgerDB{
-		db: db,
+		db:  db,
+		dir: dbPath,
 	}
 
 	return database, nil
@@ -40,7 +48,8 @@ func NewBadgerDB(name string, dir string) (*BadgerDB, error) {
 var _ Database = (*BadgerDB)(nil)
 
 type BadgerDB struct {
-	db *badger.DB
+	db  *badger.DB
+	dir string
 }
 
 func (db *BadgerDB) GetBucket(id BucketID) (Bucket, error) {
@@ -50,6 +59,82 @@ func (db *BadgerDB) GetBucket(id BucketID) (Bucket, error) {
 	}, nil
 }
 
+func (db *BadgerDB) NewBatch() Batch {
+	return &badgerBatch{db: db.db}
+}
+
+func (db *BadgerDB) NewSnapshot() (Snapshot, error) {
+	return &badgerSnapshot{
+		txn: db.db.NewTransaction(false),
+	}, nil
+}
+
+// Stats returns approximate sizes of the buckets without scanning the
+// entries, so it doesn't count the keys (see CountKeys). Sizes of the buckets are the sizes
+// of LSM tables covering their key ranges, so they don't include values in
+// the value log, and a table covering multiple buckets is counted for each
+// of them.
+func (db *BadgerDB) Stats(ids []BucketID) (*Statistics, error) {
+	buckets, err := estimateBuckets(ids, db.sizeOf)
+	if err != nil {
+		return nil, err
+	}
+	lsm, vlog := db.db.Size()
+	return &Statistics{
+		Backend: BadgerDBBackend,
+		Size:    lsm + vlog,
+		Buckets: buckets,
+		Compaction: map[string]interface{}{
+			"lsmSize":  lsm,
+			"vlogSize": vlog,
+		},
+	}, nil
+}
+
+func (db *BadgerDB) sizeOf(ranges []keyRange) ([]int64, error) {
+	sizes := make([]int64, len(ranges))
+	for _, t := range db.db.Tables() {
+		fi, err := os.Stat(filepath.Join(db.dir, fmt.Sprintf("%06d.sst", t.ID)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				// removed by compaction
+				continue
+			}
+			return nil, err
+		}
+		left, right := badgerKeyWithoutTs(t.Left), badgerKeyWithoutTs(t.Right)
+		for i, r := range ranges {
+			if bytes.Compare(right, r.start) >= 0 &&
+				(r.limit == nil || bytes.Compare(left, r.limit) < 0) {
+				sizes[i] += fi.Size()
+			}
+		}
+	}
+	return sizes, nil
+}
+
+// badgerKeyWithoutTs returns the key without the version, which badger
+// appends to the keys in the tables.
+func badgerKeyWithoutTs(key []byte) []byte {
+	if len(key) < 8 {
+		return key
+	}
+	return key[:len(key)-8]
+}
+
+// Compact runs garbage collection of value log until there is no
+// more file to rewrite. LSM tree is compacted by badger itself.
+func (db *BadgerDB) Compact() error {
+	for {
+		if err := db.db.RunValueLogGC(badgerGCDiscardRatio); err != nil {
+			if err == badger.ErrNoRewrite {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
 func (db *BadgerDB) Close() error {
 	err := db.db.Close()
 	return err
@@ -103,3 +188,194 @@ func (bucket *badgerBucket) Delete(key []byte) error {
 		return txn.Delete(ikey)
 	})
 }
+
+func (bucket *badgerBucket) Iterator(prefix, start []byte) Iterator {
+	return newBadgerIterator(bucket.db.NewTransaction(false), true,
+		bucket.id, prefix, start)
+}
+
+//----------------------------------------
+// Snapshot
+
+var _ Snapshot = (*badgerSnapshot)(nil)
+
+// badgerSnapshot is a snapshot based on a read-only transaction.
+// All iterators of it should be released before releasing it.
+type badgerSnapshot struct {
+	txn *badger.Txn
+}
+
+func (s *badgerSnapshot) GetBucket(id BucketID) (Bucket, error) {
+	return &badgerSnapshotBucket{
+		id:  id,
+		txn: s.txn,
+	}, nil
+}
+
+func (s *badgerSnapshot) NewBatch() Batch {
+	return &readOnlyBatch{}
+}
+
+func (s *badgerSnapshot) Release() {
+	s.txn.Discard()
+}
+
+func (s *badgerSnapshot) Close() error {
+	s.Release()
+	return nil
+}
+
+type badgerSnapshotBucket struct {
+	id  BucketID
+	txn *badger.Txn
+}
+
+func (bucket *badgerSnapshotBucket) Get(key []byte) ([]byte, error) {
+	item, err := bucket.txn.Get(internalKey(bucket.id, key))
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return item.ValueCopy(nil)
+}
+
+func (bucket *badgerSnapshotBucket) Has(key []byte) bool {
+	value, err := bucket.Get(key)
+	return value != nil && err == nil
+}
+
+func (bucket *badgerSnapshotBucket) Set(key []byte, value []byte) error {
+	return errReadOnly
+}
+
+func (bucket *badgerSnapshotBucket) Delete(key []byte) error {
+	return errReadOnly
+}
+
+func (bucket *badgerSnapshotBucket) Iterator(prefix, start []byte) Iterator {
+	return newBadgerIterator(bucket.txn, false, bucket.id, prefix, start)
+}
+
+//----------------------------------------
+// Iterator
+
+type badgerIterator struct {
+	txn    *badger.Txn
+	owned  bool
+	iter   *badger.Iterator
+	id     BucketID
+	prefix []byte
+	seek   []byte
+	offset int
+
+	started bool
+	key     []byte
+	value   []byte
+	err     error
+}
+
+// newBadgerIterator returns an iterator on the transaction. If owned is true,
+// then the transaction is discarded on Release.
+func newBadgerIterator(txn *badger.Txn, owned bool, id BucketID, prefix, start []byte) Iterator {
+	return &badgerIterator{
+		txn:    txn,
+		owned:  owned,
+		iter:   txn.NewIterator(badger.DefaultIteratorOptions),
+		id:     id,
+		prefix: internalKey(id, prefix),
+		seek:   internalKey(id, seekKey(prefix, start)),
+		offset: len(id),
+	}
+}
+
+func (it *badgerIterator) Next() bool {
+	if it.err != nil || it.iter == nil {
+		return false
+	}
+	if !it.started {
+		it.started = true
+		it.iter.Seek(it.seek)
+	} else {
+		it.iter.Next()
+	}
+	for it.iter.ValidForPrefix(it.prefix) && !isKeyOf(it.id, it.iter.Item().Key()) {
+		it.iter.Next()
+	}
+	it.key, it.value = nil, nil
+	if !it.iter.ValidForPrefix(it.prefix) {
+		return false
+	}
+	item := it.iter.Item()
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.key = copyBytes(item.Key()[it.offset:])
+	it.value = value
+	return true
+}
+
+func (it *badgerIterator) Key() []byte {
+	return it.key
+}
+
+func (it *badgerIterator) Value() []byte {
+	return it.value
+}
+
+func (it *badgerIterator) Error() error {
+	return it.err
+}
+
+func (it *badgerIterator) Release() {
+	if it.iter != nil {
+		it.iter.Close()
+		if it.owned {
+			it.txn.Discard()
+		}
+		it.iter = nil
+	}
+}
+
+//----------------------------------------
+// Batch
+
+type badgerBatch struct {
+	batchOps
+	db *badger.DB
+}
+
+// Write applies the writes in one transaction if they fit in it. Otherwise,
+// a transaction is committed whenever it gets too big, and the rest are
+// applied in the next one. So a batch larger than a transaction of badger
+// isn't applied atomically.
+func (b *badgerBatch) Write() error {
+	txn := b.db.NewTransaction(true)
+	defer func() {
+		txn.Discard()
+	}()
+	for _, op := range b.ops {
+		err := b.apply(txn, op)
+		if err == badger.ErrTxnTooBig {
+			if err := txn.Commit(nil); err != nil {
+				return err
+			}
+			txn = b.db.NewTransaction(true)
+			err = b.apply(txn, op)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return txn.Commit(nil)
+}
+
+func (b *badgerBatch) apply(txn *badger.Txn, op batchOp) error {
+	if op.delete {
+		return txn.Delete(internalKey(op.id, op.key))
+	}
+	return txn.Set(internalKey(op.id, op.key), op.value)
+}
@@ -1,6 +1,8 @@
 package db
 
 import (
+	"bytes"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -32,3 +34,28 @@ func TestBadgerDB_Database(t *testing.T) {
 	result, _ = bucket.Get(key)
 	assert.Nil(t, result, "empty")
 }
+
+func TestBadgerDB_LargeBatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "badgerdb")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	testDB, err := openDatabase(BadgerDBBackend, "test", dir)
+	assert.NoError(t, err)
+	defer testDB.Close()
+
+	// larger than a transaction of badger
+	const count = 12000
+	padding := bytes.Repeat([]byte{1}, 1024)
+	batch := testDB.NewBatch()
+	for i := 0; i < count; i++ {
+		key := append([]byte(fmt.Sprintf("key%05d", i)), padding...)
+		batch.Set(BytesByHash, key, []byte("value"))
+	}
+	assert.NoError(t, batch.Write())
+
+	bk, _ := testDB.GetBucket(BytesByHash)
+	assert.Len(t, collectEntries(t, bk, nil, nil), count)
+}
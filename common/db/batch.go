@@ -0,0 +1,96 @@
+package db
+
+// Batch is a set of writes to the buckets of a database.
+// Writes are applied atomically by Write, so either all of them
+// or none of them are stored. For badger, it's atomic only if the writes fit
+// in one transaction of it (see badgerBatch.Write).
+type Batch interface {
+	Set(id BucketID, key []byte, value []byte)
+	Delete(id BucketID, key []byte)
+
+	// Len returns number of writes in the batch.
+	Len() int
+
+	// Reset clears all writes in the batch.
+	Reset()
+
+	// Write applies the writes in the batch to the database.
+	// The batch can be reused after Reset.
+	Write() error
+}
+
+type batchOp struct {
+	id     BucketID
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batchOps is a recorded sequence of writes, which is used to implement
+// Batch for each backend.
+type batchOps struct {
+	ops []batchOp
+}
+
+func (b *batchOps) Set(id BucketID, key []byte, value []byte) {
+	b.ops = append(b.ops, batchOp{
+		id:    id,
+		key:   copyBytes(key),
+		value: nonNilBytes(copyBytes(value)),
+	})
+}
+
+func (b *batchOps) Delete(id BucketID, key []byte) {
+	b.ops = append(b.ops, batchOp{
+		id:     id,
+		key:    copyBytes(key),
+		delete: true,
+	})
+}
+
+func (b *batchOps) Len() int {
+	return len(b.ops)
+}
+
+func (b *batchOps) Reset() {
+	b.ops = nil
+}
+
+// bucketBatch applies writes through the buckets of the database.
+// It's used for the databases which can't apply writes atomically by
+// themselves, like proxies or in-memory layers.
+type bucketBatch struct {
+	batchOps
+	database Database
+}
+
+func (b *bucketBatch) Write() error {
+	buckets := make(map[BucketID]Bucket)
+	for _, op := range b.ops {
+		bk, ok := buckets[op.id]
+		if !ok {
+			var err error
+			if bk, err = b.database.GetBucket(op.id); err != nil {
+				return err
+			}
+			buckets[op.id] = bk
+		}
+		if op.delete {
+			if err := bk.Delete(op.key); err != nil {
+				return err
+			}
+		} else {
+			if err := bk.Set(op.key, op.value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NewBucketBatch returns a batch which applies writes through the buckets
+// of the database one by one. It doesn't guarantee atomicity, so it should be
+// used only for the databases without native batch support.
+func NewBucketBatch(database Database) Batch {
+	return &bucketBatch{database: database}
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"bytes"
 	"path/filepath"
 
 	bolt "go.etcd.io/bbolt"
@@ -44,6 +45,47 @@ func (db *BoltDB) GetBucket(id BucketID) (Bucket, error) {
 	return &boltBucket{db: db.db, id: bid}, err
 }
 
+func (db *BoltDB) NewBatch() Batch {
+	return &boltBatch{db: db.db}
+}
+
+func (db *BoltDB) Stats(ids []BucketID) (*Statistics, error) {
+	stats := &Statistics{
+		Backend:     BoltDBBackend,
+		KeysCounted: true,
+		Buckets:     make([]BucketStatistics, 0, len(ids)),
+	}
+	err := db.db.View(func(tx *bolt.Tx) error {
+		stats.Size = tx.Size()
+		for _, id := range ids {
+			bs := BucketStatistics{ID: id}
+			if bk := tx.Bucket([]byte("B" + id)); bk != nil {
+				s := bk.Stats()
+				bs.Keys = int64(s.KeyN)
+				bs.Size = int64(s.LeafInuse)
+			}
+			stats.Buckets = append(stats.Buckets, bs)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s := db.db.Stats()
+	stats.Compaction = map[string]interface{}{
+		"freePages":    s.FreePageN,
+		"pendingPages": s.PendingPageN,
+		"freeAlloc":    s.FreeAlloc,
+	}
+	return stats, nil
+}
+
+// Compact is not supported by boltdb. Free pages are reused by boltdb, but
+// the file doesn't shrink.
+func (db *BoltDB) Compact() error {
+	return ErrCompactionUnsupported
+}
+
 func (db *BoltDB) Close() error {
 	err := db.db.Close()
 	return err
@@ -96,3 +138,102 @@ func (bucket *boltBucket) Delete(key []byte) error {
 	})
 	return err
 }
+
+// Iterator returns an iterator holding a read transaction until it's
+// released. Release it before writing in the same goroutine.
+func (bucket *boltBucket) Iterator(prefix, start []byte) Iterator {
+	tx, err := bucket.db.Begin(false)
+	if err != nil {
+		return NewErrorIterator(err)
+	}
+	return &boltIterator{
+		tx:     tx,
+		cursor: tx.Bucket(bucket.id).Cursor(),
+		prefix: copyBytes(prefix),
+		seek:   copyBytes(seekKey(prefix, start)),
+	}
+}
+
+//----------------------------------------
+// Iterator
+
+type boltIterator struct {
+	tx     *bolt.Tx
+	cursor *bolt.Cursor
+	prefix []byte
+	seek   []byte
+
+	started bool
+	key     []byte
+	value   []byte
+}
+
+func (it *boltIterator) Next() bool {
+	if it.tx == nil {
+		return false
+	}
+	var k, v []byte
+	if !it.started {
+		it.started = true
+		k, v = it.cursor.Seek(it.seek)
+	} else {
+		k, v = it.cursor.Next()
+	}
+	// skip nested buckets
+	for k != nil && v == nil {
+		k, v = it.cursor.Next()
+	}
+	if k == nil || !bytes.HasPrefix(k, it.prefix) {
+		it.key, it.value = nil, nil
+		return false
+	}
+	it.key, it.value = copyBytes(k), copyBytes(v)
+	return true
+}
+
+func (it *boltIterator) Key() []byte {
+	return it.key
+}
+
+func (it *boltIterator) Value() []byte {
+	return it.value
+}
+
+func (it *boltIterator) Error() error {
+	return nil
+}
+
+func (it *boltIterator) Release() {
+	if it.tx != nil {
+		it.tx.Rollback()
+		it.tx = nil
+	}
+}
+
+//----------------------------------------
+// Batch
+
+type boltBatch struct {
+	batchOps
+	db *bolt.DB
+}
+
+func (b *boltBatch) Write() error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		for _, op := range b.ops {
+			bk, err := tx.CreateBucketIfNotExists([]byte("B" + op.id))
+			if err != nil {
+				return err
+			}
+			if op.delete {
+				err = bk.Delete(op.key)
+			} else {
+				err = bk.Put(op.key, op.value)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
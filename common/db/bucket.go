@@ -6,6 +6,11 @@ type Bucket interface {
 	Has(key []byte) bool
 	Set(key []byte, value []byte) error
 	Delete(key []byte) error
+
+	// Iterator returns an iterator over the entries whose keys have the
+	// prefix, in ascending order of keys starting from start.
+	// If start is nil or less than prefix, then it starts from the prefix.
+	Iterator(prefix, start []byte) Iterator
 }
 
 type BucketID string
@@ -13,6 +18,7 @@ type BucketID string
 //	Bucket ID
 const (
 	// MerkleTrie maps RLP encoded data from sha3(data)
+	// Keys of this bucket should be 32 bytes (see hashKeySize).
 	MerkleTrie BucketID = ""
 
 	// BytesByHash maps data except merkle trie nodes from sha3(data)
@@ -32,8 +38,40 @@ const (
 
 	// ChainProperty is general key value map for chain property.
 	ChainProperty BucketID = "C"
+
+	// EventLogIndex maps locations of event logs from the terms of them.
+	// It's written only if the index is enabled.
+	EventLogIndex BucketID = "L"
+
+	// TransactionLocatorByAddress maps hashes of transactions from the
+	// addresses sending or receiving them with the locations of them.
+	// It's written only if the index is enabled.
+	TransactionLocatorByAddress BucketID = "A"
+
+	// InternalTransactionsByHash maps internal transactions from the hash
+	// of the transaction. It's written only if the recording is enabled.
+	InternalTransactionsByHash BucketID = "I"
+
+	// StepUsedDetailsByHash maps details of the used steps from the hash of
+	// the transaction. It's written only if the recording is enabled.
+	StepUsedDetailsByHash BucketID = "D"
 )
 
+// AllBucketIDs is the list of bucket IDs used by the chain.
+var AllBucketIDs = []BucketID{
+	MerkleTrie,
+	BytesByHash,
+	TransactionLocatorByHash,
+	BlockHeaderHashByHeight,
+	BlockV1ByHash,
+	ReceiptV1ByHash,
+	ChainProperty,
+	EventLogIndex,
+	TransactionLocatorByAddress,
+	InternalTransactionsByHash,
+	StepUsedDetailsByHash,
+}
+
 // internalKey returns key prefixed with the bucket's id.
 func internalKey(id BucketID, key []byte) []byte {
 	buf := make([]byte, len(key)+len(id))
@@ -6,6 +6,9 @@ import (
 
 type Database interface {
 	GetBucket(id BucketID) (Bucket, error)
+
+	// NewBatch returns a new batch for writes to the buckets of the database.
+	NewBatch() Batch
 	Close() error
 }
 
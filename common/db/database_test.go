@@ -0,0 +1,254 @@
+package db
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collectEntries(t *testing.T, bk Bucket, prefix, start []byte) []string {
+	var entries []string
+	iter := bk.Iterator(prefix, start)
+	defer iter.Release()
+	for iter.Next() {
+		entries = append(entries, string(iter.Key())+"="+string(iter.Value()))
+	}
+	assert.NoError(t, iter.Error())
+	return entries
+}
+
+func testIteratorAndBatch(t *testing.T, testDB Database) {
+	bk1, _ := testDB.GetBucket("hello")
+	bk2, _ := testDB.GetBucket("world")
+
+	batch := testDB.NewBatch()
+	batch.Set("hello", []byte("b2"), []byte("v4"))
+	batch.Set("hello", []byte("a1"), []byte("v1"))
+	batch.Set("hello", []byte("a3"), []byte("v3"))
+	batch.Set("hello", []byte("a2"), []byte("v2"))
+	batch.Set("world", []byte("a1"), []byte("w1"))
+	assert.Equal(t, 5, batch.Len())
+
+	// nothing is written before Write()
+	assert.False(t, bk1.Has([]byte("a1")))
+	assert.Empty(t, collectEntries(t, bk1, nil, nil))
+
+	assert.NoError(t, batch.Write())
+	assert.Equal(t, []string{"a1=v1", "a2=v2", "a3=v3", "b2=v4"},
+		collectEntries(t, bk1, nil, nil))
+	assert.Equal(t, []string{"a1=v1", "a2=v2", "a3=v3"},
+		collectEntries(t, bk1, []byte("a"), nil))
+	assert.Equal(t, []string{"a2=v2", "a3=v3"},
+		collectEntries(t, bk1, []byte("a"), []byte("a2")))
+	assert.Empty(t, collectEntries(t, bk1, []byte("a"), []byte("b")))
+	assert.Empty(t, collectEntries(t, bk1, []byte("c"), nil))
+	assert.Equal(t, []string{"a1=w1"}, collectEntries(t, bk2, nil, nil))
+
+	batch.Reset()
+	assert.Equal(t, 0, batch.Len())
+	batch.Delete("hello", []byte("a2"))
+	batch.Set("hello", []byte("a3"), []byte("v5"))
+	batch.Delete("world", []byte("a1"))
+	assert.NoError(t, batch.Write())
+	assert.Equal(t, []string{"a1=v1", "a3=v5"},
+		collectEntries(t, bk1, []byte("a"), nil))
+	assert.Empty(t, collectEntries(t, bk2, nil, nil))
+}
+
+func TestDatabase_IteratorAndBatch(t *testing.T) {
+	for _, backend := range []BackendType{
+		BadgerDBBackend, GoLevelDBBackend, BoltDBBackend, MapDBBackend,
+	} {
+		t.Run(string(backend), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", string(backend))
+			if err != nil {
+				panic(err)
+			}
+			defer os.RemoveAll(dir)
+
+			testDB, err := openDatabase(backend, "test", dir)
+			assert.NoError(t, err)
+			defer testDB.Close()
+
+			testIteratorAndBatch(t, testDB)
+		})
+	}
+}
+
+func TestDatabase_Snapshot(t *testing.T) {
+	for _, backend := range []BackendType{
+		BadgerDBBackend, GoLevelDBBackend,
+	} {
+		t.Run(string(backend), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", string(backend))
+			if err != nil {
+				panic(err)
+			}
+			defer os.RemoveAll(dir)
+
+			testDB, err := openDatabase(backend, "test", dir)
+			assert.NoError(t, err)
+			defer testDB.Close()
+
+			bk, _ := testDB.GetBucket("hello")
+			bk.Set([]byte("a1"), []byte("v1"))
+			bk.Set([]byte("a2"), []byte("v2"))
+
+			snapshot, err := NewSnapshot(testDB)
+			assert.NoError(t, err)
+			defer snapshot.Release()
+
+			bk.Set([]byte("a1"), []byte("v3"))
+			bk.Delete([]byte("a2"))
+			bk.Set([]byte("a3"), []byte("v4"))
+
+			sbk, err := snapshot.GetBucket("hello")
+			assert.NoError(t, err)
+			value, err := sbk.Get([]byte("a1"))
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("v1"), value)
+			assert.True(t, sbk.Has([]byte("a2")))
+			assert.False(t, sbk.Has([]byte("a3")))
+			assert.Equal(t, []string{"a1=v1", "a2=v2"},
+				collectEntries(t, sbk, nil, nil))
+			assert.Error(t, sbk.Set([]byte("a4"), []byte("v5")))
+			assert.Error(t, sbk.Delete([]byte("a1")))
+
+			batch := snapshot.NewBatch()
+			batch.Set("hello", []byte("a4"), []byte("v5"))
+			assert.Error(t, batch.Write())
+
+			assert.Equal(t, []string{"a1=v3", "a3=v4"},
+				collectEntries(t, bk, nil, nil))
+		})
+	}
+}
+
+func TestDatabase_SnapshotUnsupported(t *testing.T) {
+	_, err := NewSnapshot(NewMapDB())
+	assert.Equal(t, ErrSnapshotUnsupported, err)
+}
+
+func TestDatabase_KeyLayout(t *testing.T) {
+	for _, backend := range []BackendType{
+		BadgerDBBackend, GoLevelDBBackend, BoltDBBackend, MapDBBackend,
+	} {
+		t.Run(string(backend), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", string(backend))
+			if err != nil {
+				panic(err)
+			}
+			defer os.RemoveAll(dir)
+
+			testDB, err := openDatabase(backend, "test", dir)
+			assert.NoError(t, err)
+			defer testDB.Close()
+
+			// trie nodes whose keys start with IDs of other buckets
+			hash := bytes.Repeat([]byte{'S'}, hashKeySize)
+			batch := testDB.NewBatch()
+			batch.Set(MerkleTrie, hash, []byte("node"))
+			batch.Set(BytesByHash, hash[2:], []byte("short"))
+			batch.Set(BytesByHash, hash, []byte("bytes"))
+			batch.Set(ChainProperty, []byte("key"), []byte("value"))
+			assert.NoError(t, batch.Write())
+
+			bk, _ := testDB.GetBucket(MerkleTrie)
+			assert.Equal(t, []string{string(hash) + "=node"},
+				collectEntries(t, bk, nil, nil))
+			bk, _ = testDB.GetBucket(BytesByHash)
+			assert.Equal(t, []string{
+				string(hash[2:]) + "=short",
+				string(hash) + "=bytes",
+			}, collectEntries(t, bk, nil, nil))
+			bk, _ = testDB.GetBucket(ChainProperty)
+			assert.Equal(t, []string{"key=value"},
+				collectEntries(t, bk, nil, nil))
+		})
+	}
+}
+
+func TestDatabase_Stats(t *testing.T) {
+	for _, backend := range []BackendType{
+		BadgerDBBackend, GoLevelDBBackend, BoltDBBackend, MapDBBackend,
+	} {
+		t.Run(string(backend), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", string(backend))
+			if err != nil {
+				panic(err)
+			}
+			defer os.RemoveAll(dir)
+
+			testDB, err := openDatabase(backend, "test", dir)
+			assert.NoError(t, err)
+			defer testDB.Close()
+
+			hash1 := bytes.Repeat([]byte{'S'}, hashKeySize)
+			hash2 := bytes.Repeat([]byte{'T'}, hashKeySize)
+			batch := testDB.NewBatch()
+			batch.Set(MerkleTrie, hash1, []byte("node1"))
+			batch.Set(MerkleTrie, hash2, []byte("node2"))
+			batch.Set(BytesByHash, hash1, []byte("bytes"))
+			batch.Set(ChainProperty, []byte("key"), []byte("value"))
+			assert.NoError(t, batch.Write())
+
+			// entries of other buckets shouldn't be visible
+			bk, _ := testDB.GetBucket(MerkleTrie)
+			assert.Len(t, collectEntries(t, bk, nil, nil), 2)
+			bk, _ = testDB.GetBucket(BytesByHash)
+			assert.Len(t, collectEntries(t, bk, nil, nil), 1)
+
+			stats, err := StatsOf(testDB, MerkleTrie, BytesByHash, ChainProperty)
+			assert.NoError(t, err)
+			assert.Equal(t, backend, stats.Backend)
+			assert.Len(t, stats.Buckets, 3)
+			keys := make(map[BucketID]int64)
+			for _, bs := range stats.Buckets {
+				keys[bs.ID] = bs.Keys
+			}
+			if backend == GoLevelDBBackend || backend == BadgerDBBackend {
+				// only sizes are estimated
+				assert.False(t, stats.KeysCounted)
+				assert.NoError(t, CountKeys(testDB, stats))
+				for _, bs := range stats.Buckets {
+					keys[bs.ID] = bs.Keys
+				}
+			}
+			assert.True(t, stats.KeysCounted)
+			assert.Equal(t, map[BucketID]int64{
+				MerkleTrie:    2,
+				BytesByHash:   1,
+				ChainProperty: 1,
+			}, keys)
+
+			err = Compact(testDB)
+			if backend == BoltDBBackend {
+				assert.Equal(t, ErrCompactionUnsupported, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if backend == GoLevelDBBackend {
+				// entries are in the tables after compaction, and sizes are
+				// estimated by blocks of the tables
+				value := bytes.Repeat([]byte{1}, 1024)
+				batch.Reset()
+				for i := 0; i < 256; i++ {
+					key := bytes.Repeat([]byte{byte(i)}, hashKeySize)
+					batch.Set(MerkleTrie, key, value)
+					batch.Set(BytesByHash, key, value)
+				}
+				assert.NoError(t, batch.Write())
+				assert.NoError(t, Compact(testDB))
+				stats, err = StatsOf(testDB, MerkleTrie, BytesByHash)
+				assert.NoError(t, err)
+				for _, bs := range stats.Buckets {
+					assert.True(t, bs.Size > 0)
+				}
+			}
+		})
+	}
+}
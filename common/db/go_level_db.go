@@ -1,10 +1,14 @@
 package db
 
 import (
+	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 func init() {
@@ -46,6 +50,96 @@ func (db *GoLevelDB) GetBucket(id BucketID) (Bucket, error) {
 	}, nil
 }
 
+func (db *GoLevelDB) NewBatch() Batch {
+	return &goLevelBatch{db: db.db}
+}
+
+func (db *GoLevelDB) NewSnapshot() (Snapshot, error) {
+	snapshot, err := db.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &goLevelSnapshot{snapshot: snapshot}, nil
+}
+
+// Stats returns approximate sizes of the buckets on the disk without
+// scanning the entries, so it doesn't count the keys (see CountKeys).
+func (db *GoLevelDB) Stats(ids []BucketID) (*Statistics, error) {
+	buckets, err := estimateBuckets(ids, db.sizeOf)
+	if err != nil {
+		return nil, err
+	}
+	levels, size, err := db.tables()
+	if err != nil {
+		return nil, err
+	}
+	compaction := make(map[string]interface{})
+	compaction["filesAtLevel"] = levels
+	if v, err := db.db.GetProperty("leveldb.stats"); err == nil {
+		compaction["stats"] = v
+	}
+	return &Statistics{
+		Backend:    GoLevelDBBackend,
+		Size:       size,
+		Buckets:    buckets,
+		Compaction: compaction,
+	}, nil
+}
+
+// tables returns the number of tables at each level and the sum of sizes of
+// the tables.
+func (db *GoLevelDB) tables() ([]int, int64, error) {
+	v, err := db.db.GetProperty("leveldb.sstables")
+	if err != nil {
+		return nil, 0, err
+	}
+	var levels []int
+	var size int64
+	for _, line := range strings.Split(v, "\n") {
+		if strings.HasPrefix(line, "--- level ") {
+			levels = append(levels, 0)
+			continue
+		}
+		var num, tableSize int64
+		if n, _ := fmt.Sscanf(line, "%d:%d", &num, &tableSize); n == 2 && len(levels) > 0 {
+			levels[len(levels)-1] += 1
+			size += tableSize
+		}
+	}
+	return levels, size, nil
+}
+
+func (db *GoLevelDB) sizeOf(ranges []keyRange) ([]int64, error) {
+	rs := make([]util.Range, len(ranges))
+	for i, r := range ranges {
+		if r.limit == nil {
+			// SizeOf takes nil limit as the lowest key, so it gets the size
+			// before the start to subtract it from the total.
+			rs[i] = util.Range{Limit: r.start}
+		} else {
+			rs[i] = util.Range{Start: r.start, Limit: r.limit}
+		}
+	}
+	sizes, err := db.db.SizeOf(rs)
+	if err != nil {
+		return nil, err
+	}
+	_, total, err := db.tables()
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range ranges {
+		if r.limit == nil {
+			sizes[i] = total - sizes[i]
+		}
+	}
+	return sizes, nil
+}
+
+func (db *GoLevelDB) Compact() error {
+	return db.db.CompactRange(util.Range{})
+}
+
 func (db *GoLevelDB) Close() error {
 	return db.db.Close()
 }
@@ -84,3 +178,141 @@ func (bucket *goLevelBucket) Set(key []byte, value []byte) error {
 func (bucket *goLevelBucket) Delete(key []byte) error {
 	return bucket.db.Delete(internalKey(bucket.id, key), nil)
 }
+
+func (bucket *goLevelBucket) Iterator(prefix, start []byte) Iterator {
+	return newGoLevelIterator(bucket.db, bucket.id, prefix, start)
+}
+
+//----------------------------------------
+// Snapshot
+
+var _ Snapshot = (*goLevelSnapshot)(nil)
+
+type goLevelSnapshot struct {
+	snapshot *leveldb.Snapshot
+}
+
+func (s *goLevelSnapshot) GetBucket(id BucketID) (Bucket, error) {
+	return &goLevelSnapshotBucket{
+		id:       id,
+		snapshot: s.snapshot,
+	}, nil
+}
+
+func (s *goLevelSnapshot) NewBatch() Batch {
+	return &readOnlyBatch{}
+}
+
+func (s *goLevelSnapshot) Release() {
+	s.snapshot.Release()
+}
+
+func (s *goLevelSnapshot) Close() error {
+	s.Release()
+	return nil
+}
+
+type goLevelSnapshotBucket struct {
+	id       BucketID
+	snapshot *leveldb.Snapshot
+}
+
+func (bucket *goLevelSnapshotBucket) Get(key []byte) ([]byte, error) {
+	value, err := bucket.snapshot.Get(internalKey(bucket.id, key), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	} else {
+		return value, err
+	}
+}
+
+func (bucket *goLevelSnapshotBucket) Has(key []byte) bool {
+	ret, err := bucket.snapshot.Has(internalKey(bucket.id, key), nil)
+	if err != nil {
+		return false
+	}
+	return ret
+}
+
+func (bucket *goLevelSnapshotBucket) Set(key []byte, value []byte) error {
+	return errReadOnly
+}
+
+func (bucket *goLevelSnapshotBucket) Delete(key []byte) error {
+	return errReadOnly
+}
+
+func (bucket *goLevelSnapshotBucket) Iterator(prefix, start []byte) Iterator {
+	return newGoLevelIterator(bucket.snapshot, bucket.id, prefix, start)
+}
+
+//----------------------------------------
+// Iterator
+
+type goLevelIterable interface {
+	NewIterator(slice *util.Range, ro *opt.ReadOptions) iterator.Iterator
+}
+
+type goLevelIterator struct {
+	iter   iterator.Iterator
+	id     BucketID
+	offset int
+}
+
+func newGoLevelIterator(db goLevelIterable, id BucketID, prefix, start []byte) Iterator {
+	r := util.BytesPrefix(internalKey(id, prefix))
+	r.Start = internalKey(id, seekKey(prefix, start))
+	return &goLevelIterator{
+		iter:   db.NewIterator(r, nil),
+		id:     id,
+		offset: len(id),
+	}
+}
+
+func (it *goLevelIterator) Next() bool {
+	for it.iter.Next() {
+		if isKeyOf(it.id, it.iter.Key()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (it *goLevelIterator) Key() []byte {
+	if key := it.iter.Key(); key != nil {
+		return copyBytes(key[it.offset:])
+	}
+	return nil
+}
+
+func (it *goLevelIterator) Value() []byte {
+	return copyBytes(it.iter.Value())
+}
+
+func (it *goLevelIterator) Error() error {
+	return it.iter.Error()
+}
+
+func (it *goLevelIterator) Release() {
+	it.iter.Release()
+}
+
+//----------------------------------------
+// Batch
+
+type goLevelBatch struct {
+	batchOps
+	db *leveldb.DB
+}
+
+func (b *goLevelBatch) Write() error {
+	batch := new(leveldb.Batch)
+	for _, op := range b.ops {
+		if op.delete {
+			batch.Delete(internalKey(op.id, op.key))
+		} else {
+			batch.Put(internalKey(op.id, op.key), op.value)
+		}
+	}
+	return b.db.Write(batch, nil)
+}
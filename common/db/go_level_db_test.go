@@ -1,11 +1,14 @@
 package db
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/syndtr/goleveldb/leveldb"
 )
 
 func TestGoLevelDB_Database(t *testing.T) {
@@ -32,3 +35,51 @@ func TestGoLevelDB_Database(t *testing.T) {
 	result, _ = bucket.Get(key)
 	assert.Nil(t, result, "empty")
 }
+
+func TestGoLevelDB_KeyFormat(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goleveldb")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// entries written by previous releases
+	hash := bytes.Repeat([]byte{'S'}, hashKeySize)
+	raw, err := leveldb.OpenFile(filepath.Join(dir, "test"), nil)
+	assert.NoError(t, err)
+	assert.NoError(t, raw.Put(hash, []byte("node"), nil))
+	assert.NoError(t, raw.Put(internalKey(BytesByHash, hash), []byte("bytes"), nil))
+	assert.NoError(t, raw.Close())
+
+	testDB, err := NewGoLevelDB("test", dir)
+	assert.NoError(t, err)
+
+	bk, _ := testDB.GetBucket(MerkleTrie)
+	value, err := bk.Get(hash)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("node"), value)
+	assert.Equal(t, []string{string(hash) + "=node"},
+		collectEntries(t, bk, nil, nil))
+	hash2 := bytes.Repeat([]byte{'T'}, hashKeySize)
+	assert.NoError(t, bk.Set(hash2, []byte("node2")))
+	bk, _ = testDB.GetBucket(BytesByHash)
+	assert.Equal(t, []string{string(hash) + "=bytes"},
+		collectEntries(t, bk, nil, nil))
+	assert.NoError(t, testDB.Close())
+
+	// entries are written in the same format without any other entry
+	raw, err = leveldb.OpenFile(filepath.Join(dir, "test"), nil)
+	assert.NoError(t, err)
+	defer raw.Close()
+	var keys []string
+	iter := raw.NewIterator(nil, nil)
+	for iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	iter.Release()
+	assert.Equal(t, []string{
+		string(hash),
+		string(internalKey(BytesByHash, hash)),
+		string(hash2),
+	}, keys)
+}
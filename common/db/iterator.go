@@ -0,0 +1,212 @@
+package db
+
+import (
+	"bytes"
+	"sort"
+)
+
+// Iterator iterates key-value pairs of a bucket in ascending order of keys.
+// Key and Value return copies, so they are valid after calling Next.
+// Iterator must be released after use.
+//
+//	iter := bucket.Iterator(prefix, nil)
+//	defer iter.Release()
+//	for iter.Next() {
+//		use(iter.Key(), iter.Value())
+//	}
+//	if err := iter.Error(); err != nil {
+//		...
+//	}
+type Iterator interface {
+	// Next moves to the next entry. It returns false if there is no more
+	// entry or an error occurred.
+	Next() bool
+	Key() []byte
+	Value() []byte
+	Error() error
+	Release()
+}
+
+// seekKey returns the first key to visit for the prefix and the start key.
+func seekKey(prefix, start []byte) []byte {
+	if bytes.Compare(start, prefix) > 0 {
+		return start
+	}
+	return prefix
+}
+
+func copyBytes(bs []byte) []byte {
+	if bs == nil {
+		return nil
+	}
+	return append([]byte{}, bs...)
+}
+
+//----------------------------------------
+// Error iterator
+
+type errorIterator struct {
+	err error
+}
+
+func (*errorIterator) Next() bool {
+	return false
+}
+
+func (*errorIterator) Key() []byte {
+	return nil
+}
+
+func (*errorIterator) Value() []byte {
+	return nil
+}
+
+func (it *errorIterator) Error() error {
+	return it.err
+}
+
+func (*errorIterator) Release() {
+}
+
+// NewErrorIterator returns an iterator without any entry, which returns
+// err on Error(). It's nil-safe, so it can be used for an empty iterator.
+func NewErrorIterator(err error) Iterator {
+	return &errorIterator{err: err}
+}
+
+//----------------------------------------
+// Snapshot iterator
+
+type keyValue struct {
+	key   []byte
+	value []byte
+}
+
+// snapshotIterator iterates over the entries collected in advance.
+type snapshotIterator struct {
+	entries []keyValue
+	index   int
+}
+
+func (it *snapshotIterator) Next() bool {
+	if it.index > len(it.entries) {
+		return false
+	}
+	it.index += 1
+	return it.index <= len(it.entries)
+}
+
+func (it *snapshotIterator) current() *keyValue {
+	if it.index < 1 || it.index > len(it.entries) {
+		return nil
+	}
+	return &it.entries[it.index-1]
+}
+
+func (it *snapshotIterator) Key() []byte {
+	if e := it.current(); e != nil {
+		return copyBytes(e.key)
+	}
+	return nil
+}
+
+func (it *snapshotIterator) Value() []byte {
+	if e := it.current(); e != nil {
+		return copyBytes(e.value)
+	}
+	return nil
+}
+
+func (it *snapshotIterator) Error() error {
+	return nil
+}
+
+func (it *snapshotIterator) Release() {
+	it.entries = nil
+}
+
+// newSnapshotIterator returns an iterator over the entries. Entries are
+// sorted by their keys. Values of entries are not copied.
+func newSnapshotIterator(entries []keyValue) *snapshotIterator {
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+	return &snapshotIterator{entries: entries}
+}
+
+//----------------------------------------
+// Merged iterator
+
+// mergedIterator iterates entries of upper layer and lower layer together.
+// Entries of the upper layer hide the ones of the lower layer with the same
+// key, and the entry of upper layer with nil value means deletion.
+type mergedIterator struct {
+	upper *snapshotIterator
+	lower Iterator
+
+	upperValid bool
+	lowerValid bool
+	started    bool
+
+	key   []byte
+	value []byte
+}
+
+func (it *mergedIterator) Next() bool {
+	if !it.started {
+		it.started = true
+		it.upperValid = it.upper.Next()
+		it.lowerValid = it.lower.Next()
+	}
+	for it.upperValid || it.lowerValid {
+		var cmp int
+		if !it.upperValid {
+			cmp = 1
+		} else if !it.lowerValid {
+			cmp = -1
+		} else {
+			cmp = bytes.Compare(it.upper.current().key, it.lower.Key())
+		}
+		if cmp <= 0 {
+			e := it.upper.current()
+			it.upperValid = it.upper.Next()
+			if cmp == 0 {
+				it.lowerValid = it.lower.Next()
+			}
+			if e.value == nil {
+				continue
+			}
+			it.key, it.value = copyBytes(e.key), copyBytes(e.value)
+		} else {
+			it.key, it.value = it.lower.Key(), it.lower.Value()
+			it.lowerValid = it.lower.Next()
+		}
+		return true
+	}
+	it.key, it.value = nil, nil
+	return false
+}
+
+func (it *mergedIterator) Key() []byte {
+	return it.key
+}
+
+func (it *mergedIterator) Value() []byte {
+	return it.value
+}
+
+func (it *mergedIterator) Error() error {
+	return it.lower.Error()
+}
+
+func (it *mergedIterator) Release() {
+	it.upper.Release()
+	it.lower.Release()
+}
+
+func newMergedIterator(upper *snapshotIterator, lower Iterator) Iterator {
+	return &mergedIterator{
+		upper: upper,
+		lower: lower,
+	}
+}
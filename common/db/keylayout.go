@@ -0,0 +1,94 @@
+package db
+
+// Backends sharing one key space among the buckets (goleveldb and badger)
+// prefix keys of the buckets with the bucket IDs, but MerkleTrie bucket has
+// empty ID, so its keys have no prefix. It's the format of the databases
+// written by previous releases, so it must be kept.
+//
+// Entries of MerkleTrie bucket are told from the others only by the size of
+// the keys (hashKeySize). Iterators of MerkleTrie bucket visit only the keys
+// of the size, and iterators of the other buckets skip them. So a key of
+// other buckets making hashKeySize bytes with the bucket ID is visible only
+// to iterators of MerkleTrie bucket. Keys of the buckets in this package are
+// hashes (33 bytes with the ID), heights or fixed size index keys, which
+// never make the size.
+
+// hashKeySize is the size of keys in MerkleTrie bucket.
+const hashKeySize = 32
+
+// isKeyOf returns whether the key in the shared key space belongs to the
+// bucket. The key should have the ID of the bucket as its prefix.
+func isKeyOf(id BucketID, ikey []byte) bool {
+	if id == MerkleTrie {
+		return len(ikey) == hashKeySize
+	}
+	return len(ikey) != hashKeySize
+}
+
+// keyRange is a range of keys in the key space. limit is exclusive, and nil
+// limit means that there is no upper bound.
+type keyRange struct {
+	start, limit []byte
+}
+
+// rangeOf returns the key range of the bucket in the shared key space. The
+// range of MerkleTrie bucket is the whole key space.
+func rangeOf(id BucketID) keyRange {
+	prefix := []byte(id)
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if c := prefix[i]; c < 0xff {
+			limit := make([]byte, i+1)
+			copy(limit, prefix)
+			limit[i] = c + 1
+			return keyRange{start: prefix, limit: limit}
+		}
+	}
+	return keyRange{start: prefix}
+}
+
+// estimateBuckets returns statistics of the buckets with the sizes of their
+// key ranges, which sizeOf returns. MerkleTrie bucket doesn't have its own
+// range, so its size is what remains after the sizes of the other buckets,
+// and the sizes of the other buckets include trie nodes in their ranges.
+func estimateBuckets(ids []BucketID, sizeOf func(ranges []keyRange) ([]int64, error)) ([]BucketStatistics, error) {
+	all := append([]BucketID{}, ids...)
+	if containsBucketID(ids, MerkleTrie) {
+		for _, id := range AllBucketIDs {
+			if id != MerkleTrie && !containsBucketID(all, id) {
+				all = append(all, id)
+			}
+		}
+	}
+	ranges := make([]keyRange, len(all))
+	for i, id := range all {
+		ranges[i] = rangeOf(id)
+	}
+	sizes, err := sizeOf(ranges)
+	if err != nil {
+		return nil, err
+	}
+	buckets := make([]BucketStatistics, len(ids))
+	for i, id := range ids {
+		buckets[i] = BucketStatistics{ID: id, Size: sizes[i]}
+		if id == MerkleTrie {
+			for j, other := range all {
+				if other != MerkleTrie {
+					buckets[i].Size -= sizes[j]
+				}
+			}
+			if buckets[i].Size < 0 {
+				buckets[i].Size = 0
+			}
+		}
+	}
+	return buckets, nil
+}
+
+func containsBucketID(ids []BucketID, id BucketID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateBuckets(t *testing.T) {
+	sizes := map[string]int64{
+		"":  100,
+		"S": 10,
+		"C": 1,
+	}
+	sizeOf := func(ranges []keyRange) ([]int64, error) {
+		res := make([]int64, len(ranges))
+		for i, r := range ranges {
+			if len(r.start) == 0 {
+				for _, v := range sizes {
+					res[i] += v
+				}
+			} else {
+				res[i] = sizes[string(r.start)]
+			}
+		}
+		return res, nil
+	}
+
+	// trie nodes are what remains after the other buckets
+	buckets, err := estimateBuckets(
+		[]BucketID{MerkleTrie, BytesByHash}, sizeOf)
+	assert.NoError(t, err)
+	assert.Equal(t, []BucketStatistics{
+		{ID: MerkleTrie, Size: 100},
+		{ID: BytesByHash, Size: 10},
+	}, buckets)
+}
@@ -1,12 +1,15 @@
 package db
 
 import (
-	"github.com/pkg/errors"
+	"bytes"
 	"sync"
+
+	"github.com/pkg/errors"
 )
 
 type layerBucket struct {
 	lock sync.Mutex
+	id   BucketID
 	data map[string][]byte
 	real Bucket
 }
@@ -65,25 +68,44 @@ func (bk *layerBucket) Delete(key []byte) error {
 	}
 }
 
-func (bk *layerBucket) Flush(write bool) error {
+func (bk *layerBucket) Iterator(prefix, start []byte) Iterator {
 	bk.lock.Lock()
 	defer bk.lock.Unlock()
 
-	if write && bk.data != nil {
-		for k, v := range bk.data {
-			if v == nil {
-				if err := bk.real.Delete([]byte(k)); err != nil {
-					return err
-				}
-			} else {
-				if err := bk.real.Set([]byte(k), v); err != nil {
-					return err
-				}
-			}
+	if bk.data == nil {
+		return bk.real.Iterator(prefix, start)
+	}
+	seek := seekKey(prefix, start)
+	var entries []keyValue
+	for k, v := range bk.data {
+		key := []byte(k)
+		if bytes.HasPrefix(key, prefix) && bytes.Compare(key, seek) >= 0 {
+			entries = append(entries, keyValue{key, v})
+		}
+	}
+	return newMergedIterator(newSnapshotIterator(entries),
+		bk.real.Iterator(prefix, start))
+}
+
+// writeTo puts pending writes of the bucket to the batch.
+func (bk *layerBucket) writeTo(batch Batch) {
+	bk.lock.Lock()
+	defer bk.lock.Unlock()
+
+	for k, v := range bk.data {
+		if v == nil {
+			batch.Delete(bk.id, []byte(k))
+		} else {
+			batch.Set(bk.id, []byte(k), v)
 		}
 	}
+}
+
+func (bk *layerBucket) reset() {
+	bk.lock.Lock()
+	defer bk.lock.Unlock()
+
 	bk.data = nil
-	return nil
 }
 
 type layerDB struct {
@@ -110,6 +132,7 @@ func (ldb *layerDB) GetBucket(id BucketID) (Bucket, error) {
 		return realbk, nil
 	}
 	bk := &layerBucket{
+		id:   id,
 		data: make(map[string][]byte),
 		real: realbk,
 	}
@@ -121,15 +144,31 @@ func (ldb *layerDB) Flush(write bool) error {
 	ldb.lock.Lock()
 	defer ldb.lock.Unlock()
 
-	for _, bk := range ldb.buckets {
-		if err := bk.Flush(write); err != nil {
-			return err
+	if ldb.flushed {
+		return nil
+	}
+	if write {
+		batch := ldb.real.NewBatch()
+		for _, bk := range ldb.buckets {
+			bk.writeTo(batch)
+		}
+		if batch.Len() > 0 {
+			if err := batch.Write(); err != nil {
+				return err
+			}
 		}
 	}
+	for _, bk := range ldb.buckets {
+		bk.reset()
+	}
 	ldb.flushed = true
 	return nil
 }
 
+func (ldb *layerDB) NewBatch() Batch {
+	return NewBucketBatch(ldb)
+}
+
 func (ldb *layerDB) Close() error {
 	return nil
 }
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayerDB_Iterator(t *testing.T) {
+	real := NewMapDB()
+	rbk, _ := real.GetBucket("hello")
+	rbk.Set([]byte("a1"), []byte("v1"))
+	rbk.Set([]byte("a2"), []byte("v2"))
+	rbk.Set([]byte("a3"), []byte("v3"))
+
+	ldb := NewLayerDB(real)
+	bk, _ := ldb.GetBucket("hello")
+	bk.Delete([]byte("a2"))
+	bk.Set([]byte("a3"), []byte("v4"))
+	bk.Set([]byte("a4"), []byte("v5"))
+	bk.Set([]byte("b1"), []byte("v6"))
+
+	assert.Equal(t, []string{"a1=v1", "a3=v4", "a4=v5"},
+		collectEntries(t, bk, []byte("a"), nil))
+	assert.Equal(t, []string{"a3=v4", "a4=v5", "b1=v6"},
+		collectEntries(t, bk, nil, []byte("a2")))
+	assert.Equal(t, []string{"a1=v1", "a2=v2", "a3=v3"},
+		collectEntries(t, rbk, nil, nil))
+}
+
+func TestLayerDB_Flush(t *testing.T) {
+	real := NewMapDB()
+	ldb := NewLayerDB(real)
+	bk1, _ := ldb.GetBucket("hello")
+	bk2, _ := ldb.GetBucket("world")
+	bk1.Set([]byte("a1"), []byte("v1"))
+	bk2.Set([]byte("a2"), []byte("v2"))
+
+	rbk1, _ := real.GetBucket("hello")
+	rbk2, _ := real.GetBucket("world")
+	assert.False(t, rbk1.Has([]byte("a1")))
+	assert.False(t, rbk2.Has([]byte("a2")))
+
+	assert.NoError(t, ldb.Flush(true))
+	assert.True(t, rbk1.Has([]byte("a1")))
+	assert.True(t, rbk2.Has([]byte("a2")))
+
+	// writes after flush go to the real database
+	bk1.Set([]byte("a3"), []byte("v3"))
+	assert.True(t, rbk1.Has([]byte("a3")))
+}
+
+func TestLayerDB_FlushDiscard(t *testing.T) {
+	real := NewMapDB()
+	ldb := NewLayerDB(real)
+	bk, _ := ldb.GetBucket("hello")
+	bk.Set([]byte("a1"), []byte("v1"))
+
+	assert.NoError(t, ldb.Flush(false))
+	rbk, _ := real.GetBucket("hello")
+	assert.False(t, rbk.Has([]byte("a1")))
+	assert.False(t, bk.Has([]byte("a1")))
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/icon-project/goloop/common/log"
@@ -41,19 +42,44 @@ type mapDatabase struct {
 	bks  map[BucketID]*mapBucket
 }
 
-func (t *mapDatabase) GetBucket(id BucketID) (Bucket, error) {
-	t.lock.Lock()
-	defer t.lock.Unlock()
-
+func (t *mapDatabase) getBucketInLock(id BucketID) *mapBucket {
 	if bk, ok := t.bks[id]; ok {
-		return bk, nil
+		return bk
 	}
 	bk := &mapBucket{
 		id:   fmt.Sprintf("%s:%s", t.name, id),
 		real: make(map[string]string),
 	}
 	t.bks[id] = bk
-	return bk, nil
+	return bk
+}
+
+func (t *mapDatabase) GetBucket(id BucketID) (Bucket, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.getBucketInLock(id), nil
+}
+
+func (t *mapDatabase) NewBatch() Batch {
+	return &mapBatch{database: t}
+}
+
+func (t *mapDatabase) Stats(ids []BucketID) (*Statistics, error) {
+	buckets, size, err := scanBuckets(t, ids)
+	if err != nil {
+		return nil, err
+	}
+	return &Statistics{
+		Backend:     MapDBBackend,
+		Size:        size,
+		KeysCounted: true,
+		Buckets:     buckets,
+	}, nil
+}
+
+func (t *mapDatabase) Compact() error {
+	return nil
 }
 
 func (t *mapDatabase) Close() error {
@@ -120,3 +146,61 @@ func (t *mapBucket) Delete(k []byte) error {
 	delete(t.real, string(k))
 	return nil
 }
+
+func (t *mapBucket) Iterator(prefix, start []byte) Iterator {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	seek := string(seekKey(prefix, start))
+	var entries []keyValue
+	for k, v := range t.real {
+		if strings.HasPrefix(k, string(prefix)) && k >= seek {
+			entries = append(entries, keyValue{[]byte(k), []byte(v)})
+		}
+	}
+	return newSnapshotIterator(entries)
+}
+
+//----------------------------------------
+// Batch
+
+type mapBatch struct {
+	batchOps
+	database *mapDatabase
+}
+
+func (b *mapBatch) Write() error {
+	for _, op := range b.ops {
+		if !op.delete && len(op.key) == 0 {
+			return errors.Errorf("Illegal Key:%x", op.key)
+		}
+	}
+
+	b.database.lock.Lock()
+	defer b.database.lock.Unlock()
+
+	locked := make(map[BucketID]*mapBucket)
+	defer func() {
+		for _, bk := range locked {
+			bk.mutex.Unlock()
+		}
+	}()
+	for _, op := range b.ops {
+		bk, ok := locked[op.id]
+		if !ok {
+			bk = b.database.getBucketInLock(op.id)
+			bk.mutex.Lock()
+			locked[op.id] = bk
+		}
+		if configLogMapDB {
+			log.Printf("mapBucket[%s].Batch(%x,%x,delete=%v)",
+				bk.id, op.key, op.value, op.delete)
+		}
+		if op.delete {
+			delete(bk.real, string(op.key))
+		} else {
+			bk.real[string(op.key)] = string(op.value)
+		}
+	}
+	return nil
+}
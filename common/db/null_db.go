@@ -7,6 +7,10 @@ func (*nullDB) GetBucket(id BucketID) (Bucket, error) {
 	return &nullBucket{}, nil
 }
 
+func (*nullDB) NewBatch() Batch {
+	return &nullBatch{}
+}
+
 func (*nullDB) Close() error {
 	return nil
 }
@@ -30,6 +34,21 @@ func (*nullBucket) Delete(key []byte) error {
 	panic("NullBucket.Delete() Unsupported")
 }
 
+func (*nullBucket) Iterator(prefix, start []byte) Iterator {
+	return NewErrorIterator(nil)
+}
+
+type nullBatch struct {
+	batchOps
+}
+
+func (b *nullBatch) Write() error {
+	if len(b.ops) > 0 {
+		panic("NullBatch.Write() Unsupported")
+	}
+	return nil
+}
+
 func NewNullDB() *nullDB {
 	return &nullDB{}
 }
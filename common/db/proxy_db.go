@@ -36,6 +36,13 @@ func (bk *proxyBucket) Delete(key []byte) error {
 	return errors.New("ProxyIsNotRealized")
 }
 
+func (bk *proxyBucket) Iterator(prefix, start []byte) Iterator {
+	if bk.real != nil {
+		return bk.real.Iterator(prefix, start)
+	}
+	return NewErrorIterator(errors.New("ProxyIsNotRealized"))
+}
+
 type proxyDB struct {
 	real    Database
 	buckets map[string]*proxyBucket
@@ -57,6 +64,13 @@ func (pdb *proxyDB) GetBucket(id BucketID) (Bucket, error) {
 	return bk, nil
 }
 
+func (pdb *proxyDB) NewBatch() Batch {
+	if pdb.real != nil {
+		return pdb.real.NewBatch()
+	}
+	return NewBucketBatch(pdb)
+}
+
 func (pdb *proxyDB) Close() error {
 	return nil
 }
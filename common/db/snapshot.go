@@ -0,0 +1,39 @@
+package db
+
+import "github.com/pkg/errors"
+
+var (
+	ErrSnapshotUnsupported = errors.New("SnapshotUnsupported")
+	errReadOnly            = errors.New("ReadOnlySnapshot")
+)
+
+// Snapshot is a read-only point-in-time view of a database.
+// Writes to the buckets of the snapshot fail, and writes to the database
+// after creation of the snapshot are not visible through it.
+// It should be released after use. Close also releases it.
+type Snapshot interface {
+	Database
+	Release()
+}
+
+// Snapshotter is implemented by the databases supporting Snapshot.
+type Snapshotter interface {
+	NewSnapshot() (Snapshot, error)
+}
+
+// NewSnapshot returns a new snapshot of the database. It returns
+// ErrSnapshotUnsupported if the database doesn't support snapshots.
+func NewSnapshot(database Database) (Snapshot, error) {
+	if s, ok := database.(Snapshotter); ok {
+		return s.NewSnapshot()
+	}
+	return nil, ErrSnapshotUnsupported
+}
+
+type readOnlyBatch struct {
+	batchOps
+}
+
+func (b *readOnlyBatch) Write() error {
+	return errReadOnly
+}
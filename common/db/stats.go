@@ -0,0 +1,134 @@
+package db
+
+import "github.com/pkg/errors"
+
+var (
+	ErrStatsUnsupported      = errors.New("StatsUnsupported")
+	ErrCompactionUnsupported = errors.New("CompactionUnsupported")
+)
+
+// BucketStatistics is statistics of a bucket.
+type BucketStatistics struct {
+	ID BucketID `json:"id"`
+
+	// Keys is the number of entries. It's valid only if KeysCounted of
+	// the statistics is true.
+	Keys int64 `json:"keys"`
+
+	// Size is approximate size of the entries in bytes.
+	Size int64 `json:"size"`
+}
+
+// Statistics is statistics of a database.
+type Statistics struct {
+	Backend BackendType `json:"backend"`
+
+	// Size is approximate size of the database in bytes.
+	Size int64 `json:"size"`
+
+	// KeysCounted is whether the entries of the buckets are counted.
+	// Backends estimating sizes (goleveldb and badger) don't count them
+	// unless CountKeys is used.
+	KeysCounted bool               `json:"keysCounted"`
+	Buckets     []BucketStatistics `json:"buckets"`
+
+	// Compaction is backend specific information about compaction state.
+	Compaction map[string]interface{} `json:"compaction,omitempty"`
+}
+
+// Stats is implemented by the databases reporting statistics and
+// supporting compaction on demand.
+type Stats interface {
+	// Stats returns statistics of the buckets. Backends for large databases
+	// (goleveldb and badger) estimate sizes of the buckets with the sizes of
+	// their key ranges, and the others may scan all entries of the buckets.
+	Stats(ids []BucketID) (*Statistics, error)
+
+	// Compact compacts the storage to reclaim the space of deleted or
+	// overwritten entries. It blocks until it's done.
+	Compact() error
+}
+
+// StatsOf returns statistics of the buckets of the database. If ids is
+// empty, then it returns statistics of AllBucketIDs.
+func StatsOf(database Database, ids ...BucketID) (*Statistics, error) {
+	s, ok := database.(Stats)
+	if !ok {
+		return nil, ErrStatsUnsupported
+	}
+	if len(ids) == 0 {
+		ids = AllBucketIDs
+	}
+	return s.Stats(ids)
+}
+
+// Compact compacts the storage of the database.
+func Compact(database Database) error {
+	if s, ok := database.(Stats); ok {
+		return s.Compact()
+	}
+	return ErrCompactionUnsupported
+}
+
+// CountKeys counts the entries of the buckets in the statistics by scanning
+// all of them, if they are not counted yet. It takes long for large
+// databases.
+func CountKeys(database Database, stats *Statistics) error {
+	if stats.KeysCounted {
+		return nil
+	}
+	for i := range stats.Buckets {
+		bk, err := database.GetBucket(stats.Buckets[i].ID)
+		if err != nil {
+			return err
+		}
+		var keys int64
+		iter := bk.Iterator(nil, nil)
+		for iter.Next() {
+			keys += 1
+		}
+		err = iter.Error()
+		iter.Release()
+		if err != nil {
+			return err
+		}
+		stats.Buckets[i].Keys = keys
+	}
+	stats.KeysCounted = true
+	return nil
+}
+
+// scanBucket returns statistics of the bucket by scanning all entries.
+func scanBucket(database Database, id BucketID) (*BucketStatistics, error) {
+	bk, err := database.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	stats := &BucketStatistics{ID: id}
+	iter := bk.Iterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		stats.Keys += 1
+		stats.Size += int64(len(iter.Key()) + len(iter.Value()))
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// scanBuckets returns statistics of the buckets by scanning all entries.
+// It also returns the sum of sizes of the buckets.
+func scanBuckets(database Database, ids []BucketID) ([]BucketStatistics, int64, error) {
+	var size int64
+	buckets := make([]BucketStatistics, 0, len(ids))
+	for _, id := range ids {
+		stats, err := scanBucket(database, id)
+		if err != nil {
+			return nil, 0, err
+		}
+		buckets = append(buckets, *stats)
+		size += stats.Size
+	}
+	return buckets, size, nil
+}
@@ -43,10 +43,48 @@ func (m *databaseWithCacheManager) getAccountNodeCache(id []byte) *NodeCache {
 	}
 }
 
+// NewSnapshot returns a snapshot of the underlying database.
+// Node caches are shared with the snapshot. Nodes are identified by their
+// hashes, so cached nodes are valid for any snapshot.
+func (m *databaseWithCacheManager) NewSnapshot() (db.Snapshot, error) {
+	snapshot, err := db.NewSnapshot(m.Database)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotWithCacheManager{
+		Snapshot: snapshot,
+		manager:  m,
+	}, nil
+}
+
+func (m *databaseWithCacheManager) Stats(ids []db.BucketID) (*db.Statistics, error) {
+	return db.StatsOf(m.Database, ids...)
+}
+
+func (m *databaseWithCacheManager) Compact() error {
+	return db.Compact(m.Database)
+}
+
+type snapshotWithCacheManager struct {
+	db.Snapshot
+	manager *databaseWithCacheManager
+}
+
+func managerOf(database db.Database) *databaseWithCacheManager {
+	switch d := database.(type) {
+	case *databaseWithCacheManager:
+		return d
+	case *snapshotWithCacheManager:
+		return d.manager
+	default:
+		return nil
+	}
+}
+
 // WorldNodeCacheOf get node cache of the world if it has.
 // If node cache for world state is not enabled, it returns nil.
 func WorldNodeCacheOf(database db.Database) *NodeCache {
-	if m, ok := database.(*databaseWithCacheManager); ok {
+	if m := managerOf(database); m != nil {
 		return m.getWorldNodeCache()
 	}
 	return nil
@@ -55,7 +93,7 @@ func WorldNodeCacheOf(database db.Database) *NodeCache {
 // AccountNodeCacheOf get node cache of the account specified by *id*.
 // If node cache for the account is not enabled, it returns nil.
 func AccountNodeCacheOf(database db.Database, id []byte) *NodeCache {
-	if m, ok := database.(*databaseWithCacheManager); ok {
+	if m := managerOf(database); m != nil {
 		return m.getAccountNodeCache(id)
 	}
 	return nil
@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+)
+
+func TestCacheManager_Snapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cachemanager")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	database, err := db.NewGoLevelDB("test", dir)
+	assert.NoError(t, err)
+	defer database.Close()
+
+	cdb := AttachManager(database, dir, 1, 0)
+	snapshot, err := db.NewSnapshot(cdb)
+	assert.NoError(t, err)
+	defer snapshot.Release()
+
+	assert.NotNil(t, WorldNodeCacheOf(snapshot))
+	assert.Equal(t, WorldNodeCacheOf(cdb), WorldNodeCacheOf(snapshot))
+	id := []byte("account")
+	assert.NotNil(t, AccountNodeCacheOf(snapshot, id))
+	assert.Equal(t, AccountNodeCacheOf(cdb, id), AccountNodeCacheOf(snapshot, id))
+
+	assert.Nil(t, WorldNodeCacheOf(database))
+
+	_, err = db.NewSnapshot(AttachManager(db.NewMapDB(), dir, 1, 0))
+	assert.Equal(t, db.ErrSnapshotUnsupported, err)
+}
@@ -27,7 +27,11 @@ type commitVoteList struct {
 }
 
 func (vl *commitVoteList) Verify(block module.BlockData, validators module.ValidatorList) error {
-	if block.Height() == 0 {
+	return vl.verify(block.Height(), block.ID(), validators)
+}
+
+func (vl *commitVoteList) verify(height int64, id []byte, validators module.ValidatorList) error {
+	if height == 0 {
 		if len(vl.Items) == 0 {
 			return nil
 		} else {
@@ -36,10 +40,10 @@ func (vl *commitVoteList) Verify(block module.BlockData, validators module.Valid
 	}
 	vset := make([]bool, validators.Len())
 	msg := newVoteMessage()
-	msg.Height = block.Height()
+	msg.Height = height
 	msg.Round = vl.Round
 	msg.Type = voteTypePrecommit
-	msg.BlockID = block.ID()
+	msg.BlockID = id
 	msg.BlockPartSetID = vl.BlockPartSetID
 	for i, item := range vl.Items {
 		msg.Timestamp = item.Timestamp
@@ -144,3 +148,14 @@ func NewCommitVoteSetFromBytes(bs []byte) module.CommitVoteSet {
 	}
 	return vl
 }
+
+// VerifyCommitVotes verifies serialized commit votes for the block of the
+// height and the ID with the validators of the block. It's used by the
+// clients which have only headers of blocks.
+func VerifyCommitVotes(bs []byte, height int64, id []byte, validators module.ValidatorList) error {
+	vl := &commitVoteList{}
+	if _, err := vlCodec.UnmarshalFromBytes(bs, vl); err != nil {
+		return errors.IllegalArgumentError.Wrap(err, "InvalidCommitVotes")
+	}
+	return vl.verify(height, id, validators)
+}
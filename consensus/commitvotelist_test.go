@@ -4,6 +4,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
 )
 
 func TestCommitVoteList_Timestamp(t *testing.T) {
@@ -28,3 +34,50 @@ func TestCommitVoteList_Timestamp(t *testing.T) {
 		assert.Equal(t, cvl.Timestamp(), c.out)
 	}
 }
+
+func TestVerifyCommitVotes(t *testing.T) {
+	const height = 10
+	id := crypto.SHA3Sum256([]byte("block"))
+	psid := &PartSetID{Count: 1, Hash: crypto.SHA3Sum256([]byte("parts"))}
+
+	wallets := make([]module.Wallet, 4)
+	validators := make([]module.Validator, len(wallets))
+	for i := range wallets {
+		wallets[i] = wallet.New()
+		v, err := state.ValidatorFromAddress(wallets[i].Address())
+		assert.NoError(t, err)
+		validators[i] = v
+	}
+	vl, err := state.ValidatorSnapshotFromSlice(db.NewMapDB(), validators)
+	assert.NoError(t, err)
+
+	votesOf := func(ws []module.Wallet) []byte {
+		msgs := make([]*voteMessage, len(ws))
+		for i, w := range ws {
+			msg := newVoteMessage()
+			msg.Height = height
+			msg.Round = 0
+			msg.Type = voteTypePrecommit
+			msg.BlockID = id
+			msg.BlockPartSetID = psid
+			msg.Timestamp = int64(i)
+			assert.NoError(t, msg.sign(w))
+			msgs[i] = msg
+		}
+		return newCommitVoteList(msgs).Bytes()
+	}
+
+	votes := votesOf(wallets[:3])
+	assert.NoError(t, VerifyCommitVotes(votes, height, id, vl))
+	assert.Error(t, VerifyCommitVotes(votes, height+1, id, vl))
+	assert.Error(t, VerifyCommitVotes(votes, height, crypto.SHA3Sum256(id), vl))
+
+	// votes of 2/3 aren't enough
+	assert.Error(t, VerifyCommitVotes(votesOf(wallets[:2]), height, id, vl))
+
+	// votes of unknown validator
+	others := []module.Wallet{wallets[0], wallets[1], wallet.New()}
+	assert.Error(t, VerifyCommitVotes(votesOf(others), height, id, vl))
+
+	assert.Error(t, VerifyCommitVotes([]byte{0x01}, height, id, vl))
+}
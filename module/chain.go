@@ -25,6 +25,13 @@ type Chain interface {
 	NormalTxPoolSize() int
 	PatchTxPoolSize() int
 	MaxBlockTxBytes() int
+	TxPoolOrder() string
+	EventIndex() bool
+	TxIndex() bool
+	InternalTx() bool
+	StepDetails() bool
+	ParallelCheck() bool
+	QueryCacheSize() int
 	DefaultWaitTimeout() time.Duration
 	MaxWaitTimeout() time.Duration
 	Genesis() []byte
@@ -43,7 +50,10 @@ type Chain interface {
 	Stop() error
 	Import(src string, height int64) error
 	Prune(gs string, dbt string, height int64) error
-	Backup(file string, extra []string) error
+	Backup(file string, base string, extra []string) error
+	StartGC(keep int64) error
+	PauseGC() error
+	ResumeGC() error
 	Term() error
 	State() (string, int64, error)
 	IsStarted() bool
@@ -104,13 +104,15 @@ const (
 	Revision6
 	Revision7
 	Revision8
+	Revision9
+	Revision10
 	RevisionReserved
 )
 
 const (
 	DefaultRevision = Revision4
 	MaxRevision     = RevisionReserved - 1
-	LatestRevision  = Revision8
+	LatestRevision  = Revision10
 )
 
 func (s Status) String() string {
@@ -322,7 +324,8 @@ type ServiceManager interface {
 	// SendPatch sends a patch
 	SendPatch(patch Patch) error
 
-	// Call handles read-only contract API call.
+	// Call handles read-only contract API call. stateOverride of js, if
+	// exists, replaces the state of the accounts only for the call.
 	Call(result []byte, vl ValidatorList, js []byte, bi BlockInfo) (interface{}, error)
 
 	// ValidatorListFromHash returns ValidatorList from hash.
@@ -334,6 +337,14 @@ type ServiceManager interface {
 	// GetTotalSupply returns total supplied coin
 	GetTotalSupply(result []byte) (*big.Int, error)
 
+	// GetProofForAccount returns encoded account and merkle proof of it in
+	// the world state of the result.
+	GetProofForAccount(result []byte, addr Address) ([]byte, [][]byte, error)
+
+	// GetProofForStorage returns value and merkle proof of it in the storage
+	// of the account.
+	GetProofForStorage(result []byte, addr Address, key []byte) ([]byte, [][]byte, error)
+
 	// GetNetworkID returns network ID of the state
 	GetNetworkID(result []byte) (int64, error)
 
@@ -358,6 +369,61 @@ type ServiceManager interface {
 	// SendTransactionAndWait send transaction and return channel for result
 	SendTransactionAndWait(tx interface{}) ([]byte, <-chan interface{}, error)
 
+	// SubscribeTransactionPool returns the channel receiving events of
+	// transactions entering and leaving the pool and the function cancelling
+	// the subscription. Events are queued until the subscriber receives
+	// them, so they are delivered in order without loss.
+	SubscribeTransactionPool(size int) (<-chan *TransactionPoolEvent, func())
+
+	// GetPendingTransactions returns transactions in the pool. Transactions
+	// are filtered by from and to if they are not nil. It returns all of them
+	// for a non-positive limit.
+	GetPendingTransactions(from, to Address, limit int) []Transaction
+
+	// GetTransactionPoolStatus returns status of pools for each group.
+	GetTransactionPoolStatus() []*TransactionPoolStatus
+
+	// GetEventIndexStart returns the height of the first block indexed by
+	// the event index. It returns UnsupportedError if the index is disabled,
+	// and NotFoundError if no block is indexed yet.
+	GetEventIndexStart() (int64, error)
+
+	// FindEvents returns locations of events of the term in the blocks from
+	// the height to the height by the event index. It returns up to limit
+	// locations after the location if after isn't nil. The term can be
+	// made by service.EventTermOf.
+	FindEvents(term []byte, from, to int64, after *EventLocation, limit int) ([]EventLocation, error)
+
+	// GetTransactionIndexStart returns the height of the first block indexed
+	// by the transaction index. It returns UnsupportedError if the index is
+	// disabled, and NotFoundError if no block is indexed yet.
+	GetTransactionIndexStart() (int64, error)
+
+	// FindTransactions returns locations of normal transactions sent from or
+	// to the address in the reverse order. It returns up to limit locations
+	// before the location if before isn't nil.
+	FindTransactions(addr Address, before *TransactionLocation, limit int) ([]TransactionLocation, error)
+
+	// PruneTransactionIndex removes entries of the transaction index for
+	// the blocks below the height.
+	PruneTransactionIndex(height int64) error
+
+	// PruneEventIndex removes entries of the event index for the blocks
+	// below the height, and moves the start height of the index to it.
+	PruneEventIndex(height int64) error
+
+	// GetInternalTransactions returns internal transactions of the normal
+	// transaction in the block of the height. It returns UnsupportedError
+	// if the recording is disabled, and NotFoundError if the block isn't
+	// recorded.
+	GetInternalTransactions(height int64, id []byte) ([]InternalTransaction, error)
+
+	// GetStepUsedDetails returns the amount of used steps for each step
+	// type of the normal transaction in the block of the height. It returns
+	// UnsupportedError if the recording is disabled, and NotFoundError if
+	// the block isn't recorded.
+	GetStepUsedDetails(height int64, id []byte) (map[string]*big.Int, error)
+
 	// WaitTransactionResult return channel for result.
 	WaitTransactionResult(id []byte) (<-chan interface{}, error)
 
@@ -371,10 +437,88 @@ type ServiceManager interface {
 
 	// ExecuteTransaction executes the transaction on the specified state.
 	// Then it returns the expected result of the transaction.
-	// It ignores supplied step limit.
+	// It ignores supplied step limit. stateOverride of js, if exists,
+	// replaces the state of the accounts only for the execution.
 	ExecuteTransaction(result []byte, vh []byte, js []byte, bi BlockInfo) (Receipt, error)
+
+	// SimulateTransactions executes the transactions in the order on the
+	// specified state. Each transaction sees the changes of the ones before
+	// it, and nothing is committed. It ignores supplied step limits.
+	SimulateTransactions(result []byte, vh []byte, txs [][]byte, bi BlockInfo) (*SimulationResult, error)
 }
 
+type TransactionPoolEventType int
+
+const (
+	TransactionAdded TransactionPoolEventType = iota
+	TransactionRemoved
+	TransactionDropped
+)
+
+// TransactionPoolEvent is the event of the transaction entering or leaving
+// the pool. Transaction is available only for TransactionAdded, and Err is
+// the reason for TransactionDropped.
+type TransactionPoolEvent struct {
+	Type        TransactionPoolEventType
+	ID          []byte
+	Transaction Transaction
+	Err         error
+}
+
+// TransactionPoolStatus is the status of the pool of the group. Drops is
+// the number of dropped transactions for each reason.
+type TransactionPoolStatus struct {
+	Group TransactionGroup
+	Size  int
+	Used  int
+	Drops map[string]int
+}
+
+// EventLocation is the location of the event log. Height is the height of
+// the block including the transaction. Note that the receipt of the
+// transaction is in the result of the next block.
+type EventLocation struct {
+	Height     int64
+	TxIndex    int
+	EventIndex int
+}
+
+// TransactionLocation is the location of the normal transaction. ID is the
+// hash of the transaction.
+type TransactionLocation struct {
+	Height  int64
+	TxIndex int
+	ID      []byte
+}
+
+// InternalTransaction is ICX transferred by a contract during the execution
+// of a transaction. Depth is the depth of the call making the transfer, and
+// the call of the transaction is at depth zero.
+type InternalTransaction interface {
+	From() Address
+	To() Address
+	Value() *big.Int
+	Depth() int
+	ToJSON(version JSONVersion) (interface{}, error)
+}
+
+// BalanceChange is the change of the balance of the account.
+type BalanceChange struct {
+	Address Address
+	Before  *big.Int
+	After   *big.Int
+}
+
+// SimulationResult is the result of the transactions simulated in the
+// order. Changes are the balances changed by the transactions including
+// the fees gathered to the treasury.
+type SimulationResult struct {
+	Receipts []Receipt
+	Changes  []BalanceChange
+}
+
+// TraceInfo selects the transaction to be traced. All transactions of the
+// group are traced if Index is negative.
 type TraceInfo struct {
 	Group    TransactionGroup
 	Index    int
@@ -393,3 +537,27 @@ type TraceCallback interface {
 	OnLog(level TraceLevel, msg string)
 	OnEnd(e error)
 }
+
+// TraceFrame is the call frame entered during the execution. Type is one of
+// "call", "transfer", "deploy", "accept", "patch" and "getAPI".
+type TraceFrame struct {
+	Type      string
+	From      Address
+	To        Address
+	Value     *big.Int
+	Method    string
+	StepLimit *big.Int
+}
+
+// TraceFrameCallback can be implemented by TraceCallback to get the call
+// frames of the transactions in addition to the logs. OnTransactionStart is
+// called before the frames of each traced transaction. OnFrameEnter and
+// OnFrameExit are paired, so they make a call tree. OnFrameEvent is called
+// for events of the current frame, and OnFrameExit has the address of the
+// deployed contract for the deploy.
+type TraceFrameCallback interface {
+	OnTransactionStart(group TransactionGroup, index int)
+	OnFrameEnter(frame *TraceFrame)
+	OnFrameEvent(addr Address, indexed, data [][]byte)
+	OnFrameExit(stepUsed *big.Int, status error, addr Address)
+}
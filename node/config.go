@@ -6,9 +6,11 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"time"
 
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server"
 )
 
 const (
@@ -101,13 +103,17 @@ func (c *StaticConfig) AbsBaseDir() string {
 }
 
 const (
-	DefaultEEInstances = 1
+	DefaultEEInstances     = 1
+	DefaultEEInvokeTimeout = 5000
 )
 
 type RuntimeConfig struct {
 	EEInstances       int    `json:"eeInstances"`
+	EEInvokeTimeout   int    `json:"eeInvokeTimeout"` // millisecond
 	RPCDefaultChannel string `json:"rpcDefaultChannel"`
 	RPCIncludeDebug   bool   `json:"rpcIncludeDebug"`
+	RPCBatchLimit     int    `json:"rpcBatchLimit"`
+	RPCBatchTimeout   int    `json:"rpcBatchTimeout"` // millisecond
 
 	FilePath string `json:"-"` // absolute path
 }
@@ -141,8 +147,11 @@ func (c *RuntimeConfig) save() error {
 
 func loadRuntimeConfig(baseDir string) (*RuntimeConfig, error) {
 	cfg := &RuntimeConfig{
-		EEInstances: DefaultEEInstances,
-		FilePath:    path.Join(baseDir, "rconfig.json"),
+		EEInstances:     DefaultEEInstances,
+		EEInvokeTimeout: DefaultEEInvokeTimeout,
+		RPCBatchLimit:   server.DefaultJsonRpcBatchLimit,
+		RPCBatchTimeout: int(server.DefaultJsonRpcBatchTimeout / time.Millisecond),
+		FilePath:        path.Join(baseDir, "rconfig.json"),
 	}
 	if err := cfg.load(); err != nil {
 		if os.IsNotExist(err) {
@@ -15,12 +15,14 @@ import (
 
 	"github.com/icon-project/goloop/chain"
 	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/server"
 	"github.com/icon-project/goloop/server/metric"
+	"github.com/icon-project/goloop/service"
 	"github.com/icon-project/goloop/service/eeproxy"
 )
 
@@ -420,6 +422,13 @@ func (n *Node) JoinChain(
 		NormalTxPoolSize: p.NormalTxPoolSize,
 		PatchTxPoolSize:  p.PatchTxPoolSize,
 		MaxBlockTxBytes:  p.MaxBlockTxBytes,
+		TxPoolOrder:      p.TxPoolOrder,
+		EventIndex:       p.EventIndex,
+		TxIndex:          p.TxIndex,
+		InternalTx:       p.InternalTx,
+		StepDetails:      p.StepDetails,
+		ParallelCheck:    p.ParallelCheck,
+		QueryCacheSize:   p.QueryCacheSize,
 		NodeCache:        p.NodeCache,
 		DefWaitTimeout:   p.DefWaitTimeout,
 		MaxWaitTimeout:   p.MaxWaitTimeout,
@@ -540,7 +549,9 @@ func (n *Node) PruneChain(cid int, dbt string, height int64) error {
 	return c.Prune(gs, dbt, height)
 }
 
-func (n *Node) BackupChain(cid int) (string, error) {
+// BackupChain makes a backup of the chain in the backup directory. If base is
+// not empty, it makes an incremental backup based on the backup of the name.
+func (n *Node) BackupChain(cid int, base string) (string, error) {
 	defer n.mtx.RUnlock()
 	n.mtx.RLock()
 
@@ -554,11 +565,109 @@ func (n *Node) BackupChain(cid int) (string, error) {
 		return "", errors.InvalidStateError.Wrapf(err,
 			"Fail to make backup directory=%s", backupDir)
 	}
+	var baseFile string
+	if len(base) > 0 {
+		if base != path.Base(base) {
+			return "", errors.IllegalArgumentError.Errorf(
+				"InvalidBackupName(name=%s)", base)
+		}
+		baseFile = path.Join(backupDir, base)
+		if _, err := os.Stat(baseFile); err != nil {
+			return "", errors.NotFoundError.Wrapf(err,
+				"BaseBackupNotFound(name=%s)", base)
+		}
+	}
 	now := time.Now()
 	name := fmt.Sprintf("%#x_%#x_%s_%s.zip", c.CID(), c.NID(), c.Channel(),
 		now.Format("20060102-150405"))
 	file := path.Join(backupDir, name)
-	return name, c.Backup(file, []string{ChainGenesisZipFileName, ChainConfigFileName})
+	return name, c.Backup(file, baseFile,
+		[]string{ChainGenesisZipFileName, ChainConfigFileName})
+}
+
+func (n *Node) _getChainDatabase(cid int) (db.Database, error) {
+	c, err := n._get(cid)
+	if err != nil {
+		return nil, err
+	}
+	database := c.Database()
+	if database == nil {
+		return nil, errors.InvalidStateError.Errorf(
+			"DatabaseIsNotOpened(cid=%#x)", cid)
+	}
+	return database, nil
+}
+
+// GetChainDBStats returns statistics of the chain database. If countKeys is
+// true, then it counts the keys by scanning all entries for the backends
+// estimating statistics without counting them.
+func (n *Node) GetChainDBStats(cid int, countKeys bool) (*db.Statistics, error) {
+	n.mtx.RLock()
+	database, err := n._getChainDatabase(cid)
+	n.mtx.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	stats, err := db.StatsOf(database)
+	if err == db.ErrStatsUnsupported {
+		return nil, errors.UnsupportedError.Wrap(err, "DBStatsUnsupported")
+	} else if err != nil {
+		return nil, err
+	}
+	if countKeys {
+		if err := db.CountKeys(database, stats); err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+func (n *Node) CompactChainDB(cid int) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	database, err := n._getChainDatabase(cid)
+	if err != nil {
+		return err
+	}
+	err = db.Compact(database)
+	if err == db.ErrCompactionUnsupported {
+		return errors.UnsupportedError.Wrap(err, "DBCompactionUnsupported")
+	}
+	return err
+}
+
+func (n *Node) StartChainGC(cid int, keep int64) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	c, err := n._get(cid)
+	if err != nil {
+		return err
+	}
+	return c.StartGC(keep)
+}
+
+func (n *Node) PauseChainGC(cid int) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	c, err := n._get(cid)
+	if err != nil {
+		return err
+	}
+	return c.PauseGC()
+}
+
+func (n *Node) ResumeChainGC(cid int) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	c, err := n._get(cid)
+	if err != nil {
+		return err
+	}
+	return c.ResumeGC()
 }
 
 type BackupInfo struct {
@@ -701,6 +810,49 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			} else {
 				c.cfg.MaxBlockTxBytes = intVal
 			}
+		case "txPoolOrder":
+			if !service.IsTxOrderOption(value) {
+				return errors.Errorf("InvalidTxPoolOrderOption(%s)", value)
+			}
+			c.cfg.TxPoolOrder = value
+		case "eventIndex":
+			if ei, err := strconv.ParseBool(value); err != nil {
+				return err
+			} else {
+				c.cfg.EventIndex = ei
+			}
+		case "txIndex":
+			if ti, err := strconv.ParseBool(value); err != nil {
+				return err
+			} else {
+				c.cfg.TxIndex = ti
+			}
+		case "internalTx":
+			if it, err := strconv.ParseBool(value); err != nil {
+				return err
+			} else {
+				c.cfg.InternalTx = it
+			}
+		case "stepDetails":
+			if sd, err := strconv.ParseBool(value); err != nil {
+				return err
+			} else {
+				c.cfg.StepDetails = sd
+			}
+		case "parallelCheck":
+			if pc, err := strconv.ParseBool(value); err != nil {
+				return err
+			} else {
+				c.cfg.ParallelCheck = pc
+			}
+		case "queryCacheSize":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else if intVal < 0 {
+				return errors.Errorf("negative value")
+			} else {
+				c.cfg.QueryCacheSize = intVal
+			}
 		case "nodeCache":
 			if !chain.IsNodeCacheOption(value) {
 				return errors.Errorf("InvalidNodeCacheOption(%s)", value)
@@ -834,6 +986,15 @@ func (n *Node) Configure(key string, value string) error {
 		if err := n.pm.SetInstances(n.rcfg.EEInstances, n.rcfg.EEInstances, n.rcfg.EEInstances); err != nil {
 			return err
 		}
+	case "eeInvokeTimeout":
+		if intVal, err := strconv.Atoi(value); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else if intVal < 0 {
+			return errors.Errorf("negative value")
+		} else {
+			n.rcfg.EEInvokeTimeout = intVal
+		}
+		n.pm.SetInvokeTimeout(time.Duration(n.rcfg.EEInvokeTimeout) * time.Millisecond)
 	case "rpcDefaultChannel":
 		n.rcfg.RPCDefaultChannel = value
 		n.srv.SetDefaultChannel(n.rcfg.RPCDefaultChannel)
@@ -844,6 +1005,24 @@ func (n *Node) Configure(key string, value string) error {
 			n.rcfg.RPCIncludeDebug = boolVal
 		}
 		n.srv.SetIncludeDebug(n.rcfg.RPCIncludeDebug)
+	case "rpcBatchLimit":
+		if intVal, err := strconv.Atoi(value); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else if intVal < 0 {
+			return errors.Errorf("negative value")
+		} else {
+			n.rcfg.RPCBatchLimit = intVal
+		}
+		n.srv.SetBatchLimit(n.rcfg.RPCBatchLimit)
+	case "rpcBatchTimeout":
+		if intVal, err := strconv.Atoi(value); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else if intVal < 0 {
+			return errors.Errorf("negative value")
+		} else {
+			n.rcfg.RPCBatchTimeout = intVal
+		}
+		n.srv.SetBatchTimeout(time.Duration(n.rcfg.RPCBatchTimeout) * time.Millisecond)
 	default:
 		return errors.Errorf("not found key")
 	}
@@ -876,6 +1055,8 @@ func NewNode(
 		_ = nt.SetListenAddress(cfg.P2PListenAddr)
 	}
 	srv := server.NewManager(cfg.RPCAddr, cfg.RPCDump, rcfg.RPCIncludeDebug, rcfg.RPCDefaultChannel, w, l)
+	srv.SetBatchLimit(rcfg.RPCBatchLimit)
+	srv.SetBatchTimeout(time.Duration(rcfg.RPCBatchTimeout) * time.Millisecond)
 
 	ee, err := eeproxy.AllocEngines(l, strings.Split(cfg.Engines, ",")...)
 	if err != nil {
@@ -887,6 +1068,7 @@ func NewNode(
 		log.Panicf("fail to start EEManager err=%+v", err)
 	}
 
+	pm.SetInvokeTimeout(time.Duration(rcfg.EEInvokeTimeout) * time.Millisecond)
 	if err := pm.SetInstances(rcfg.EEInstances, rcfg.EEInstances, rcfg.EEInstances); err != nil {
 		log.Panicf("fail to EEManager.SetInstances err=%+v", err)
 	}
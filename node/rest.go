@@ -84,6 +84,13 @@ type ChainConfig struct {
 	NormalTxPoolSize int    `json:"normalTxPool,omitempty"`
 	PatchTxPoolSize  int    `json:"patchTxPool,omitempty"`
 	MaxBlockTxBytes  int    `json:"maxBlockTxBytes,omitempty"`
+	TxPoolOrder      string `json:"txPoolOrder,omitempty"`
+	EventIndex       bool   `json:"eventIndex,omitempty"`
+	TxIndex          bool   `json:"txIndex,omitempty"`
+	InternalTx       bool   `json:"internalTx,omitempty"`
+	StepDetails      bool   `json:"stepDetails,omitempty"`
+	ParallelCheck    bool   `json:"parallelCheck,omitempty"`
+	QueryCacheSize   int    `json:"queryCacheSize,omitempty"`
 	NodeCache        string `json:"nodeCache,omitempty"`
 	Channel          string `json:"channel"`
 	SecureSuites     string `json:"secureSuites"`
@@ -103,6 +110,14 @@ type ChainPruneParam struct {
 	Height int64  `json:"height"`
 }
 
+type ChainBackupParam struct {
+	Base string `json:"base,omitempty"`
+}
+
+type ChainGCParam struct {
+	Keep int64 `json:"keep"`
+}
+
 type ConfigureParam struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
@@ -152,6 +167,13 @@ func NewChainConfig(cfg *chain.Config) *ChainConfig {
 		NormalTxPoolSize: cfg.NormalTxPoolSize,
 		PatchTxPoolSize:  cfg.PatchTxPoolSize,
 		MaxBlockTxBytes:  cfg.MaxBlockTxBytes,
+		TxPoolOrder:      cfg.TxPoolOrder,
+		EventIndex:       cfg.EventIndex,
+		TxIndex:          cfg.TxIndex,
+		InternalTx:       cfg.InternalTx,
+		StepDetails:      cfg.StepDetails,
+		ParallelCheck:    cfg.ParallelCheck,
+		QueryCacheSize:   cfg.QueryCacheSize,
 		NodeCache:        cfg.NodeCache,
 		Channel:          cfg.Channel,
 		SecureSuites:     cfg.SecureSuites,
@@ -204,6 +226,11 @@ func (r *Rest) RegisterChainHandlers(g *echo.Group) {
 	g.POST(UrlChainRes+"/import", r.ImportChain, r.ChainInjector)
 	g.POST(UrlChainRes+"/prune", r.PruneChain, r.ChainInjector)
 	g.POST(UrlChainRes+"/backup", r.BackupChain, r.ChainInjector)
+	g.GET(UrlChainRes+"/dbstats", r.GetChainDBStats, r.ChainInjector)
+	g.POST(UrlChainRes+"/compact", r.CompactChain, r.ChainInjector)
+	g.POST(UrlChainRes+"/gc", r.StartChainGC, r.ChainInjector)
+	g.POST(UrlChainRes+"/gc/pause", r.PauseChainGC, r.ChainInjector)
+	g.POST(UrlChainRes+"/gc/resume", r.ResumeChainGC, r.ChainInjector)
 	route := g.GET(UrlChainRes+"/genesis", r.GetChainGenesis, r.ChainInjector)
 	if r.a != nil {
 		r.a.SetSkip(route, false)
@@ -376,13 +403,66 @@ func (r *Rest) PruneChain(ctx echo.Context) error {
 
 func (r *Rest) BackupChain(ctx echo.Context) error {
 	c := ctx.Get("chain").(*Chain)
-	if name, err := r.n.BackupChain(c.CID()); err != nil {
+	param := &ChainBackupParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	if name, err := r.n.BackupChain(c.CID(), param.Base); err != nil {
 		return err
 	} else {
 		return ctx.String(http.StatusOK, name)
 	}
 }
 
+func (r *Rest) GetChainDBStats(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	countKeys, _ := strconv.ParseBool(ctx.QueryParam("keys"))
+	stats, err := r.n.GetChainDBStats(c.CID(), countKeys)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, stats)
+}
+
+func (r *Rest) CompactChain(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	if err := r.n.CompactChainDB(c.CID()); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) StartChainGC(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	param := &ChainGCParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	if param.Keep < 1 {
+		return echo.ErrBadRequest
+	}
+	if err := r.n.StartChainGC(c.CID(), param.Keep); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) PauseChainGC(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	if err := r.n.PauseChainGC(c.CID()); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) ResumeChainGC(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	if err := r.n.ResumeChainGC(c.CID()); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
 func (r *Rest) GetChainGenesis(ctx echo.Context) error {
 	c := ctx.Get("chain").(*Chain)
 	gsFile := path.Join(c.cfg.AbsBaseDir(), ChainGenesisZipFileName)
@@ -410,6 +490,7 @@ func (r *Rest) RegisterSystemHandlers(g *echo.Group) {
 	g.GET("", r.GetSystem)
 	g.GET("/configure", r.GetSystemConfig)
 	g.POST("/configure", r.ConfigureSystem)
+	g.GET("/ee", r.GetEEHealth)
 	r.RegistryBackupHandlers(g.Group("/backup"))
 	r.RegistryRestoreHandlers(g.Group("/restore"))
 }
@@ -449,6 +530,16 @@ func (r *Rest) ConfigureSystem(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, "OK")
 }
 
+func (r *Rest) GetEEHealth(ctx echo.Context) error {
+	v := r.n.pm.Health()
+
+	format := ctx.QueryParam("format")
+	if format != "" {
+		return defaultJsonTemplate.Response(format, v, ctx.Response())
+	}
+	return ctx.JSON(http.StatusOK, v)
+}
+
 func (r *Rest) RegistryBackupHandlers(g *echo.Group) {
 	g.GET("", r.GetBackups)
 }
@@ -74,10 +74,60 @@ type RestoreManager struct {
 	channel   string
 	overwrite bool
 
-	state   RestoreState
-	current int
-	total   int
-	lastErr error
+	state     RestoreState
+	current   int
+	total     int
+	verifying bool
+	lastErr   error
+}
+
+// openBackups opens the backup and its base backups. Base backups are
+// searched in the directory of the backup. It returns readers and manifests
+// in the order of restoration. Manifests of the backups without manifest
+// are nil.
+func openBackups(file string) (zrs []*zip.ReadCloser, mfs []*chain.BackupManifest, ret error) {
+	defer func() {
+		if ret != nil {
+			for _, zr := range zrs {
+				zr.Close()
+			}
+			zrs = nil
+		}
+	}()
+
+	var mf *chain.BackupManifest
+	visited := make(map[string]bool)
+	for name := file; ; {
+		if visited[name] {
+			return zrs, nil, errors.IllegalArgumentError.Errorf(
+				"CyclicBaseBackup(backup=%s)", path.Base(name))
+		}
+		visited[name] = true
+
+		zr, err := zip.OpenReader(name)
+		if err != nil {
+			return zrs, nil, errors.IllegalArgumentError.Wrapf(err,
+				"ZipOpenFailure(backup=%s)", name)
+		}
+		zrs = append([]*zip.ReadCloser{zr}, zrs...)
+
+		m, err := chain.ReadBackupManifest(&zr.Reader)
+		if err != nil {
+			return zrs, nil, errors.IllegalArgumentError.Wrapf(err,
+				"InvalidBackupManifest(backup=%s)", path.Base(name))
+		}
+		if name == file {
+			mf = m
+		} else if m == nil || m.CID != mf.CID || m.NID != mf.NID {
+			return zrs, nil, errors.IllegalArgumentError.Errorf(
+				"IncompatibleBaseBackup(backup=%s)", path.Base(name))
+		}
+		mfs = append([]*chain.BackupManifest{m}, mfs...)
+		if m == nil || len(m.Base) == 0 {
+			return zrs, mfs, nil
+		}
+		name = path.Join(path.Dir(file), m.Base)
+	}
 }
 
 func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrite bool) (ret error) {
@@ -103,18 +153,19 @@ func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrit
 		}
 	}()
 
-	zr, err := zip.OpenReader(file)
+	zrs, mfs, err := openBackups(file)
 	if err != nil {
-		return errors.IllegalArgumentError.Wrapf(err,
-			"ZipOpenFailure(backup=%s)", file)
+		return err
 	}
 	defer func() {
 		if ret != nil {
-			zr.Close()
+			for _, zr := range zrs {
+				zr.Close()
+			}
 		}
 	}()
 
-	info, err := chain.ReadBackupInfo(&zr.Reader)
+	info, err := chain.ReadBackupInfo(&zrs[len(zrs)-1].Reader)
 	if err != nil {
 		return errors.IllegalArgumentError.Wrap(err,
 			"InvalidBackupInfo")
@@ -131,7 +182,7 @@ func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrit
 	}
 
 	go func() {
-		if err := m._restore(node, zr, tmpDir, overwrite); err != nil {
+		if err := m._restore(node, zrs, mfs, tmpDir, overwrite); err != nil {
 			node.logger.Debugf("Restore failed err=%+v", err)
 			if errors.InterruptedError.Equals(err) {
 				m._setState(RestoreNone, nil)
@@ -147,7 +198,11 @@ func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrit
 	m.overwrite = overwrite
 	m.state = RestoreStarted
 	m.current = 0
-	m.total = len(zr.File)
+	m.total = 0
+	m.verifying = false
+	for _, zr := range zrs {
+		m.total += len(zr.File)
+	}
 	return nil
 }
 
@@ -170,10 +225,14 @@ func (m *RestoreManager) GetStatus() *RestoreStatus {
 	case RestoreNone:
 		return nil
 	case RestoreStarted:
+		state := fmt.Sprintf("started %d/%d", m.current, m.total)
+		if m.verifying {
+			state = "verifying"
+		}
 		return &RestoreStatus{
 			File:      m.file,
 			Overwrite: m.overwrite,
-			State:     fmt.Sprintf("started %d/%d", m.current, m.total),
+			State:     state,
 		}
 	default:
 		return &RestoreStatus{
@@ -199,10 +258,24 @@ func (m *RestoreManager) _setStateInLock(s RestoreState, e error) {
 		m.file = ""
 		m.total = 0
 		m.current = 0
+		m.verifying = false
+	}
+}
+
+func (m *RestoreManager) _onVerifying() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.state != RestoreStarted {
+		return errors.ErrInterrupted
 	}
+	m.verifying = true
+	return nil
 }
 
-func zipExtract(file *zip.File, tmpDir string) (ret error) {
+// zipExtract extracts the file to the directory. If overwrite is true, then
+// it overwrites the existing file, otherwise it fails.
+func zipExtract(file *zip.File, tmpDir string, overwrite bool) (ret error) {
 	rc, err := file.Open()
 	if err != nil {
 		return err
@@ -223,8 +296,11 @@ func zipExtract(file *zip.File, tmpDir string) (ret error) {
 		return err
 	}
 
-	fd, err := os.OpenFile(target,
-		os.O_CREATE|os.O_EXCL|os.O_RDWR|os.O_TRUNC, mode.Perm())
+	flags := os.O_CREATE | os.O_RDWR | os.O_TRUNC
+	if !overwrite {
+		flags |= os.O_EXCL
+	}
+	fd, err := os.OpenFile(target, flags, mode.Perm())
 	if err != nil {
 		return err
 	}
@@ -235,26 +311,73 @@ func zipExtract(file *zip.File, tmpDir string) (ret error) {
 	return err
 }
 
-func (m *RestoreManager) _restore(node *Node, zr *zip.ReadCloser, tmpDir string, overwrite bool) (ret error) {
+// _extract extracts the files of the backups in the order. Files of the
+// base backups are overwritten by the later backups, but each backup can't
+// have the same file more than once.
+func (m *RestoreManager) _extract(zrs []*zip.ReadCloser, tmpDir string) error {
+	idx := 0
+	for i, zr := range zrs {
+		extracted := make(map[string]bool)
+		for _, file := range zr.File {
+			if file.Name != chain.BackupManifestFile {
+				overwrite := i > 0 && !extracted[file.Name]
+				extracted[file.Name] = true
+				if err := zipExtract(file, tmpDir, overwrite); err != nil {
+					return err
+				}
+			}
+			if err := m._onRestored(idx); err != nil {
+				return err
+			}
+			idx += 1
+		}
+	}
+	return nil
+}
+
+func (m *RestoreManager) _restore(node *Node, zrs []*zip.ReadCloser, mfs []*chain.BackupManifest, tmpDir string, overwrite bool) (ret error) {
 	defer func() {
 		if ret != nil {
 			os.RemoveAll(tmpDir)
 		}
 	}()
-	defer zr.Close()
-
-	for idx, file := range zr.File {
-		if err := zipExtract(file, tmpDir); err != nil {
-			return err
+	defer func() {
+		for _, zr := range zrs {
+			zr.Close()
 		}
-		if err := m._onRestored(idx); err != nil {
+	}()
+
+	if err := m._extract(zrs, tmpDir); err != nil {
+		return err
+	}
+
+	if mf := mfs[len(mfs)-1]; mf != nil {
+		if err := m._verify(node, mf, mfs[:len(mfs)-1], tmpDir); err != nil {
 			return err
 		}
 	}
-
 	return node.restoreChain(tmpDir, overwrite)
 }
 
+// _verify checks the restored files and the last block of the database
+// with the manifest before the chain is added.
+func (m *RestoreManager) _verify(node *Node, mf *chain.BackupManifest, bases []*chain.BackupManifest, tmpDir string) error {
+	if err := m._onVerifying(); err != nil {
+		return err
+	}
+	if err := mf.VerifyFiles(tmpDir, bases...); err != nil {
+		return errors.InvalidStateError.Wrap(err, "InvalidBackupFiles")
+	}
+	cfg, err := node.loadChainConfig(tmpDir)
+	if err != nil {
+		return err
+	}
+	if err := mf.VerifyDatabase(tmpDir, cfg.DBType, cfg.NID); err != nil {
+		return errors.InvalidStateError.Wrap(err, "InvalidBackupDatabase")
+	}
+	return nil
+}
+
 func (m *RestoreManager) Stop() error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
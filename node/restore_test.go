@@ -0,0 +1,204 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package node
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/chain"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+type restoreTestEntry struct {
+	name    string
+	content string
+}
+
+func restoreTestFileOf(name, content string) chain.BackupFile {
+	sum := sha256.Sum256([]byte(content))
+	return chain.BackupFile{
+		Name:     name,
+		Size:     int64(len(content)),
+		Checksum: sum[:],
+	}
+}
+
+// writeRestoreTestBackup writes the backup with the entries. If mf is not
+// nil, then it's stored as the manifest of the backup.
+func writeRestoreTestBackup(t *testing.T, file string, mf *chain.BackupManifest, entries ...restoreTestEntry) {
+	fd, err := os.Create(file)
+	assert.NoError(t, err)
+	defer fd.Close()
+
+	zw := zip.NewWriter(fd)
+	for _, e := range entries {
+		w, err := zw.Create(e.name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(e.content))
+		assert.NoError(t, err)
+	}
+	if mf != nil {
+		w, err := zw.Create(chain.BackupManifestFile)
+		assert.NoError(t, err)
+		assert.NoError(t, json.NewEncoder(w).Encode(mf))
+	}
+	assert.NoError(t, zw.Close())
+}
+
+func restoreTestManifest(base string, files ...chain.BackupFile) *chain.BackupManifest {
+	return &chain.BackupManifest{
+		BackupInfo: chain.BackupInfo{
+			NID:  common.HexInt32{Value: 1},
+			CID:  common.HexInt32{Value: 1},
+			Base: base,
+		},
+		Files: files,
+	}
+}
+
+func extractRestoreTest(t *testing.T, file, dir string) ([]*chain.BackupManifest, error) {
+	zrs, mfs, err := openBackups(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, zr := range zrs {
+			zr.Close()
+		}
+	}()
+	assert.Equal(t, len(zrs), len(mfs))
+
+	m := &RestoreManager{state: RestoreStarted}
+	return mfs, m._extract(zrs, dir)
+}
+
+func assertRestoredFile(t *testing.T, dir, name, content string) {
+	bs, err := ioutil.ReadFile(path.Join(dir, name))
+	assert.NoError(t, err, name)
+	assert.Equal(t, content, string(bs), name)
+}
+
+func TestRestore_BaseAndIncremental(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	base := path.Join(dir, "base.zip")
+	writeRestoreTestBackup(t, base,
+		restoreTestManifest("",
+			restoreTestFileOf("config.json", "{}"),
+			restoreTestFileOf("db/a", "a"),
+			restoreTestFileOf("db/c", "c"),
+		),
+		restoreTestEntry{"config.json", "{}"},
+		restoreTestEntry{"db/a", "a"},
+		restoreTestEntry{"db/c", "c"},
+	)
+	inc := path.Join(dir, "inc.zip")
+	writeRestoreTestBackup(t, inc,
+		restoreTestManifest("base.zip",
+			restoreTestFileOf("config.json", "{}"),
+			restoreTestFileOf("db/a", "a2"),
+			restoreTestFileOf("db/d", "d"),
+		),
+		restoreTestEntry{"db/a", "a2"},
+		restoreTestEntry{"db/d", "d"},
+	)
+
+	target := path.Join(dir, "target")
+	mfs, err := extractRestoreTest(t, inc, target)
+	assert.NoError(t, err)
+	assert.Len(t, mfs, 2)
+	assert.Equal(t, "base.zip", mfs[1].Base)
+
+	// a file not in the backups is kept
+	assert.NoError(t, ioutil.WriteFile(path.Join(target, "local"), nil, 0644))
+
+	mf := mfs[len(mfs)-1]
+	assert.NoError(t, mf.VerifyFiles(target, mfs[:len(mfs)-1]...))
+	assertRestoredFile(t, target, "config.json", "{}")
+	assertRestoredFile(t, target, "db/a", "a2")
+	assertRestoredFile(t, target, "db/d", "d")
+	assertRestoredFile(t, target, "local", "")
+	_, err = os.Stat(path.Join(target, "db/c"))
+	assert.True(t, os.IsNotExist(err))
+
+	// the base backup only
+	target = path.Join(dir, "target2")
+	mfs, err = extractRestoreTest(t, base, target)
+	assert.NoError(t, err)
+	assert.Len(t, mfs, 1)
+	assert.NoError(t, mfs[0].VerifyFiles(target))
+	assertRestoredFile(t, target, "db/a", "a")
+	assertRestoredFile(t, target, "db/c", "c")
+}
+
+func TestRestore_DuplicateEntries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	base := path.Join(dir, "base.zip")
+	writeRestoreTestBackup(t, base, restoreTestManifest(""),
+		restoreTestEntry{"db/a", "a"},
+		restoreTestEntry{"db/a", "b"},
+	)
+	_, err = extractRestoreTest(t, base, path.Join(dir, "target1"))
+	assert.True(t, os.IsExist(err), err)
+
+	writeRestoreTestBackup(t, base, restoreTestManifest(""),
+		restoreTestEntry{"db/a", "a"},
+	)
+	inc := path.Join(dir, "inc.zip")
+	writeRestoreTestBackup(t, inc, restoreTestManifest("base.zip"),
+		restoreTestEntry{"db/a", "b"},
+		restoreTestEntry{"db/a", "c"},
+	)
+	_, err = extractRestoreTest(t, inc, path.Join(dir, "target2"))
+	assert.True(t, os.IsExist(err), err)
+}
+
+func TestRestore_InvalidBase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restore")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	inc := path.Join(dir, "inc.zip")
+	writeRestoreTestBackup(t, inc, restoreTestManifest("base.zip"))
+	_, err = extractRestoreTest(t, inc, path.Join(dir, "target"))
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	base := path.Join(dir, "base.zip")
+	mf := restoreTestManifest("")
+	mf.NID.Value = 2
+	writeRestoreTestBackup(t, base, mf)
+	_, err = extractRestoreTest(t, inc, path.Join(dir, "target"))
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	writeRestoreTestBackup(t, base, restoreTestManifest("inc.zip"))
+	_, err = extractRestoreTest(t, inc, path.Join(dir, "target"))
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+}
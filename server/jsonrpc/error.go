@@ -159,6 +159,18 @@ func ErrServer(message ...interface{}) *Error {
 	return re
 }
 
+func newErrorResponse(id interface{}, err error) *ErrorResponse {
+	re, ok := err.(*Error)
+	if !ok {
+		re = ErrorCodeServer.Wrap(err, false)
+	}
+	return &ErrorResponse{
+		ID:      id,
+		Version: Version,
+		Error:   re,
+	}
+}
+
 func ErrorHandler(re *Error, c echo.Context) {
 	var res *ErrorResponse
 	status := 0
@@ -170,12 +182,13 @@ func ErrorHandler(re *Error, c echo.Context) {
 		}
 		status = http.StatusBadRequest
 	} else {
-		req := c.Get("request").(*Request)
 		res = &ErrorResponse{
-			ID:      req.ID,
 			Version: Version,
 			Error:   re,
 		}
+		if req, ok := c.Get("request").(*Request); ok {
+			res.ID = req.ID
+		}
 		switch re.Code {
 		case ErrorCodeInvalidRequest, ErrorCodeInvalidParams:
 			status = http.StatusBadRequest
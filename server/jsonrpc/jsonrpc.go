@@ -25,6 +25,24 @@ type Request struct {
 	ID      interface{}     `json:"id"`
 }
 
+// BatchRequest is the list of raw requests of JSON-RPC 2.0 batch call.
+type BatchRequest []json.RawMessage
+
+// IsBatch returns whether the body is a JSON-RPC 2.0 batch call (JSON array).
+func IsBatch(body []byte) bool {
+	for _, b := range body {
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '[':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
 type Response struct {
 	Version string      `json:"jsonrpc"`
 	Result  interface{} `json:"result"`
@@ -132,11 +150,15 @@ func (ctx *Context) IncludeDebug() bool {
 }
 
 func (ctx *Context) GetTimeout(t time.Duration) time.Duration {
-	if v, err := ctx.opts.GetInt(IconOptionsTimeout); err != nil {
-		return t
-	} else {
-		return time.Duration(v) * time.Millisecond
+	if v, err := ctx.opts.GetInt(IconOptionsTimeout); err == nil {
+		t = time.Duration(v) * time.Millisecond
+	}
+	if deadline, ok := ctx.Request().Context().Deadline(); ok {
+		if remain := time.Until(deadline); remain < t {
+			return remain
+		}
 	}
+	return t
 }
 
 type Params struct {
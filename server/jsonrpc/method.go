@@ -1,8 +1,11 @@
 package jsonrpc
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -45,9 +48,7 @@ func (mr *MethodRepository) TakeMethod(r *Request) (Handler, error) {
 	return md, nil
 }
 
-func (mr *MethodRepository) InvokeMethod(c echo.Context, r *Request) (interface{}, error) {
-	h := c.Get("method").(Handler)
-
+func (mr *MethodRepository) invoke(c echo.Context, h Handler, r *Request) (interface{}, error) {
 	ctx := NewContext(c)
 	param := Params{
 		rawMessage: r.Params,
@@ -57,7 +58,15 @@ func (mr *MethodRepository) InvokeMethod(c echo.Context, r *Request) (interface{
 	return h(ctx, &param)
 }
 
+func (mr *MethodRepository) InvokeMethod(c echo.Context, r *Request) (interface{}, error) {
+	h := c.Get("method").(Handler)
+	return mr.invoke(c, h, r)
+}
+
 func (mr *MethodRepository) Handle(c echo.Context) (err error) {
+	if batch, ok := c.Get("batch").(BatchRequest); ok {
+		return mr.HandleBatch(c, batch)
+	}
 	r := c.Get("request").(*Request)
 
 	result, err := mr.InvokeMethod(c, r)
@@ -73,3 +82,71 @@ func (mr *MethodRepository) Handle(c echo.Context) (err error) {
 
 	return c.JSON(http.StatusOK, res)
 }
+
+// HandleBatch invokes the requests of the batch in order and responds with
+// the list of responses. The context of the request has the deadline given
+// by "batchTimeout", so handlers stop waiting on it, and requests which are
+// not started before it are answered with a timeout error. Notifications,
+// requests without "id", are invoked, but they are not answered.
+func (mr *MethodRepository) HandleBatch(c echo.Context, batch BatchRequest) error {
+	if timeout, ok := c.Get("batchTimeout").(time.Duration); ok && timeout > 0 {
+		ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+		defer cancel()
+		c.SetRequest(c.Request().WithContext(ctx))
+	}
+	res := make([]interface{}, 0, len(batch))
+	for _, msg := range batch {
+		if r := mr.handleBatchEntry(c, msg); r != nil {
+			res = append(res, r)
+		}
+	}
+	if len(res) == 0 {
+		return c.NoContent(http.StatusNoContent)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// handleBatchEntry returns the response for the request. It returns nil for
+// the valid notification.
+func (mr *MethodRepository) handleBatchEntry(c echo.Context, msg json.RawMessage) interface{} {
+	r := new(Request)
+	if err := json.Unmarshal(msg, r); err != nil {
+		return newErrorResponse(nil, ErrInvalidRequest())
+	}
+	if err := c.Validate(r); err != nil {
+		return newErrorResponse(r.ID, ErrInvalidRequest())
+	}
+	notification := isNotification(msg)
+	if err := c.Request().Context().Err(); err != nil {
+		if notification {
+			return nil
+		}
+		return newErrorResponse(r.ID, ErrorCodeTimeout.New("BatchTimeout"))
+	}
+	var res interface{}
+	if h, err := mr.TakeMethod(r); err != nil {
+		res = newErrorResponse(r.ID, err)
+	} else if result, err := mr.invoke(c, h, r); err != nil {
+		res = newErrorResponse(r.ID, err)
+	} else {
+		res = &Response{
+			ID:      r.ID,
+			Version: Version,
+			Result:  result,
+		}
+	}
+	if notification {
+		return nil
+	}
+	return res
+}
+
+// isNotification returns whether the request doesn't have "id" member.
+func isNotification(msg json.RawMessage) bool {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &members); err != nil {
+		return false
+	}
+	_, ok := members["id"]
+	return !ok
+}
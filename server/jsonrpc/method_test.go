@@ -3,8 +3,13 @@ package jsonrpc
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -54,3 +59,147 @@ func hello(ctx *Context, params *Params) (result interface{}, err error) {
 	}
 	return "hello, " + param.Name, nil
 }
+
+func TestMethodRepository_HandleBatch(t *testing.T) {
+	mr := NewMethodRepository()
+	mr.RegisterMethod("hello", hello)
+
+	batch := BatchRequest{
+		json.RawMessage(`{"id":1,"jsonrpc":"2.0","method":"hello","params":{"name":"icon"}}`),
+		json.RawMessage(`{"id":2,"jsonrpc":"2.0","method":"unknown"}`),
+		json.RawMessage(`{"id":3,"method":"hello"}`),
+		json.RawMessage(`{"id":4,"jsonrpc":"2.0","method":"hello","params":{"name":"loop"}}`),
+	}
+
+	e := echo.New()
+	e.Validator = NewValidator()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(""))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := mr.HandleBatch(c, batch)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var res []struct {
+		ID     int    `json:"id"`
+		Result string `json:"result"`
+		Error  *Error `json:"error"`
+	}
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	assert.NoError(t, err)
+	assert.Len(t, res, 4)
+
+	assert.Equal(t, 1, res[0].ID)
+	assert.Equal(t, "hello, icon", res[0].Result)
+	assert.Nil(t, res[0].Error)
+
+	assert.Equal(t, 2, res[1].ID)
+	assert.Equal(t, ErrorCodeMethodNotFound, res[1].Error.Code)
+
+	assert.Equal(t, 3, res[2].ID)
+	assert.Equal(t, ErrorCodeInvalidRequest, res[2].Error.Code)
+
+	assert.Equal(t, 4, res[3].ID)
+	assert.Equal(t, "hello, loop", res[3].Result)
+}
+
+func TestMethodRepository_HandleBatchTimeout(t *testing.T) {
+	mr := NewMethodRepository()
+	mr.RegisterMethod("sleep", func(ctx *Context, params *Params) (interface{}, error) {
+		time.Sleep(ctx.GetTimeout(time.Second))
+		return "done", nil
+	})
+	mr.RegisterMethod("wait", func(ctx *Context, params *Params) (interface{}, error) {
+		select {
+		case <-ctx.Request().Context().Done():
+			return nil, ErrorCodeTimeout.New("Canceled")
+		case <-time.After(time.Second):
+			return "done", nil
+		}
+	})
+
+	batch := BatchRequest{
+		json.RawMessage(`{"id":1,"jsonrpc":"2.0","method":"wait"}`),
+		json.RawMessage(`{"id":2,"jsonrpc":"2.0","method":"sleep"}`),
+	}
+
+	e := echo.New()
+	e.Validator = NewValidator()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(""))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("batchTimeout", 100*time.Millisecond)
+
+	start := time.Now()
+	err := mr.HandleBatch(c, batch)
+	assert.NoError(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+
+	var res []struct {
+		ID     int    `json:"id"`
+		Result string `json:"result"`
+		Error  *Error `json:"error"`
+	}
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+
+	assert.Equal(t, 1, res[0].ID)
+	assert.Equal(t, ErrorCodeTimeout, res[0].Error.Code)
+	assert.Equal(t, 2, res[1].ID)
+	assert.Equal(t, ErrorCodeTimeout, res[1].Error.Code)
+}
+
+func TestMethodRepository_HandleBatchNotification(t *testing.T) {
+	mr := NewMethodRepository()
+	var names []string
+	mr.RegisterMethod("hello", func(ctx *Context, params *Params) (interface{}, error) {
+		var param HelloParam
+		if err := params.Convert(&param); err != nil {
+			return nil, ErrInvalidParams()
+		}
+		names = append(names, param.Name)
+		return "hello, " + param.Name, nil
+	})
+
+	e := echo.New()
+	e.Validator = NewValidator()
+
+	batch := BatchRequest{
+		json.RawMessage(`{"jsonrpc":"2.0","method":"hello","params":{"name":"icon"}}`),
+		json.RawMessage(`{"id":null,"jsonrpc":"2.0","method":"hello","params":{"name":"loop"}}`),
+		json.RawMessage(`{"jsonrpc":"2.0","method":"unknown"}`),
+	}
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(""))
+	rec := httptest.NewRecorder()
+	err := mr.HandleBatch(e.NewContext(req, rec), batch)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"icon", "loop"}, names)
+
+	// only the request with null id is answered
+	var res []struct {
+		ID     interface{} `json:"id"`
+		Result string      `json:"result"`
+	}
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Nil(t, res[0].ID)
+	assert.Equal(t, "hello, loop", res[0].Result)
+
+	// nothing is returned for notifications only
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(""))
+	rec = httptest.NewRecorder()
+	err = mr.HandleBatch(e.NewContext(req, rec), batch[:1])
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Body.Bytes())
+}
+
+func TestIsBatch(t *testing.T) {
+	assert.True(t, IsBatch([]byte(` [{"id":1}]`)))
+	assert.True(t, IsBatch([]byte("\n\t[]")))
+	assert.False(t, IsBatch([]byte(`{"id":1}`)))
+	assert.False(t, IsBatch([]byte(``)))
+}
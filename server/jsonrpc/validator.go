@@ -11,6 +11,7 @@ var (
 	scoreAddressRegex = regexp.MustCompile("^cx[0-9a-f]{40}$")
 	hexInt            = regexp.MustCompile("^0x[0-9a-f]+$")
 	hashRegex         = regexp.MustCompile("^0x[0-9a-f]{64}$")
+	binDataRegex      = regexp.MustCompile("^0x([0-9a-f]{2})+$")
 )
 
 type Validator struct {
@@ -30,6 +31,7 @@ func NewValidator() *Validator {
 	v.RegisterValidation("t_addr_score", isScoreAddress)
 	v.RegisterValidation("t_int", isHexInt)
 	v.RegisterValidation("t_hash", isHash)
+	v.RegisterValidation("t_bin_data", isBinData)
 
 	v.RegisterAlias("t_sig", "base64")
 	v.RegisterAlias("t_addr", "t_addr_eoa|t_addr_score")
@@ -72,3 +74,7 @@ func isHexInt(fl validator.FieldLevel) bool {
 func isHash(fl validator.FieldLevel) bool {
 	return hashRegex.MatchString(fl.Field().String())
 }
+
+func isBinData(fl validator.FieldLevel) bool {
+	return binDataRegex.MatchString(fl.Field().String())
+}
@@ -0,0 +1,68 @@
+package metric
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	msEERestart       = stats.Int64("ee_restart", "Restart Execution Engine", stats.UnitDimensionless)
+	msEETimeout       = stats.Int64("ee_timeout", "Timeout of Execution Engine", stats.UnitDimensionless)
+	msEEQueueWait     = stats.Int64("ee_queue_wait", "Wait for Executor", stats.UnitMilliseconds)
+	msEEInvokeLatency = stats.Int64("ee_invoke_latency", "Invoke Latency of Execution Engine", stats.UnitMilliseconds)
+	mkEEType          = NewMetricKey("ee_type")
+	mkEEPriority      = NewMetricKey("ee_priority")
+	eeMks             = []tag.Key{mkEEType}
+	eeQueueMks        = []tag.Key{mkEEPriority}
+)
+
+func RegisterExecutor() {
+	RegisterMetricView(msEERestart, view.Count(), eeMks)
+	RegisterMetricView(msEETimeout, view.Count(), eeMks)
+	RegisterMetricView(msEEQueueWait, view.LastValue(), eeQueueMks)
+	RegisterMetricView(msEEQueueWait, view.Distribution(0, 10, 50, 100, 500, 1000, 5000), eeQueueMks)
+	RegisterMetricView(msEEInvokeLatency, view.LastValue(), eeMks)
+	RegisterMetricView(msEEInvokeLatency, view.Distribution(0, 10, 50, 100, 500, 1000, 5000), eeMks)
+}
+
+// EEMetric records metrics of an execution engine.
+type EEMetric struct {
+	context context.Context
+}
+
+func (c *EEMetric) OnRestart() {
+	stats.Record(c.context, msEERestart.M(1))
+}
+
+func (c *EEMetric) OnTimeout() {
+	stats.Record(c.context, msEETimeout.M(1))
+}
+
+func (c *EEMetric) OnInvoke(d time.Duration) {
+	stats.Record(c.context, msEEInvokeLatency.M(int64(d/time.Millisecond)))
+}
+
+func NewEEMetric(ctx context.Context, t string) *EEMetric {
+	return &EEMetric{
+		context: GetMetricContext(ctx, &mkEEType, t),
+	}
+}
+
+// EEQueueMetric records the time waiting for an executor.
+type EEQueueMetric struct {
+	context context.Context
+}
+
+func (c *EEQueueMetric) OnWait(d time.Duration) {
+	stats.Record(c.context, msEEQueueWait.M(int64(d/time.Millisecond)))
+}
+
+func NewEEQueueMetric(ctx context.Context, priority string) *EEQueueMetric {
+	return &EEQueueMetric{
+		context: GetMetricContext(ctx, &mkEEPriority, priority),
+	}
+}
@@ -170,6 +170,8 @@ func PrometheusExporter() *prometheus.Exporter {
 	RegisterConsensus()
 	RegisterNetwork()
 	RegisterTransaction()
+	RegisterExecutor()
+	RegisterQuery()
 	return pe
 }
 
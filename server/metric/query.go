@@ -0,0 +1,44 @@
+package metric
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	msQueryCacheHit   = stats.Int64("query_cache_hit", "Hit of Query Cache", stats.UnitDimensionless)
+	msQueryCacheMiss  = stats.Int64("query_cache_miss", "Miss of Query Cache", stats.UnitDimensionless)
+	msQueryCacheBytes = stats.Int64("query_cache_bytes", "Bytes of Query Cache", stats.UnitBytes)
+	queryMks          = []tag.Key{}
+)
+
+func RegisterQuery() {
+	RegisterMetricView(msQueryCacheHit, view.Count(), queryMks)
+	RegisterMetricView(msQueryCacheMiss, view.Count(), queryMks)
+	RegisterMetricView(msQueryCacheBytes, view.LastValue(), queryMks)
+}
+
+type QueryCacheMetric struct {
+	context context.Context
+}
+
+func (c *QueryCacheMetric) OnHit() {
+	stats.Record(c.context, msQueryCacheHit.M(1))
+}
+
+func (c *QueryCacheMetric) OnMiss() {
+	stats.Record(c.context, msQueryCacheMiss.M(1))
+}
+
+func (c *QueryCacheMetric) OnBytes(n int) {
+	stats.Record(c.context, msQueryCacheBytes.M(int64(n)))
+}
+
+func NewQueryCacheMetric(ctx context.Context) *QueryCacheMetric {
+	return &QueryCacheMetric{
+		context: ctx,
+	}
+}
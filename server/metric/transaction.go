@@ -24,6 +24,9 @@ var (
 	msDropUserTx    = stats.Int64("txpool_user_drop", "Drop User Transaction", stats.UnitBytes)
 	msFinLatency    = stats.Int64("txlatency_finalize", "Finalize Transaction Latency", stats.UnitMilliseconds)
 	msCommitLatency = stats.Int64("txlatency_commit", "Commit Transaction Latency", stats.UnitMilliseconds)
+	msPositionTx    = stats.Int64("txpool_position", "Position of Added Transaction", stats.UnitDimensionless)
+	msEvictTx       = stats.Int64("txpool_evict", "Evict Transaction", stats.UnitBytes)
+	msReplaceTx     = stats.Int64("txpool_replace", "Replace Transaction", stats.UnitBytes)
 	mkTxType        = NewMetricKey("tx_type")
 	txPoolMks       = []tag.Key{mkTxType}
 )
@@ -43,6 +46,12 @@ func RegisterTransaction() {
 	RegisterMetricView(msDropUserTx, view.Sum(), txPoolMks)
 	RegisterMetricView(msFinLatency, view.LastValue(), txPoolMks)
 	RegisterMetricView(msCommitLatency, view.LastValue(), txPoolMks)
+	RegisterMetricView(msPositionTx, view.LastValue(), txPoolMks)
+	RegisterMetricView(msPositionTx, view.Distribution(0, 10, 100, 500, 1000, 2000, 5000), txPoolMks)
+	RegisterMetricView(msEvictTx, view.Count(), txPoolMks)
+	RegisterMetricView(msEvictTx, view.Sum(), txPoolMks)
+	RegisterMetricView(msReplaceTx, view.Count(), txPoolMks)
+	RegisterMetricView(msReplaceTx, view.Sum(), txPoolMks)
 }
 
 type commitRecord struct {
@@ -77,6 +86,18 @@ func (c *TxMetric) OnDropTx(n int, user bool) {
 	}
 }
 
+func (c *TxMetric) OnPositionTx(pos int) {
+	stats.Record(c.context, msPositionTx.M(int64(pos)))
+}
+
+func (c *TxMetric) OnEvictTx(n int, replaced bool) {
+	if replaced {
+		stats.Record(c.context, msReplaceTx.M(int64(n)))
+	} else {
+		stats.Record(c.context, msEvictTx.M(int64(n)))
+	}
+}
+
 func (c *TxMetric) OnFinalize(hash []byte, ts time.Time) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
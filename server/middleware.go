@@ -2,6 +2,7 @@ package server
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -20,6 +21,28 @@ func JsonRpc(mr *jsonrpc.MethodRepository) echo.MiddlewareFunc {
 			if strings.HasPrefix(ctype, echo.MIMETextPlain) {
 				c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 			}
+			body, err := ioutil.ReadAll(c.Request().Body)
+			if err != nil {
+				return jsonrpc.ErrParse()
+			}
+			c.Request().Body = ioutil.NopCloser(bytes.NewReader(body))
+			c.Request().ContentLength = int64(len(body))
+			if jsonrpc.IsBatch(body) {
+				var batch jsonrpc.BatchRequest
+				if err := json.Unmarshal(body, &batch); err != nil {
+					return jsonrpc.ErrParse()
+				}
+				if len(batch) == 0 {
+					return jsonrpc.ErrInvalidRequest("empty batch")
+				}
+				limit, _ := c.Get("batchLimit").(int)
+				if len(batch) > limit {
+					return jsonrpc.ErrInvalidRequest(
+						fmt.Sprintf("batch size(%d) exceeds limit(%d)", len(batch), limit))
+				}
+				c.Set("batch", batch)
+				return next(c)
+			}
 			r := new(jsonrpc.Request)
 			if err := c.Bind(r); err != nil {
 				return jsonrpc.ErrParse()
@@ -23,6 +23,11 @@ const (
 	UrlAdmin          = "/admin"
 )
 
+const (
+	DefaultJsonRpcBatchLimit   = 100
+	DefaultJsonRpcBatchTimeout = 10 * time.Second
+)
+
 type Manager struct {
 	e                     *echo.Echo
 	addr                  string
@@ -33,6 +38,8 @@ type Manager struct {
 	jsonrpcDefaultChannel string
 	jsonrpcMessageDump    int32
 	jsonrpcIncludeDebug   int32
+	jsonrpcBatchLimit     int32
+	jsonrpcBatchTimeout   int64
 	logger                log.Logger
 }
 
@@ -67,6 +74,8 @@ func NewManager(addr string,
 	}
 	m.SetMessageDump(jsonrpcDump)
 	m.SetIncludeDebug(jsonrpcIncludeDebug)
+	m.SetBatchLimit(DefaultJsonRpcBatchLimit)
+	m.SetBatchTimeout(DefaultJsonRpcBatchTimeout)
 	return m
 }
 
@@ -147,6 +156,26 @@ func (srv *Manager) IncludeDebug() bool {
 	return atomicLoad(&srv.jsonrpcIncludeDebug)
 }
 
+// SetBatchLimit sets the maximum number of requests in a JSON-RPC batch call.
+// Batch calls are not allowed if limit is zero.
+func (srv *Manager) SetBatchLimit(limit int) {
+	atomic.StoreInt32(&srv.jsonrpcBatchLimit, int32(limit))
+}
+
+func (srv *Manager) BatchLimit() int {
+	return int(atomic.LoadInt32(&srv.jsonrpcBatchLimit))
+}
+
+// SetBatchTimeout sets the execution deadline of a JSON-RPC batch call.
+// There is no deadline if timeout is zero.
+func (srv *Manager) SetBatchTimeout(timeout time.Duration) {
+	atomic.StoreInt64(&srv.jsonrpcBatchTimeout, int64(timeout))
+}
+
+func (srv *Manager) BatchTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&srv.jsonrpcBatchTimeout))
+}
+
 func (srv *Manager) Start() error {
 	srv.logger.Infoln("starting the server")
 	// middleware
@@ -171,6 +200,8 @@ func (srv *Manager) Start() error {
 	g.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
 			ctx.Set("includeDebug", srv.IncludeDebug())
+			ctx.Set("batchLimit", srv.BatchLimit())
+			ctx.Set("batchTimeout", srv.BatchTimeout())
 			return next(ctx)
 		}
 	})
@@ -189,6 +220,7 @@ func (srv *Manager) Start() error {
 	// websocket
 	srv.e.GET("/api/v3/:channel/block", srv.wssm.RunBlockSession, ChainInjector(srv))
 	srv.e.GET("/api/v3/:channel/event", srv.wssm.RunEventSession, ChainInjector(srv))
+	srv.e.GET("/api/v3/:channel/ws", srv.wssm.RunMuxSession, ChainInjector(srv))
 
 	// metric
 	srv.e.GET("/metrics", echo.WrapHandler(metric.PrometheusExporter()))
@@ -13,14 +13,21 @@ import (
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
 	"github.com/icon-project/goloop/service"
 	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/transitionresult"
+	"github.com/icon-project/goloop/service/txresult"
 )
 
 const (
-	ConfigShowPatchTransaction = false
+	ConfigShowPatchTransaction   = false
+	ConfigMaxPendingTransactions = 1000
+
+	ConfigTraceTimeout      = 5 * time.Second
+	ConfigTraceBlockTimeout = 30 * time.Second
 )
 
 func MethodRepository() *jsonrpc.MethodRepository {
@@ -44,6 +51,13 @@ func MethodRepository() *jsonrpc.MethodRepository {
 	mr.RegisterMethod("icx_getVotesByHeight", getVotesByHeight)
 	mr.RegisterMethod("icx_getProofForResult", getProofForResult)
 	mr.RegisterMethod("icx_getProofForEvents", getProofForEvents)
+	mr.RegisterMethod("icx_getProofForAccount", getProofForAccount)
+	mr.RegisterMethod("icx_getProofForStorage", getProofForStorage)
+	mr.RegisterMethod("icx_getPendingTransactions", getPendingTransactions)
+	mr.RegisterMethod("icx_getTransactionPoolStatus", getTransactionPoolStatus)
+	mr.RegisterMethod("icx_getLogs", getLogs)
+	mr.RegisterMethod("icx_getTransactionsByAddress", getTransactionsByAddress)
+	mr.RegisterMethod("icx_getInternalTransactions", getInternalTransactions)
 
 	return mr
 }
@@ -174,6 +188,43 @@ func getBlockByHash(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{},
 	return blockJson, nil
 }
 
+// getBlockForQuery returns the block whose state is used for the query.
+// Block.Result() of the block at height h is the state after executing the
+// transactions in the block at height h-1.
+func getBlockForQuery(bm module.BlockManager, param *StateQueryParam, debug bool) (module.Block, error) {
+	var blk module.Block
+	var err error
+	if param.BlockHash != "" {
+		blk, err = bm.GetBlock(param.BlockHash.Bytes())
+		if err == nil && param.Height != "" && blk.Height() != param.Height.Value() {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"HeightMismatch(height=%d,block=%d)", param.Height.Value(), blk.Height())
+		}
+	} else if param.Height != "" {
+		var height int64
+		if height, err = param.Height.ParseInt(64); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		blk, err = bm.GetBlockByHeight(height)
+	} else {
+		blk, err = bm.GetLastBlock()
+	}
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	return blk, nil
+}
+
+// queryError converts the error of the query on the state.
+func queryError(err error, debug bool) error {
+	if service.PrunedStateError.Equals(err) {
+		return jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	}
+	return jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+}
+
 func call(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
 
@@ -193,7 +244,10 @@ func call(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
 	}
 
-	block, err := bm.GetLastBlock()
+	block, err := getBlockForQuery(bm, &param.StateQueryParam, debug)
+	if err != nil {
+		return nil, err
+	}
 	result, err := sm.Call(block.Result(), block.NextValidators(), params.RawMessage(), block)
 	if err != nil {
 		if service.InvalidQueryError.Equals(err) {
@@ -201,7 +255,7 @@ func call(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 		} else if scoreresult.IsValid(err) {
 			return nil, jsonrpc.ErrScore(err, debug)
 		} else {
-			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+			return nil, queryError(err, debug)
 		}
 	} else {
 		return result, nil
@@ -227,13 +281,13 @@ func getBalance(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, erro
 	}
 
 	var balance common.HexInt
-	block, err := bm.GetLastBlock()
+	block, err := getBlockForQuery(bm, &param.StateQueryParam, debug)
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		return nil, err
 	}
 	b, err := sm.GetBalance(block.Result(), param.Address.Address())
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		return nil, queryError(err, debug)
 	}
 	balance.Set(b)
 	return &balance, nil
@@ -254,16 +308,16 @@ func getScoreApi(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, err
 	if bm == nil || sm == nil {
 		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
 	}
-	b, err := bm.GetLastBlock()
+	b, err := getBlockForQuery(bm, &param.StateQueryParam, debug)
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		return nil, err
 	}
 	info, err := sm.GetAPIInfo(b.Result(), param.Address.Address())
 	if service.NoActiveContractError.Equals(err) {
 		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
 	}
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		return nil, queryError(err, debug)
 	}
 	if jso, err := info.ToJSON(module.JSONVersion3); err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
@@ -272,8 +326,14 @@ func getScoreApi(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, err
 	}
 }
 
-func getTotalSupply(ctx *jsonrpc.Context, _ *jsonrpc.Params) (interface{}, error) {
+func getTotalSupply(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	debug := ctx.IncludeDebug()
+	var param StateQueryParam
+	if !params.IsEmpty() {
+		if err := params.Convert(&param); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+	}
 	chain, err := ctx.Chain()
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
@@ -284,15 +344,15 @@ func getTotalSupply(ctx *jsonrpc.Context, _ *jsonrpc.Params) (interface{}, error
 		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
 	}
 
-	b, err := bm.GetLastBlock()
+	b, err := getBlockForQuery(bm, &param, debug)
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		return nil, err
 	}
 
 	var tsValue common.HexInt
 	ts, err := sm.GetTotalSupply(b.Result())
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		return nil, queryError(err, debug)
 	}
 	tsValue.Set(ts)
 
@@ -346,6 +406,15 @@ func getTransactionResult(ctx *jsonrpc.Context, params *jsonrpc.Params) (interfa
 	result["txIndex"] = "0x" + strconv.FormatInt(int64(txInfo.Index()), 16)
 	result["txHash"] = "0x" + hex.EncodeToString(txInfo.Transaction().ID())
 
+	details, err := sm.GetStepUsedDetails(blk.Height(), param.Hash.Bytes())
+	if err != nil && !errors.UnsupportedError.Equals(err) &&
+		!errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	if details != nil {
+		result["stepUsedDetails"] = txresult.StepUsedDetails(details).ToJSON()
+	}
+
 	return result, nil
 }
 
@@ -632,6 +701,208 @@ func getProofForEvents(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{
 	return proofs, nil
 }
 
+func proofToJSON(proof [][]byte) []common.HexBytes {
+	res := make([]common.HexBytes, len(proof))
+	for i, p := range proof {
+		res[i] = p
+	}
+	return res
+}
+
+// proofResultOf returns common fields of the results of account and
+// storage proofs, which are used to verify the proofs.
+func proofResultOf(b module.Block) (map[string]interface{}, error) {
+	stateHash, err := transitionresult.StateHashOf(b.Result())
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"height":    common.HexInt64{Value: b.Height()},
+		"blockHash": common.HexBytes(b.ID()),
+		"stateHash": common.HexBytes(stateHash),
+	}, nil
+}
+
+func getProofForAccount(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param ProofAccountParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	b, err := getBlockForQuery(bm, &param.StateQueryParam, debug)
+	if err != nil {
+		return nil, err
+	}
+	addr := param.Address.Address()
+	account, proof, err := sm.GetProofForAccount(b.Result(), addr)
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, queryError(err, debug)
+	}
+
+	result, err := proofResultOf(b)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	result["address"] = addr
+	result["account"] = common.HexBytes(account)
+	result["proof"] = proofToJSON(proof)
+	return result, nil
+}
+
+func getProofForStorage(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param ProofStorageParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	b, err := getBlockForQuery(bm, &param.StateQueryParam, debug)
+	if err != nil {
+		return nil, err
+	}
+	addr := param.Address.Address()
+	account, accountProof, err := sm.GetProofForAccount(b.Result(), addr)
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, queryError(err, debug)
+	}
+	key := param.Key.Bytes()
+	value, proof, err := sm.GetProofForStorage(b.Result(), addr, key)
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, queryError(err, debug)
+	}
+
+	result, err := proofResultOf(b)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	result["address"] = addr
+	result["account"] = common.HexBytes(account)
+	result["accountProof"] = proofToJSON(accountProof)
+	result["key"] = common.HexBytes(key)
+	result["value"] = common.HexBytes(value)
+	result["proof"] = proofToJSON(proof)
+	return result, nil
+}
+
+func getPendingTransactions(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param PendingTransactionsParam
+	if !params.IsEmpty() {
+		if err := params.Convert(&param); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	sm := chain.ServiceManager()
+	if sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	limit := ConfigMaxPendingTransactions
+	if param.Limit != "" {
+		value, err := param.Limit.ParseInt(32)
+		if err != nil || value <= 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidLimit(limit=%s)", param.Limit)
+		}
+		if int(value) < limit {
+			limit = int(value)
+		}
+	}
+	var from, to module.Address
+	if param.FromAddress != "" {
+		from = param.FromAddress.Address()
+	}
+	if param.ToAddress != "" {
+		to = param.ToAddress.Address()
+	}
+
+	txs := sm.GetPendingTransactions(from, to, limit)
+	list := make([]interface{}, 0, len(txs))
+	for _, tx := range txs {
+		if tx.Group() == module.TransactionGroupPatch && !ConfigShowPatchTransaction {
+			continue
+		}
+		res, err := tx.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		list = append(list, res)
+	}
+	return list, nil
+}
+
+func getTransactionPoolStatus(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	sm := chain.ServiceManager()
+	if sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	result := make(map[string]interface{})
+	for _, status := range sm.GetTransactionPoolStatus() {
+		drops := make(map[string]interface{}, len(status.Drops))
+		for reason, count := range status.Drops {
+			drops[reason] = intconv.FormatInt(int64(count))
+		}
+		jso := map[string]interface{}{
+			"size":  intconv.FormatInt(int64(status.Size)),
+			"used":  intconv.FormatInt(int64(status.Used)),
+			"drops": drops,
+		}
+		switch status.Group {
+		case module.TransactionGroupPatch:
+			result["patch"] = jso
+		case module.TransactionGroupNormal:
+			result["normal"] = jso
+		}
+	}
+	return result, nil
+}
+
 // convert TransactionList to []Transaction
 func convertTransactionList(txs module.TransactionList, version module.JSONVersion) ([]interface{}, error) {
 	list := []interface{}{}
@@ -747,29 +1018,9 @@ func waitTransactionResultOnChannel(ctx *jsonrpc.Context, bm module.BlockManager
 ) (interface{}, error) {
 	tc := time.After(timeout)
 
-	var err error
-	var txInfo module.TransactionInfo
-	var receipt module.Receipt
 	select {
 	case result := <-fc:
-		switch ro := result.(type) {
-		case error:
-			return nil, jsonrpc.ErrorCodeSystem.Wrap(ro, debug)
-		case module.TransactionInfo:
-			txInfo = ro
-			receipt, err = txInfo.GetReceipt()
-			if err != nil {
-				return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
-			}
-		case module.Receipt:
-			txInfo, err = bm.GetTransactionInfo(id)
-			if err != nil {
-				return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
-			}
-			receipt = ro
-		default:
-			return nil, jsonrpc.ErrorCodeSystem.New("Unknown resulting object")
-		}
+		return TransactionResultOf(bm, id, result, debug)
 	case <-tc:
 		if maxLimit {
 			return nil, jsonrpc.ErrorCodeSystemTimeout.NewWithData(
@@ -784,6 +1035,33 @@ func waitTransactionResultOnChannel(ctx *jsonrpc.Context, bm module.BlockManager
 	case <-ctx.Request().Context().Done():
 		return nil, nil
 	}
+}
+
+// TransactionResultOf returns the result of the transaction for the object
+// received from the channel returned by WaitTransactionResult of
+// module.BlockManager.
+func TransactionResultOf(bm module.BlockManager, id []byte, obj interface{}, debug bool) (interface{}, error) {
+	var err error
+	var txInfo module.TransactionInfo
+	var receipt module.Receipt
+	switch ro := obj.(type) {
+	case error:
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(ro, debug)
+	case module.TransactionInfo:
+		txInfo = ro
+		receipt, err = txInfo.GetReceipt()
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+	case module.Receipt:
+		txInfo, err = bm.GetTransactionInfo(id)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		receipt = ro
+	default:
+		return nil, jsonrpc.ErrorCodeSystem.New("Unknown resulting object")
+	}
 
 	res, err := receipt.ToJSON(module.JSONVersion3)
 	if err != nil {
@@ -803,7 +1081,11 @@ func DebugMethodRepository() *jsonrpc.MethodRepository {
 	mr := jsonrpc.NewMethodRepository()
 
 	mr.RegisterMethod("debug_getTrace", getTrace)
+	mr.RegisterMethod("debug_traceTransaction", traceTransaction)
+	mr.RegisterMethod("debug_traceBlock", traceBlock)
 	mr.RegisterMethod("debug_estimateStep", estimateStep)
+	mr.RegisterMethod("debug_estimateStepDetails", estimateStepDetails)
+	mr.RegisterMethod("debug_simulateTransactions", simulateTransactions)
 
 	return mr
 }
@@ -876,9 +1158,31 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
 	}
 
-	txInfo, err := bm.GetTransactionInfo(param.Hash.Bytes())
+	txInfo, err := transactionInfoForTrace(bm, sm, param.Hash.Bytes(), debug)
+	if err != nil {
+		return nil, err
+	}
+
+	cb := &traceCallback{
+		logs:    make([]interface{}, 0, 100),
+		channel: make(chan interface{}, 10),
+	}
+	err = executeForTrace(bm, sm, txInfo.Block(), txInfo.Index(), cb, cb.channel,
+		ConfigTraceTimeout, debug)
+	if err != nil {
+		return nil, err
+	}
+	return cb.result(), nil
+}
+
+// transactionInfoForTrace returns the information of the normal transaction
+// having the result.
+func transactionInfoForTrace(bm module.BlockManager, sm module.ServiceManager,
+	id []byte, debug bool,
+) (module.TransactionInfo, error) {
+	txInfo, err := bm.GetTransactionInfo(id)
 	if errors.NotFoundError.Equals(err) {
-		if sm.HasTransaction(param.Hash.Bytes()) {
+		if sm.HasTransaction(id) {
 			return nil, jsonrpc.ErrorCodePending.New("Pending")
 		}
 		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
@@ -890,55 +1194,84 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 		return nil, jsonrpc.ErrorCodeInvalidParams.New("Patch transaction can't be replayed")
 	}
 
-	blk := txInfo.Block()
 	_, err = txInfo.GetReceipt()
 	if block.ResultNotFinalizedError.Equals(err) {
 		return nil, jsonrpc.ErrorCodeExecuting.New("Executing")
 	} else if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
+	return txInfo, nil
+}
+
+// executeForTrace replays normal transactions of the block for the callback,
+// and waits for the end of the execution. All transactions are traced if
+// index is negative.
+func executeForTrace(bm module.BlockManager, sm module.ServiceManager,
+	blk module.Block, index int, cb module.TraceCallback,
+	done <-chan interface{}, timeout time.Duration, debug bool,
+) error {
 	nblk, err := bm.GetBlockByHeight(blk.Height() + 1)
-	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	if errors.NotFoundError.Equals(err) {
+		return jsonrpc.ErrorCodeExecuting.New("Executing")
+	} else if err != nil {
+		return jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
 	tr1, err := sm.CreateInitialTransition(blk.Result(), blk.NextValidators())
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		return queryError(err, debug)
 	}
 	tr2, err := sm.CreateTransition(tr1, blk.NormalTransactions(), blk)
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		return queryError(err, debug)
 	}
 	tr2 = sm.PatchTransition(tr2, nblk.PatchTransactions(), nblk)
 
-	cb := &traceCallback{
-		logs:    make([]interface{}, 0, 100),
-		channel: make(chan interface{}, 10),
-	}
 	canceller, err := tr2.ExecuteForTrace(module.TraceInfo{
-		Group:    txInfo.Group(),
-		Index:    txInfo.Index(),
+		Group:    module.TransactionGroupNormal,
+		Index:    index,
 		Callback: cb,
 	})
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		return jsonrpc.ErrorCodeSystem.Wrap(err, debug)
 	}
 
-	timer := time.After(time.Second * 5)
-	for {
-		select {
-		case <-timer:
-			canceller()
-			return nil, jsonrpc.ErrorCodeSystemTimeout.Errorf(
-				"Not enough time to get result of %x", param.Hash.Bytes())
-		case <-cb.channel:
-			return cb.result(), nil
-		}
+	select {
+	case <-time.After(timeout):
+		canceller()
+		return jsonrpc.ErrorCodeSystemTimeout.Errorf(
+			"Not enough time to trace block %#x", blk.ID())
+	case <-done:
+		return nil
 	}
-	return nil, jsonrpc.ErrorCodeSystem.New("Unknown error on channel")
 }
 
 func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	rct, err := executeForEstimate(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	steps := new(common.HexInt)
+	steps.Set(rct.StepUsed())
+	return steps, nil
+}
+
+func estimateStepDetails(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	rct, err := executeForEstimate(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{
+		"stepUsed": intconv.FormatBigInt(rct.StepUsed()),
+	}
+	if r, ok := rct.(txresult.Receipt); ok {
+		result["stepUsedDetails"] = r.StepUsedDetails().ToJSON()
+	}
+	return result, nil
+}
+
+// executeForEstimate executes the transaction on the state of the last block
+// and returns the receipt if it succeeds.
+func executeForEstimate(ctx *jsonrpc.Context, params *jsonrpc.Params) (module.Receipt, error) {
 	debug := ctx.IncludeDebug()
 
 	chain, err := ctx.Chain()
@@ -979,12 +1312,13 @@ func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, er
 		bi,
 	)
 	if err != nil {
+		if service.InvalidQueryError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
 		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
 	}
 	if status := rct.Status(); status != module.StatusSuccess {
 		return nil, jsonrpc.ErrScoreWithStatus(status)
 	}
-	steps := new(common.HexInt)
-	steps.Set(rct.StepUsed())
-	return steps, nil
+	return rct, nil
 }
@@ -0,0 +1,158 @@
+package v3
+
+import (
+	"encoding/hex"
+
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/service/trace"
+)
+
+// callTracer collects call frames of transactions in addition to the logs.
+type callTracer struct {
+	traceCallback
+	trace.CallTree
+}
+
+func newCallTracer() *callTracer {
+	return &callTracer{
+		traceCallback: traceCallback{
+			channel: make(chan interface{}, 10),
+		},
+	}
+}
+
+// error returns the error of the execution.
+func (t *callTracer) error() error {
+	t.traceCallback.lock.Lock()
+	defer t.traceCallback.lock.Unlock()
+
+	return t.last
+}
+
+func (t *callTracer) transactionsToJSON(blk module.Block) ([]interface{}, error) {
+	txs := t.Transactions()
+	result := make([]interface{}, 0, len(txs))
+	for _, tx := range txs {
+		jso, err := transactionTraceToJSON(blk, tx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, jso)
+	}
+	return result, nil
+}
+
+func transactionTraceToJSON(blk module.Block, ttx *trace.Transaction) (interface{}, error) {
+	tx, err := blk.NormalTransactions().Get(ttx.Index)
+	if err != nil {
+		return nil, err
+	}
+	jso := map[string]interface{}{
+		"txHash":  "0x" + hex.EncodeToString(tx.ID()),
+		"txIndex": intconv.FormatInt(int64(ttx.Index)),
+	}
+	if ttx.Frame != nil {
+		frame, err := ttx.Frame.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return nil, err
+		}
+		jso["frame"] = frame
+	}
+	return jso, nil
+}
+
+func traceTransaction(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param TransactionHashParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	txInfo, err := transactionInfoForTrace(bm, sm, param.Hash.Bytes(), debug)
+	if err != nil {
+		return nil, err
+	}
+
+	blk := txInfo.Block()
+	cb := newCallTracer()
+	err = executeForTrace(bm, sm, blk, txInfo.Index(), cb, cb.channel,
+		ConfigTraceTimeout, debug)
+	if err != nil {
+		return nil, err
+	}
+	if err := cb.error(); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	txs := cb.Transactions()
+	if len(txs) == 0 {
+		return nil, jsonrpc.ErrorCodeSystem.New("NoTrace")
+	}
+	result, err := transactionTraceToJSON(blk, txs[len(txs)-1])
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	return result, nil
+}
+
+func traceBlock(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param StateQueryParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	if param.Height == "" && param.BlockHash == "" {
+		return nil, jsonrpc.ErrorCodeInvalidParams.New("NoHeightOrBlockHash")
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	blk, err := getBlockForQuery(bm, &param, debug)
+	if err != nil {
+		return nil, err
+	}
+
+	cb := newCallTracer()
+	err = executeForTrace(bm, sm, blk, -1, cb, cb.channel,
+		ConfigTraceBlockTimeout, debug)
+	if err != nil {
+		return nil, err
+	}
+	if err := cb.error(); err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	txs, err := cb.transactionsToJSON(blk)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	return map[string]interface{}{
+		"blockHeight":  intconv.FormatInt(blk.Height()),
+		"blockHash":    "0x" + hex.EncodeToString(blk.ID()),
+		"transactions": txs,
+	}, nil
+}
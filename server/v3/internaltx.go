@@ -0,0 +1,75 @@
+package v3
+
+import (
+	"encoding/hex"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+func getInternalTransactions(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param TransactionHashParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	txInfo, err := bm.GetTransactionInfo(param.Hash.Bytes())
+	if errors.NotFoundError.Equals(err) {
+		if sm.HasTransaction(param.Hash.Bytes()) {
+			return nil, jsonrpc.ErrorCodePending.New("Pending")
+		}
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	if txInfo.Group() != module.TransactionGroupNormal {
+		return nil, jsonrpc.ErrorCodeNotFound.New("NotNormalTransaction")
+	}
+
+	// internal transactions are stored along with the receipt
+	if _, err := txInfo.GetReceipt(); block.ResultNotFinalizedError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeExecuting.New("Executing")
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	blk := txInfo.Block()
+	itxs, err := sm.GetInternalTransactions(blk.Height(), param.Hash.Bytes())
+	if errors.UnsupportedError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	} else if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	list := make([]interface{}, 0, len(itxs))
+	for _, itx := range itxs {
+		jso, err := itx.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		list = append(list, jso)
+	}
+	return map[string]interface{}{
+		"txHash":               "0x" + hex.EncodeToString(param.Hash.Bytes()),
+		"blockHeight":          intconv.FormatInt(blk.Height()),
+		"internalTransactions": list,
+	}, nil
+}
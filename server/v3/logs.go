@@ -0,0 +1,428 @@
+package v3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const (
+	ConfigMaxLogs = 1000
+
+	// ConfigMaxLogsRange is the maximum number of blocks for a query with
+	// the event index, and ConfigMaxLogsScanRange is the one without it.
+	ConfigMaxLogsRange     = 1000000
+	ConfigMaxLogsScanRange = 1000
+)
+
+// logFilter selects event logs by the address, the signature and the
+// arguments of the event. Nil arguments match any value.
+type logFilter struct {
+	addr    module.Address
+	sig     []byte
+	indexed [][]byte
+	data    [][]byte
+	lb      *txresult.LogsBloom
+}
+
+func newLogFilter(param *LogsParam) (*logFilter, error) {
+	name, pts := txresult.DecomposeEventSignature(param.Signature)
+	if len(name) == 0 || pts == nil || len(pts) < len(param.Indexed)+len(param.Data) {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidSignature(sig=%s)", param.Signature)
+	}
+	f := &logFilter{
+		sig: []byte(param.Signature),
+		lb:  txresult.NewLogsBloom(nil),
+	}
+	if param.Address != "" {
+		f.addr = param.Address.Address()
+		f.lb.AddAddressOfLog(f.addr)
+	}
+	f.lb.AddIndexedOfLog(0, f.sig)
+	f.indexed = make([][]byte, len(param.Indexed))
+	for i, arg := range param.Indexed {
+		if arg == nil {
+			continue
+		}
+		bs, err := txresult.EventDataStringToBytesByType(pts[i], *arg)
+		if err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err,
+				"InvalidIndexed(idx=%d,value=%s)", i, *arg)
+		}
+		f.indexed[i] = bs
+		f.lb.AddIndexedOfLog(i+1, bs)
+	}
+	f.data = make([][]byte, len(param.Data))
+	for i, arg := range param.Data {
+		if arg == nil {
+			continue
+		}
+		bs, err := txresult.EventDataStringToBytesByType(pts[len(param.Indexed)+i], *arg)
+		if err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err,
+				"InvalidData(idx=%d,value=%s)", i, *arg)
+		}
+		f.data[i] = bs
+	}
+	return f, nil
+}
+
+// term returns the most specific term of the event index for the filter.
+func (f *logFilter) term() []byte {
+	for i, arg := range f.indexed {
+		if arg != nil {
+			return service.EventTermOf(f.addr, f.sig, i+1, arg)
+		}
+	}
+	return service.EventTermOf(f.addr, f.sig, 0, nil)
+}
+
+func (f *logFilter) match(ev module.EventLog) bool {
+	indexed := ev.Indexed()
+	if len(indexed) == 0 || !bytes.Equal(indexed[0], f.sig) {
+		return false
+	}
+	if f.addr != nil && !f.addr.Equal(ev.Address()) {
+		return false
+	}
+	for i, arg := range f.indexed {
+		if arg != nil && (i+1 >= len(indexed) || !bytes.Equal(arg, indexed[i+1])) {
+			return false
+		}
+	}
+	data := ev.Data()
+	for i, arg := range f.data {
+		if arg != nil && (i >= len(data) || !bytes.Equal(arg, data[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+const cursorSize = 16
+
+func cursorOf(loc *module.EventLocation) string {
+	bs := make([]byte, cursorSize)
+	binary.BigEndian.PutUint64(bs[0:8], uint64(loc.Height))
+	binary.BigEndian.PutUint32(bs[8:12], uint32(loc.TxIndex))
+	binary.BigEndian.PutUint32(bs[12:16], uint32(loc.EventIndex))
+	return "0x" + hex.EncodeToString(bs)
+}
+
+func locationOfCursor(bs []byte) (*module.EventLocation, error) {
+	if len(bs) != cursorSize {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidCursor(cursor=%#x)", bs)
+	}
+	return &module.EventLocation{
+		Height:     int64(binary.BigEndian.Uint64(bs[0:8])),
+		TxIndex:    int(binary.BigEndian.Uint32(bs[8:12])),
+		EventIndex: int(binary.BigEndian.Uint32(bs[12:16])),
+	}, nil
+}
+
+func isAfter(loc, after *module.EventLocation) bool {
+	if loc.Height != after.Height {
+		return loc.Height > after.Height
+	}
+	if loc.TxIndex != after.TxIndex {
+		return loc.TxIndex > after.TxIndex
+	}
+	return loc.EventIndex > after.EventIndex
+}
+
+// logReader reads event logs of the blocks. Receipts of transactions in the
+// block are in the result of the next block.
+type logReader struct {
+	bm       module.BlockManager
+	sm       module.ServiceManager
+	blocks   map[int64]module.Block
+	receipts map[int64]module.ReceiptList
+}
+
+func newLogReader(bm module.BlockManager, sm module.ServiceManager) *logReader {
+	return &logReader{
+		bm:       bm,
+		sm:       sm,
+		blocks:   make(map[int64]module.Block),
+		receipts: make(map[int64]module.ReceiptList),
+	}
+}
+
+func (r *logReader) block(height int64) (module.Block, error) {
+	if blk, ok := r.blocks[height]; ok {
+		return blk, nil
+	}
+	blk, err := r.bm.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	r.blocks[height] = blk
+	return blk, nil
+}
+
+func (r *logReader) receiptList(height int64) (module.ReceiptList, error) {
+	if rl, ok := r.receipts[height]; ok {
+		return rl, nil
+	}
+	blk, err := r.block(height + 1)
+	if err != nil {
+		return nil, err
+	}
+	rl, err := r.sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
+	if err != nil {
+		return nil, err
+	}
+	r.receipts[height] = rl
+	return rl, nil
+}
+
+func (r *logReader) event(loc *module.EventLocation) (module.EventLog, error) {
+	rl, err := r.receiptList(loc.Height)
+	if err != nil {
+		return nil, err
+	}
+	rct, err := rl.Get(loc.TxIndex)
+	if err != nil {
+		return nil, err
+	}
+	idx := 0
+	for it := rct.EventLogIterator(); it.Has(); it.Next() {
+		if idx == loc.EventIndex {
+			return it.Get()
+		}
+		idx++
+	}
+	return nil, errors.NotFoundError.Errorf(
+		"EventNotFound(height=%d,tx=%d,event=%d)", loc.Height, loc.TxIndex, loc.EventIndex)
+}
+
+func (r *logReader) toJSON(loc *module.EventLocation, ev module.EventLog) (interface{}, error) {
+	jso, err := txresult.EventLogToJSON(ev, module.JSONVersion3)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := r.block(loc.Height)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := blk.NormalTransactions().Get(loc.TxIndex)
+	if err != nil {
+		return nil, err
+	}
+	jso["blockHeight"] = intconv.FormatInt(loc.Height)
+	jso["blockHash"] = "0x" + hex.EncodeToString(blk.ID())
+	jso["txIndex"] = intconv.FormatInt(int64(loc.TxIndex))
+	jso["txHash"] = "0x" + hex.EncodeToString(tx.ID())
+	jso["logIndex"] = intconv.FormatInt(int64(loc.EventIndex))
+	return jso, nil
+}
+
+// findWithIndex finds logs by the event index. It returns the location of
+// the last candidate for the next page if there might be more logs.
+func (r *logReader) findWithIndex(f *logFilter, from, to int64, after *module.EventLocation, limit int) ([]interface{}, *module.EventLocation, error) {
+	locs, err := r.sm.FindEvents(f.term(), from, to, after, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	logs := make([]interface{}, 0, len(locs))
+	for i := range locs {
+		loc := &locs[i]
+		ev, err := r.event(loc)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !f.match(ev) {
+			continue
+		}
+		jso, err := r.toJSON(loc, ev)
+		if err != nil {
+			return nil, nil, err
+		}
+		logs = append(logs, jso)
+	}
+	if len(locs) >= limit {
+		return logs, &locs[len(locs)-1], nil
+	}
+	return logs, nil, nil
+}
+
+// scan finds logs by checking all receipts of the blocks having matching
+// logs bloom.
+func (r *logReader) scan(f *logFilter, from, to int64, after *module.EventLocation, limit int) ([]interface{}, *module.EventLocation, error) {
+	logs := make([]interface{}, 0)
+	if after != nil {
+		from = after.Height
+	}
+	for h := from; h <= to; h++ {
+		next, err := r.block(h + 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !next.LogsBloom().Contain(f.lb) {
+			continue
+		}
+		rl, err := r.receiptList(h)
+		if err != nil {
+			return nil, nil, err
+		}
+		txIndex := 0
+		for it := rl.Iterator(); it.Has(); it.Next() {
+			rct, err := it.Get()
+			if err != nil {
+				return nil, nil, err
+			}
+			eventIndex := 0
+			for eit := rct.EventLogIterator(); eit.Has(); eit.Next() {
+				loc := &module.EventLocation{
+					Height:     h,
+					TxIndex:    txIndex,
+					EventIndex: eventIndex,
+				}
+				eventIndex++
+				if after != nil && !isAfter(loc, after) {
+					continue
+				}
+				ev, err := eit.Get()
+				if err != nil {
+					return nil, nil, err
+				}
+				if !f.match(ev) {
+					continue
+				}
+				jso, err := r.toJSON(loc, ev)
+				if err != nil {
+					return nil, nil, err
+				}
+				logs = append(logs, jso)
+				if len(logs) >= limit {
+					return logs, loc, nil
+				}
+			}
+			txIndex++
+		}
+	}
+	return logs, nil, nil
+}
+
+func getLogs(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param LogsParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	filter, err := newLogFilter(&param)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	from, err := param.FromHeight.ParseInt(64)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	// receipts of the last block are not in the chain yet.
+	last, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	to := last.Height() - 1
+	if param.ToHeight != "" {
+		value, err := param.ToHeight.ParseInt(64)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		if value < from {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidRange(from=%d,to=%d)", from, value)
+		}
+		if value < to {
+			to = value
+		}
+	}
+	if gh := chain.GenesisStorage().Height(); from < gh {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"InvalidFromHeight(from=%d,genesis=%d)", from, gh)
+	}
+
+	limit := ConfigMaxLogs
+	if param.Limit != "" {
+		value, err := param.Limit.ParseInt(32)
+		if err != nil || value <= 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidLimit(limit=%s)", param.Limit)
+		}
+		if int(value) < limit {
+			limit = int(value)
+		}
+	}
+
+	var after *module.EventLocation
+	if param.Cursor != "" {
+		if after, err = locationOfCursor(param.Cursor.Bytes()); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		if after.Height < from {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidCursor(height=%d,from=%d)", after.Height, from)
+		}
+	}
+
+	result := map[string]interface{}{
+		"logs": []interface{}{},
+	}
+	if from > to {
+		return result, nil
+	}
+
+	indexed := false
+	if start, err := sm.GetEventIndexStart(); err == nil {
+		indexed = start <= from
+	}
+	maxRange := int64(ConfigMaxLogsScanRange)
+	if indexed {
+		maxRange = ConfigMaxLogsRange
+	}
+	if to-from+1 > maxRange {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"TooLargeRange(from=%d,to=%d,max=%d)", from, to, maxRange)
+	}
+
+	reader := newLogReader(bm, sm)
+	var logs []interface{}
+	var next *module.EventLocation
+	if indexed {
+		logs, next, err = reader.findWithIndex(filter, from, to, after, limit)
+	} else {
+		logs, next, err = reader.scan(filter, from, to, after, limit)
+	}
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	result["logs"] = logs
+	if next != nil {
+		result["next"] = cursorOf(next)
+	}
+	return result, nil
+}
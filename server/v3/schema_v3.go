@@ -21,19 +21,43 @@ type BlockHashParam struct {
 	Hash jsonrpc.HexBytes `json:"hash" validate:"required,t_hash"`
 }
 
+// StateQueryParam selects the block for the query on the world state.
+// If both of them are omitted, the last block is used. The state of the block
+// is the result of the transactions in the previous block, so it doesn't
+// include the changes made by the transactions in the block.
+type StateQueryParam struct {
+	Height    jsonrpc.HexInt   `json:"height,omitempty" validate:"optional,t_int"`
+	BlockHash jsonrpc.HexBytes `json:"blockHash,omitempty" validate:"optional,t_hash"`
+}
+
 type CallParam struct {
 	FromAddress jsonrpc.Address `json:"from" validate:"optional,t_addr_eoa"`
 	ToAddress   jsonrpc.Address `json:"to" validate:"required,t_addr_score"`
 	DataType    string          `json:"dataType" validate:"required,call"`
 	Data        interface{}     `json:"data"`
+	StateQueryParam
+
+	StateOverride map[string]*AccountOverrideParam `json:"stateOverride,omitempty" validate:"optional,dive,keys,t_addr,endkeys"`
+}
+
+// AccountOverrideParam replaces the state of the account for the call.
+// Storage maps raw keys of the storage to the values, and null value
+// deletes the entry.
+type AccountOverrideParam struct {
+	Balance     jsonrpc.HexInt               `json:"balance,omitempty" validate:"optional,t_int"`
+	Storage     map[string]*jsonrpc.HexBytes `json:"storage,omitempty" validate:"optional,dive,keys,t_bin_data,endkeys"`
+	Code        jsonrpc.HexBytes             `json:"code,omitempty" validate:"optional,t_bin_data"`
+	ContentType string                       `json:"contentType,omitempty"`
 }
 
 type AddressParam struct {
 	Address jsonrpc.Address `json:"address" validate:"required,t_addr"`
+	StateQueryParam
 }
 
 type ScoreAddressParam struct {
 	Address jsonrpc.Address `json:"address" validate:"required,t_addr_score"`
+	StateQueryParam
 }
 
 type TransactionHashParam struct {
@@ -48,8 +72,15 @@ type TransactionParamForEstimate struct {
 	Timestamp   jsonrpc.HexInt  `json:"timestamp" validate:"required,t_int"`
 	NetworkID   jsonrpc.HexInt  `json:"nid" validate:"required,t_int"`
 	Nonce       jsonrpc.HexInt  `json:"nonce,omitempty" validate:"optional,t_int"`
-	DataType    string          `json:"dataType,omitempty" validate:"optional,call|deploy|message"`
+	DataType    string          `json:"dataType,omitempty" validate:"optional,call|deploy|message|deposit"`
 	Data        interface{}     `json:"data,omitempty"`
+
+	StateOverride map[string]*AccountOverrideParam `json:"stateOverride,omitempty" validate:"optional,dive,keys,t_addr,endkeys"`
+}
+
+type SimulateTransactionsParam struct {
+	Transactions []*TransactionParamForEstimate `json:"transactions" validate:"gt=0,dive"`
+	StateQueryParam
 }
 
 type TransactionParam struct {
@@ -62,7 +93,7 @@ type TransactionParam struct {
 	NetworkID   jsonrpc.HexInt  `json:"nid" validate:"required,t_int"`
 	Nonce       jsonrpc.HexInt  `json:"nonce,omitempty" validate:"optional,t_int"`
 	Signature   string          `json:"signature" validate:"required,t_sig"`
-	DataType    string          `json:"dataType,omitempty" validate:"optional,call|deploy|message"`
+	DataType    string          `json:"dataType,omitempty" validate:"optional,call|deploy|message|deposit"`
 	Data        interface{}     `json:"data,omitempty"`
 }
 
@@ -80,3 +111,37 @@ type ProofEventsParam struct {
 	Index     jsonrpc.HexInt   `json:"index" validate:"required,t_int"`
 	Events    []jsonrpc.HexInt `json:"events" validate:"gt=0,dive,t_int"`
 }
+
+type ProofAccountParam struct {
+	Address jsonrpc.Address `json:"address" validate:"required,t_addr"`
+	StateQueryParam
+}
+
+type ProofStorageParam struct {
+	Address jsonrpc.Address  `json:"address" validate:"required,t_addr_score"`
+	Key     jsonrpc.HexBytes `json:"key" validate:"required,t_bin_data"`
+	StateQueryParam
+}
+
+type PendingTransactionsParam struct {
+	FromAddress jsonrpc.Address `json:"from,omitempty" validate:"optional,t_addr_eoa"`
+	ToAddress   jsonrpc.Address `json:"to,omitempty" validate:"optional,t_addr"`
+	Limit       jsonrpc.HexInt  `json:"limit,omitempty" validate:"optional,t_int"`
+}
+
+type LogsParam struct {
+	FromHeight jsonrpc.HexInt   `json:"fromHeight" validate:"required,t_int"`
+	ToHeight   jsonrpc.HexInt   `json:"toHeight,omitempty" validate:"optional,t_int"`
+	Address    jsonrpc.Address  `json:"address,omitempty" validate:"optional,t_addr_score"`
+	Signature  string           `json:"signature" validate:"required"`
+	Indexed    []*string        `json:"indexed,omitempty"`
+	Data       []*string        `json:"data,omitempty"`
+	Limit      jsonrpc.HexInt   `json:"limit,omitempty" validate:"optional,t_int"`
+	Cursor     jsonrpc.HexBytes `json:"cursor,omitempty" validate:"optional,t_bin_data"`
+}
+
+type TransactionsByAddressParam struct {
+	Address jsonrpc.Address  `json:"address" validate:"required,t_addr"`
+	Limit   jsonrpc.HexInt   `json:"limit,omitempty" validate:"optional,t_int"`
+	Cursor  jsonrpc.HexBytes `json:"cursor,omitempty" validate:"optional,t_bin_data"`
+}
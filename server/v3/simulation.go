@@ -0,0 +1,109 @@
+package v3
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const ConfigMaxSimulateTransactions = 100
+
+func balanceChangeToJSON(c *module.BalanceChange) interface{} {
+	return map[string]interface{}{
+		"address": c.Address,
+		"before":  intconv.FormatBigInt(c.Before),
+		"after":   intconv.FormatBigInt(c.After),
+		"delta":   intconv.FormatBigInt(new(big.Int).Sub(c.After, c.Before)),
+	}
+}
+
+func simulateTransactions(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param SimulateTransactionsParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	if len(param.Transactions) > ConfigMaxSimulateTransactions {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"TooManyTransactions(count=%d,max=%d)",
+			len(param.Transactions), ConfigMaxSimulateTransactions)
+	}
+	txs := make([][]byte, len(param.Transactions))
+	for i, tx := range param.Transactions {
+		if tx.StateOverride != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"StateOverrideNotAllowed(index=%d)", i)
+		}
+		js, err := json.Marshal(tx)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		txs[i] = js
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	blk, err := getBlockForQuery(bm, &param.StateQueryParam, debug)
+	if err != nil {
+		return nil, err
+	}
+
+	// new block information based on the block
+	oldTS := blk.Timestamp()
+	newTS := common.UnixMicroFromTime(time.Now())
+	if newTS <= oldTS {
+		newTS = oldTS + 1
+	}
+	bi := common.NewBlockInfo(blk.Height()+1, newTS)
+
+	res, err := sm.SimulateTransactions(blk.Result(),
+		blk.NextValidators().Hash(), txs, bi)
+	if err != nil {
+		if service.InvalidQueryError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		return nil, queryError(err, debug)
+	}
+
+	receipts := make([]interface{}, 0, len(res.Receipts))
+	for i, rct := range res.Receipts {
+		jso, err := rct.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		result := jso.(map[string]interface{})
+		result["txIndex"] = intconv.FormatInt(int64(i))
+		if r, ok := rct.(txresult.Receipt); ok {
+			result["stepUsedDetails"] = r.StepUsedDetails().ToJSON()
+		}
+		receipts = append(receipts, result)
+	}
+	changes := make([]interface{}, 0, len(res.Changes))
+	for i := range res.Changes {
+		changes = append(changes, balanceChangeToJSON(&res.Changes[i]))
+	}
+	return map[string]interface{}{
+		"blockHeight":    intconv.FormatInt(blk.Height()),
+		"blockHash":      "0x" + hex.EncodeToString(blk.ID()),
+		"receipts":       receipts,
+		"balanceChanges": changes,
+	}, nil
+}
@@ -0,0 +1,120 @@
+package v3
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+const ConfigMaxTransactionsByAddress = 100
+
+const txCursorSize = 12
+
+func txCursorOf(loc *module.TransactionLocation) string {
+	bs := make([]byte, txCursorSize)
+	binary.BigEndian.PutUint64(bs[0:8], uint64(loc.Height))
+	binary.BigEndian.PutUint32(bs[8:12], uint32(loc.TxIndex))
+	return "0x" + hex.EncodeToString(bs)
+}
+
+func txLocationOfCursor(bs []byte) (*module.TransactionLocation, error) {
+	if len(bs) != txCursorSize {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidCursor(cursor=%#x)", bs)
+	}
+	return &module.TransactionLocation{
+		Height:  int64(binary.BigEndian.Uint64(bs[0:8])),
+		TxIndex: int(binary.BigEndian.Uint32(bs[8:12])),
+	}, nil
+}
+
+func getTransactionsByAddress(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+
+	var param TransactionsByAddressParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	limit := ConfigMaxTransactionsByAddress
+	if param.Limit != "" {
+		value, err := param.Limit.ParseInt(32)
+		if err != nil || value <= 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidLimit(limit=%s)", param.Limit)
+		}
+		if int(value) < limit {
+			limit = int(value)
+		}
+	}
+
+	var before *module.TransactionLocation
+	if param.Cursor != "" {
+		if before, err = txLocationOfCursor(param.Cursor.Bytes()); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+	}
+
+	start, err := sm.GetTransactionIndexStart()
+	if errors.UnsupportedError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	} else if errors.NotFoundError.Equals(err) {
+		return map[string]interface{}{
+			"transactions": []interface{}{},
+		}, nil
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	locs, err := sm.FindTransactions(param.Address.Address(), before, limit)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+
+	txs := make([]interface{}, 0, len(locs))
+	var blk module.Block
+	for i := range locs {
+		loc := &locs[i]
+		if blk == nil || blk.Height() != loc.Height {
+			if blk, err = bm.GetBlockByHeight(loc.Height); err != nil {
+				return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+			}
+		}
+		tx, err := blk.NormalTransactions().Get(loc.TxIndex)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		res, err := tx.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+		}
+		jso := res.(map[string]interface{})
+		jso["blockHash"] = "0x" + hex.EncodeToString(blk.ID())
+		jso["blockHeight"] = intconv.FormatInt(loc.Height)
+		jso["txIndex"] = intconv.FormatInt(int64(loc.TxIndex))
+		txs = append(txs, jso)
+	}
+
+	result := map[string]interface{}{
+		"startHeight":  intconv.FormatInt(start),
+		"transactions": txs,
+	}
+	if len(locs) >= limit {
+		result["next"] = txCursorOf(&locs[len(locs)-1])
+	}
+	return result, nil
+}
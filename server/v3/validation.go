@@ -10,7 +10,7 @@ import (
 
 var (
 	hexString          = regexp.MustCompile("^0x[0-9a-f]+$")
-	deployContentTypes = []string{"application/zip", "application/java"}
+	deployContentTypes = []string{"application/zip", "application/java", "application/x.score.go"}
 )
 
 func RegisterValidationRule(v *jsonrpc.Validator) {
@@ -18,6 +18,7 @@ func RegisterValidationRule(v *jsonrpc.Validator) {
 	v.RegisterValidation("call", isCall)
 	v.RegisterValidation("deploy", isDeploy)
 	v.RegisterValidation("message", isMessage)
+	v.RegisterValidation("deposit", isDeposit)
 
 	// validate : CallParam.Data, TransactionParam.Data
 	v.RegisterStructValidation(DataParamValidation, CallParam{}, TransactionParam{})
@@ -36,6 +37,10 @@ func isMessage(fl validator.FieldLevel) bool {
 	return fl.Field().String() == "message"
 }
 
+func isDeposit(fl validator.FieldLevel) bool {
+	return fl.Field().String() == "deposit"
+}
+
 func DataParamValidation(sl validator.StructLevel) {
 	switch sl.Current().Interface().(type) {
 	case CallParam:
@@ -69,6 +74,14 @@ func DataParamValidation(sl validator.StructLevel) {
 				} else {
 					sl.ReportError(txParam.Data, "Data", "", "data", "")
 				}
+			case "deposit":
+				if data, ok := txParam.Data.(map[string]interface{}); ok {
+					if _, ok := data["action"].(string); !ok {
+						sl.ReportError(txParam.Data, "Data", "Data", "data.action", "")
+					}
+				} else {
+					sl.ReportError(txParam.Data, "Data", "", "data", "")
+				}
 			}
 		}
 	}
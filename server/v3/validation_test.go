@@ -86,3 +86,28 @@ func TestTransactionParamValidator(t *testing.T) {
 		assert.Fail(t, "validate fail", err.Error())
 	}
 }
+
+func TestStateQueryParamValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	cases := []struct {
+		json  string
+		valid bool
+	}{
+		{`{"address": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31"}`, true},
+		{`{"address": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31", "height": "0x10"}`, true},
+		{`{"address": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31", "blockHash": "0x6e1f5e4d2b0e1a4f1c3b1c8b0e2f7e1b2f6a5d4c3b2a19081726354453627181"}`, true},
+		{`{"address": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31", "height": "10"}`, false},
+		{`{"address": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31", "blockHash": "0x1234"}`, false},
+	}
+	for _, c := range cases {
+		var param AddressParam
+		if err := json.Unmarshal([]byte(c.json), &param); err != nil {
+			assert.Fail(t, "unmarshal fail", err.Error())
+			continue
+		}
+		err := validator.Validate(&param)
+		assert.Equal(t, c.valid, err == nil, c.json)
+	}
+}
@@ -13,7 +13,6 @@ import (
 type BlockRequest struct {
 	Height       common.HexInt64 `json:"height"`
 	EventFilters []*EventFilter  `json:"eventFilters,omitempty"`
-	bn           BlockNotification
 }
 
 type BlockNotification struct {
@@ -56,13 +55,6 @@ func (wm *wsSessionManager) RunBlockSession(ctx echo.Context) error {
 	go readLoop(wss.c, ech)
 
 	var bch <-chan module.Block
-	indexes := make([][]common.HexInt32, len(br.EventFilters))
-	events := make([][][]common.HexInt32, len(br.EventFilters))
-	for i := range br.EventFilters {
-		indexes[i] = make([]common.HexInt32, 0)
-		events[i] = make([][]common.HexInt32, 0)
-	}
-	var rl module.ReceiptList
 loop:
 	for {
 		bch, err = bm.WaitForBlock(h)
@@ -73,49 +65,11 @@ loop:
 		case err = <-ech:
 			break loop
 		case blk := <-bch:
-			br.bn.Height = common.HexInt64{Value: h}
-			br.bn.Hash = blk.ID()
-			if rl != nil {
-				rl = nil
-			}
-			if len(br.bn.Indexes) > 0 {
-				br.bn.Indexes = indexes[:0]
-				br.bn.Events = events[:0]
-				for i := range indexes {
-					indexes[i] = indexes[i][:0]
-				}
-				for i := range events {
-					events[i] = events[i][:0]
-				}
-			}
-			lb := blk.LogsBloom()
-			for i, f := range br.EventFilters {
-				if lb.Contain(f.lb) {
-					if rl == nil {
-						rl, err = sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
-						if err != nil {
-							break loop
-						}
-					}
-					index := int32(0)
-					for rit := rl.Iterator(); rit.Has(); rit.Next() {
-						r, err := rit.Get()
-						if err != nil {
-							break loop
-						}
-						if es, ok := f.match(r); ok {
-							if len(br.bn.Indexes) < 1 {
-								br.bn.Indexes = indexes[:]
-								br.bn.Events = events[:]
-							}
-							br.bn.Indexes[i] = append(br.bn.Indexes[i], common.HexInt32{Value: index})
-							br.bn.Events[i] = append(br.bn.Events[i], es)
-						}
-						index++
-					}
-				}
+			var bn *BlockNotification
+			if bn, err = br.notificationOf(sm, blk); err != nil {
+				break loop
 			}
-			if err = wss.WriteJSON(&br.bn); err != nil {
+			if err = wss.WriteJSON(bn); err != nil {
 				wm.logger.Infof("fail to write json BlockNotification err:%+v\n", err)
 				break loop
 			}
@@ -126,6 +80,55 @@ loop:
 	return nil
 }
 
+// notificationOf returns the notification for the block. Indexes and Events
+// have entries for all filters if any of them matches.
+func (r *BlockRequest) notificationOf(sm module.ServiceManager, blk module.Block) (*BlockNotification, error) {
+	bn := &BlockNotification{
+		Hash:   blk.ID(),
+		Height: common.HexInt64{Value: blk.Height()},
+	}
+	if len(r.EventFilters) == 0 {
+		return bn, nil
+	}
+	indexes := make([][]common.HexInt32, len(r.EventFilters))
+	events := make([][][]common.HexInt32, len(r.EventFilters))
+	matched := false
+	lb := blk.LogsBloom()
+	var rl module.ReceiptList
+	for i, f := range r.EventFilters {
+		indexes[i] = make([]common.HexInt32, 0)
+		events[i] = make([][]common.HexInt32, 0)
+		if !lb.Contain(f.lb) {
+			continue
+		}
+		if rl == nil {
+			var err error
+			rl, err = sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
+			if err != nil {
+				return nil, err
+			}
+		}
+		index := int32(0)
+		for rit := rl.Iterator(); rit.Has(); rit.Next() {
+			rct, err := rit.Get()
+			if err != nil {
+				return nil, err
+			}
+			if es, ok := f.match(rct); ok {
+				indexes[i] = append(indexes[i], common.HexInt32{Value: index})
+				events[i] = append(events[i], es)
+				matched = true
+			}
+			index++
+		}
+	}
+	if matched {
+		bn.Indexes = indexes
+		bn.Events = events
+	}
+	return bn, nil
+}
+
 func (r *BlockRequest) compile() error {
 	for i, f := range r.EventFilters {
 		if err := f.compile(); err != nil {
@@ -81,32 +81,15 @@ loop:
 		case err = <-ech:
 			break loop
 		case blk := <-bch:
-			if !blk.LogsBloom().Contain(er.lb) {
-				h++
-				continue loop
-			}
-			rl, err := sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
-			if err != nil {
+			var ens []*EventNotification
+			if ens, err = er.notificationsOf(sm, blk); err != nil {
 				break loop
 			}
-			index := int32(0)
-			for rit := rl.Iterator(); rit.Has(); rit.Next() {
-				r, err := rit.Get()
-				if err != nil {
+			for _, en := range ens {
+				if err = wss.WriteJSON(en); err != nil {
+					wm.logger.Infof("fail to write json EventNotification err:%+v\n", err)
 					break loop
 				}
-				if es, ok := er.match(r); ok {
-					var en EventNotification
-					en.Height.Value = h
-					en.Hash = blk.ID()
-					en.Index.Value = index
-					en.Events = es
-					if err := wss.WriteJSON(&en); err != nil {
-						wm.logger.Infof("fail to write json EventNotification err:%+v\n", err)
-						break loop
-					}
-				}
-				index++
 			}
 		}
 		h++
@@ -115,6 +98,36 @@ loop:
 	return nil
 }
 
+// notificationsOf returns the notifications for the receipts of the block
+// having matched events.
+func (r *EventRequest) notificationsOf(sm module.ServiceManager, blk module.Block) ([]*EventNotification, error) {
+	if !blk.LogsBloom().Contain(r.lb) {
+		return nil, nil
+	}
+	rl, err := sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
+	if err != nil {
+		return nil, err
+	}
+	var ens []*EventNotification
+	index := int32(0)
+	for rit := rl.Iterator(); rit.Has(); rit.Next() {
+		rct, err := rit.Get()
+		if err != nil {
+			return nil, err
+		}
+		if es, ok := r.match(rct); ok {
+			ens = append(ens, &EventNotification{
+				Hash:   blk.ID(),
+				Height: common.HexInt64{Value: blk.Height()},
+				Index:  common.HexInt32{Value: index},
+				Events: es,
+			})
+		}
+		index++
+	}
+	return ens, nil
+}
+
 func (f *EventFilter) compile() error {
 	lb := txresult.NewLogsBloom(nil)
 	if f.Addr != nil {
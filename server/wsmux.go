@@ -0,0 +1,486 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/v3"
+	"github.com/icon-project/goloop/service"
+)
+
+const (
+	configMaxSubscriptions   = 32
+	configWSSendQueueSize    = 128
+	configWSWriteTimeout     = 30 * time.Second
+	configPendingTxQueueSize = 256
+)
+
+const (
+	SubscriptionTypeBlock              = "block"
+	SubscriptionTypeEvent              = "event"
+	SubscriptionTypeTransactionResult  = "transactionResult"
+	SubscriptionTypePendingTransaction = "pendingTransaction"
+	SubscriptionTypeTransactionPool    = "transactionPool"
+)
+
+type SubscribeParam struct {
+	Type string `json:"type"`
+}
+
+// SubscriptionHeight is the optional start height of block and event
+// subscriptions. It starts from the next block if it's omitted.
+type SubscriptionHeight struct {
+	Height *common.HexInt64 `json:"height,omitempty"`
+}
+
+type TransactionResultSubscribeParam struct {
+	TxHash common.HexBytes `json:"txHash"`
+}
+
+type UnsubscribeParam struct {
+	Subscription common.HexInt64 `json:"subscription"`
+}
+
+type SubscriptionNotification struct {
+	Version string                  `json:"jsonrpc"`
+	Method  string                  `json:"method"`
+	Params  SubscriptionNotifyParam `json:"params"`
+}
+
+type SubscriptionNotifyParam struct {
+	Subscription common.HexInt64 `json:"subscription"`
+	Result       interface{}     `json:"result,omitempty"`
+	Error        *jsonrpc.Error  `json:"error,omitempty"`
+}
+
+// muxSession is a session multiplexing subscriptions over one connection.
+// All messages are written by the single writer through the send queue.
+// Producers of the subscriptions block on the full queue, so notifications
+// are delivered in order without loss, or the subscription ends with the
+// error notification.
+type muxSession struct {
+	*wsSession
+	wm *wsSessionManager
+
+	lock   sync.Mutex
+	nextID int64
+	subs   map[int64]chan struct{}
+
+	sendQ chan interface{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+func (wm *wsSessionManager) RunMuxSession(ctx echo.Context) error {
+	u := Upgrader()
+	c, err := u.Upgrade(ctx.Response(), ctx.Request(), nil)
+	if err != nil {
+		return err
+	}
+
+	chain, err := wm.chain(ctx)
+	if err != nil {
+		c.Close()
+		return err
+	}
+
+	wss := wm.NewSession(c, chain)
+	if wss == nil {
+		_ = c.WriteJSON(&jsonrpc.ErrorResponse{
+			Version: jsonrpc.Version,
+			Error:   jsonrpc.ErrorLackOfResource.New("too many monitor"),
+		})
+		c.Close()
+		return nil
+	}
+	defer wm.StopSession(wss)
+
+	ms := &muxSession{
+		wsSession: wss,
+		wm:        wm,
+		subs:      make(map[int64]chan struct{}),
+		sendQ:     make(chan interface{}, configWSSendQueueSize),
+		done:      make(chan struct{}),
+	}
+	go ms.writeLoop(c)
+	ms.readLoop(c)
+	ms.close()
+	return nil
+}
+
+func (ms *muxSession) close() {
+	ms.once.Do(func() {
+		close(ms.done)
+	})
+}
+
+func (ms *muxSession) writeLoop(c *websocket.Conn) {
+	defer c.Close()
+	for {
+		select {
+		case <-ms.done:
+			return
+		case msg := <-ms.sendQ:
+			_ = c.SetWriteDeadline(time.Now().Add(configWSWriteTimeout))
+			if err := c.WriteJSON(msg); err != nil {
+				ms.wm.logger.Infof("fail to write json err:%+v\n", err)
+				ms.close()
+				return
+			}
+		}
+	}
+}
+
+// send puts the message to the send queue. It waits until the queue has
+// room for it, and it returns false if the session or the subscription
+// is closed.
+func (ms *muxSession) send(cancel <-chan struct{}, msg interface{}) bool {
+	select {
+	case ms.sendQ <- msg:
+		return true
+	case <-cancel:
+		return false
+	case <-ms.done:
+		return false
+	}
+}
+
+func (ms *muxSession) readLoop(c *websocket.Conn) {
+	for {
+		_, msgBS, err := c.ReadMessage()
+		if err != nil {
+			ms.wm.logger.Debugf("%+v\n", err)
+			return
+		}
+		var req jsonrpc.Request
+		if err := json.Unmarshal(msgBS, &req); err != nil {
+			ms.send(nil, &jsonrpc.ErrorResponse{
+				Version: jsonrpc.Version,
+				Error:   jsonrpc.ErrParse(),
+			})
+			continue
+		}
+		result, err := ms.handle(&req)
+		if err != nil {
+			je, ok := err.(*jsonrpc.Error)
+			if !ok {
+				je = jsonrpc.ErrorCodeServer.Wrap(err, false)
+			}
+			ms.send(nil, &jsonrpc.ErrorResponse{
+				Version: jsonrpc.Version,
+				Error:   je,
+				ID:      req.ID,
+			})
+			continue
+		}
+		ms.send(nil, &jsonrpc.Response{
+			Version: jsonrpc.Version,
+			Result:  result,
+			ID:      req.ID,
+		})
+	}
+}
+
+func (ms *muxSession) handle(req *jsonrpc.Request) (interface{}, error) {
+	if req.Version != jsonrpc.Version {
+		return nil, jsonrpc.ErrInvalidRequest()
+	}
+	switch req.Method {
+	case "icx_subscribe":
+		id, err := ms.subscribe(req.Params)
+		if err != nil {
+			return nil, err
+		}
+		return common.HexInt64{Value: id}, nil
+	case "icx_unsubscribe":
+		var param UnsubscribeParam
+		if err := json.Unmarshal(req.Params, &param); err != nil {
+			return nil, jsonrpc.ErrInvalidParams(err.Error())
+		}
+		return ms.remove(param.Subscription.Value), nil
+	default:
+		return nil, jsonrpc.ErrMethodNotFound()
+	}
+}
+
+func (ms *muxSession) subscribe(params json.RawMessage) (int64, error) {
+	var param SubscribeParam
+	if err := json.Unmarshal(params, &param); err != nil {
+		return 0, jsonrpc.ErrInvalidParams(err.Error())
+	}
+	bm := ms.chain.BlockManager()
+	sm := ms.chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return 0, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+	// subscriptions are added only by the reader, so the limit can be
+	// checked before the subscription is prepared.
+	ms.lock.Lock()
+	count := len(ms.subs)
+	ms.lock.Unlock()
+	if count >= configMaxSubscriptions {
+		return 0, jsonrpc.ErrorLackOfResource.Errorf(
+			"too many subscriptions(max=%d)", configMaxSubscriptions)
+	}
+
+	var run func(id int64, cancel <-chan struct{})
+	switch param.Type {
+	case SubscriptionTypeBlock:
+		var br BlockRequest
+		h, err := ms.parseHeightRequest(params, &br)
+		if err != nil {
+			return 0, err
+		}
+		if err := br.compile(); err != nil {
+			return 0, jsonrpc.ErrInvalidParams(err.Error())
+		}
+		run = func(id int64, cancel <-chan struct{}) {
+			ms.runBlocks(id, cancel, bm, h, func(blk module.Block) ([]interface{}, error) {
+				bn, err := br.notificationOf(sm, blk)
+				if err != nil {
+					return nil, err
+				}
+				return []interface{}{bn}, nil
+			})
+		}
+	case SubscriptionTypeEvent:
+		var er EventRequest
+		h, err := ms.parseHeightRequest(params, &er)
+		if err != nil {
+			return 0, err
+		}
+		if err := er.compile(); err != nil {
+			return 0, jsonrpc.ErrInvalidParams(err.Error())
+		}
+		run = func(id int64, cancel <-chan struct{}) {
+			ms.runBlocks(id, cancel, bm, h, func(blk module.Block) ([]interface{}, error) {
+				ens, err := er.notificationsOf(sm, blk)
+				if err != nil {
+					return nil, err
+				}
+				res := make([]interface{}, len(ens))
+				for i, en := range ens {
+					res[i] = en
+				}
+				return res, nil
+			})
+		}
+	case SubscriptionTypeTransactionResult:
+		var tp TransactionResultSubscribeParam
+		if err := json.Unmarshal(params, &tp); err != nil {
+			return 0, jsonrpc.ErrInvalidParams(err.Error())
+		}
+		if len(tp.TxHash) != 32 {
+			return 0, jsonrpc.ErrInvalidParams("invalid txHash")
+		}
+		fc, err := bm.WaitTransactionResult(tp.TxHash)
+		if err != nil {
+			return 0, jsonrpc.ErrorCodeNotFound.Wrap(err, false)
+		}
+		run = func(id int64, cancel <-chan struct{}) {
+			ms.runTransactionResult(id, cancel, bm, tp.TxHash, fc)
+		}
+	case SubscriptionTypePendingTransaction, SubscriptionTypeTransactionPool:
+		ech, unsubscribe := sm.SubscribeTransactionPool(configPendingTxQueueSize)
+		addedOnly := param.Type == SubscriptionTypePendingTransaction
+		run = func(id int64, cancel <-chan struct{}) {
+			defer unsubscribe()
+			ms.runTransactionPool(id, cancel, ech, addedOnly)
+		}
+	default:
+		return 0, jsonrpc.ErrInvalidParams(
+			fmt.Sprintf("unknown subscription type(%q)", param.Type))
+	}
+
+	ms.lock.Lock()
+	ms.nextID++
+	id := ms.nextID
+	cancel := make(chan struct{})
+	ms.subs[id] = cancel
+	ms.lock.Unlock()
+
+	go func() {
+		run(id, cancel)
+		ms.remove(id)
+	}()
+	return id, nil
+}
+
+func (ms *muxSession) parseHeightRequest(params json.RawMessage, req interface{}) (int64, error) {
+	if err := json.Unmarshal(params, req); err != nil {
+		return 0, jsonrpc.ErrInvalidParams(err.Error())
+	}
+	var sh SubscriptionHeight
+	if err := json.Unmarshal(params, &sh); err != nil {
+		return 0, jsonrpc.ErrInvalidParams(err.Error())
+	}
+	if sh.Height == nil {
+		blk, err := ms.chain.BlockManager().GetLastBlock()
+		if err != nil {
+			return 0, jsonrpc.ErrorCodeServer.Wrap(err, false)
+		}
+		return blk.Height() + 1, nil
+	}
+	h := sh.Height.Value
+	if gh := ms.chain.GenesisStorage().Height(); gh > h {
+		return 0, jsonrpc.ErrInvalidParams(
+			fmt.Sprintf("given height(%d) is lower than genesis height(%d)", h, gh))
+	}
+	return h, nil
+}
+
+// remove cancels the subscription, and returns true if it exists.
+func (ms *muxSession) remove(id int64) bool {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	cancel, ok := ms.subs[id]
+	if !ok {
+		return false
+	}
+	close(cancel)
+	delete(ms.subs, id)
+	return true
+}
+
+func (ms *muxSession) notify(id int64, cancel <-chan struct{}, result interface{}) bool {
+	return ms.send(cancel, &SubscriptionNotification{
+		Version: jsonrpc.Version,
+		Method:  "icx_subscription",
+		Params: SubscriptionNotifyParam{
+			Subscription: common.HexInt64{Value: id},
+			Result:       result,
+		},
+	})
+}
+
+// notifyError notifies the end of the subscription with the error.
+func (ms *muxSession) notifyError(id int64, cancel <-chan struct{}, err error) {
+	je, ok := err.(*jsonrpc.Error)
+	if !ok {
+		je = jsonrpc.ErrorCodeServer.Wrap(err, false)
+	}
+	ms.send(cancel, &SubscriptionNotification{
+		Version: jsonrpc.Version,
+		Method:  "icx_subscription",
+		Params: SubscriptionNotifyParam{
+			Subscription: common.HexInt64{Value: id},
+			Error:        je,
+		},
+	})
+}
+
+func (ms *muxSession) runBlocks(id int64, cancel <-chan struct{}, bm module.BlockManager, h int64,
+	notificationsOf func(blk module.Block) ([]interface{}, error),
+) {
+	for {
+		bch, err := bm.WaitForBlock(h)
+		if err != nil {
+			ms.notifyError(id, cancel, err)
+			return
+		}
+		select {
+		case <-cancel:
+			return
+		case <-ms.done:
+			return
+		case blk := <-bch:
+			ns, err := notificationsOf(blk)
+			if err != nil {
+				ms.notifyError(id, cancel, err)
+				return
+			}
+			for _, n := range ns {
+				if !ms.notify(id, cancel, n) {
+					return
+				}
+			}
+		}
+		h++
+	}
+}
+
+func (ms *muxSession) runTransactionResult(id int64, cancel <-chan struct{}, bm module.BlockManager,
+	txHash []byte, fc <-chan interface{},
+) {
+	select {
+	case <-cancel:
+	case <-ms.done:
+	case obj := <-fc:
+		result, err := v3.TransactionResultOf(bm, txHash, obj, false)
+		if err != nil {
+			ms.notifyError(id, cancel, err)
+			return
+		}
+		ms.notify(id, cancel, result)
+	}
+}
+
+var poolEventTypes = map[module.TransactionPoolEventType]string{
+	module.TransactionAdded:   "added",
+	module.TransactionRemoved: "removed",
+	module.TransactionDropped: "dropped",
+}
+
+// poolEventToJSON returns the notification for the event of the pool. Only
+// the transaction is notified for pendingTransaction subscriptions.
+func poolEventToJSON(ev *module.TransactionPoolEvent, addedOnly bool) (interface{}, error) {
+	var tx interface{}
+	if ev.Transaction != nil {
+		var err error
+		if tx, err = ev.Transaction.ToJSON(module.JSONVersion3); err != nil {
+			return nil, err
+		}
+	}
+	if addedOnly {
+		return tx, nil
+	}
+	jso := map[string]interface{}{
+		"type":   poolEventTypes[ev.Type],
+		"txHash": common.HexBytes(ev.ID),
+	}
+	if tx != nil {
+		jso["transaction"] = tx
+	}
+	if ev.Err != nil {
+		jso["reason"] = service.DropReasonOf(ev.Err)
+		jso["message"] = ev.Err.Error()
+	}
+	return jso, nil
+}
+
+func (ms *muxSession) runTransactionPool(id int64, cancel <-chan struct{},
+	ech <-chan *module.TransactionPoolEvent, addedOnly bool,
+) {
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-ms.done:
+			return
+		case ev, ok := <-ech:
+			if !ok {
+				return
+			}
+			if addedOnly && ev.Type != module.TransactionAdded {
+				continue
+			}
+			js, err := poolEventToJSON(ev, addedOnly)
+			if err != nil {
+				ms.wm.logger.Warnf("fail to make JSON for tx=%#x err=%+v", ev.ID, err)
+				continue
+			}
+			if !ms.notify(id, cancel, js) {
+				return
+			}
+		}
+	}
+}
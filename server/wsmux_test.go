@@ -0,0 +1,214 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+type muxTestBlock struct {
+	module.Block
+	height int64
+}
+
+func (b *muxTestBlock) Height() int64 {
+	return b.height
+}
+
+type muxTestBlockManager struct {
+	module.BlockManager
+}
+
+func (bm *muxTestBlockManager) GetLastBlock() (module.Block, error) {
+	return &muxTestBlock{height: 10}, nil
+}
+
+func (bm *muxTestBlockManager) WaitForBlock(height int64) (<-chan module.Block, error) {
+	return make(chan module.Block), nil
+}
+
+type muxTestServiceManager struct {
+	module.ServiceManager
+	events       chan *module.TransactionPoolEvent
+	unsubscribed chan struct{}
+}
+
+func (sm *muxTestServiceManager) SubscribeTransactionPool(size int) (<-chan *module.TransactionPoolEvent, func()) {
+	return sm.events, func() {
+		close(sm.unsubscribed)
+	}
+}
+
+type muxTestChain struct {
+	module.Chain
+	bm *muxTestBlockManager
+	sm *muxTestServiceManager
+}
+
+func (c *muxTestChain) BlockManager() module.BlockManager {
+	return c.bm
+}
+
+func (c *muxTestChain) ServiceManager() module.ServiceManager {
+	return c.sm
+}
+
+// newMuxTestSession returns the session without the connection. Messages
+// for the client are left in the send queue, so the test reads them from
+// the queue as the client.
+func newMuxTestSession() (*muxSession, *muxTestServiceManager) {
+	sm := &muxTestServiceManager{
+		events:       make(chan *module.TransactionPoolEvent),
+		unsubscribed: make(chan struct{}),
+	}
+	c := &muxTestChain{bm: &muxTestBlockManager{}, sm: sm}
+	ms := &muxSession{
+		wsSession: &wsSession{chain: c},
+		wm:        newWSSessionManager(log.New()),
+		subs:      make(map[int64]chan struct{}),
+		sendQ:     make(chan interface{}, configWSSendQueueSize),
+		done:      make(chan struct{}),
+	}
+	return ms, sm
+}
+
+func muxTestRequest(method string, params interface{}) *jsonrpc.Request {
+	bs, err := json.Marshal(params)
+	if err != nil {
+		panic(err)
+	}
+	return &jsonrpc.Request{
+		Version: jsonrpc.Version,
+		Method:  method,
+		Params:  bs,
+		ID:      1,
+	}
+}
+
+func muxTestSubscribe(ms *muxSession, typ string) (interface{}, error) {
+	return ms.handle(muxTestRequest("icx_subscribe", &SubscribeParam{Type: typ}))
+}
+
+func muxTestUnsubscribe(ms *muxSession, id int64) (interface{}, error) {
+	return ms.handle(muxTestRequest("icx_unsubscribe", &UnsubscribeParam{
+		Subscription: common.HexInt64{Value: id},
+	}))
+}
+
+func TestMuxSession_Subscribe(t *testing.T) {
+	ms, _ := newMuxTestSession()
+	defer ms.close()
+
+	res, err := muxTestSubscribe(ms, SubscriptionTypeBlock)
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexInt64{Value: 1}, res)
+	res, err = muxTestSubscribe(ms, SubscriptionTypeTransactionPool)
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexInt64{Value: 2}, res)
+
+	// an event subscription requires the signature
+	_, err = muxTestSubscribe(ms, SubscriptionTypeEvent)
+	assert.Equal(t, jsonrpc.ErrorCodeInvalidParams, err.(*jsonrpc.Error).Code)
+	_, err = muxTestSubscribe(ms, "unknown")
+	assert.Equal(t, jsonrpc.ErrorCodeInvalidParams, err.(*jsonrpc.Error).Code)
+	_, err = ms.handle(muxTestRequest("icx_unknown", nil))
+	assert.Equal(t, jsonrpc.ErrorCodeMethodNotFound, err.(*jsonrpc.Error).Code)
+
+	res, err = muxTestUnsubscribe(ms, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, true, res)
+	res, err = muxTestUnsubscribe(ms, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, false, res)
+	res, err = muxTestUnsubscribe(ms, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, false, res)
+
+	ms.lock.Lock()
+	_, ok := ms.subs[2]
+	assert.Len(t, ms.subs, 1)
+	ms.lock.Unlock()
+	assert.True(t, ok)
+}
+
+func TestMuxSession_SubscriptionLimit(t *testing.T) {
+	ms, _ := newMuxTestSession()
+	defer ms.close()
+
+	for i := 0; i < configMaxSubscriptions; i++ {
+		_, err := muxTestSubscribe(ms, SubscriptionTypeBlock)
+		assert.NoError(t, err)
+	}
+	_, err := muxTestSubscribe(ms, SubscriptionTypeBlock)
+	assert.Equal(t, jsonrpc.ErrorLackOfResource, err.(*jsonrpc.Error).Code)
+
+	// a subscription is allowed after removing one
+	res, err := muxTestUnsubscribe(ms, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, true, res)
+	res, err = muxTestSubscribe(ms, SubscriptionTypeBlock)
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexInt64{Value: configMaxSubscriptions + 1}, res)
+}
+
+func TestMuxSession_SlowReader(t *testing.T) {
+	ms, sm := newMuxTestSession()
+	defer ms.close()
+
+	res, err := muxTestSubscribe(ms, SubscriptionTypeTransactionPool)
+	assert.NoError(t, err)
+	id := res.(common.HexInt64).Value
+
+	n := configWSSendQueueSize * 2
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+		for i := 0; i < n; i++ {
+			sm.events <- &module.TransactionPoolEvent{
+				Type: module.TransactionAdded,
+				ID:   []byte(fmt.Sprint(i)),
+			}
+		}
+	}()
+
+	// the producer waits for the client while the send queue is full
+	for i := 0; len(ms.sendQ) < cap(ms.sendQ); i++ {
+		if i > 100 {
+			assert.FailNow(t, "send queue isn't filled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case <-sent:
+		assert.FailNow(t, "events are sent without the client")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// all events are notified in order
+	for i := 0; i < n; i++ {
+		msg := (<-ms.sendQ).(*SubscriptionNotification)
+		assert.Nil(t, msg.Params.Error)
+		assert.Equal(t, id, msg.Params.Subscription.Value)
+		jso := msg.Params.Result.(map[string]interface{})
+		assert.Equal(t, "added", jso["type"])
+		assert.Equal(t, common.HexBytes(fmt.Sprint(i)), jso["txHash"])
+	}
+	<-sent
+
+	res, err = muxTestUnsubscribe(ms, id)
+	assert.NoError(t, err)
+	assert.Equal(t, true, res)
+	select {
+	case <-sm.unsubscribed:
+	case <-time.After(time.Second):
+		assert.Fail(t, "the pool subscription isn't cancelled")
+	}
+}
@@ -34,7 +34,9 @@ type (
 		ApplySteps(t state.StepType, n int) bool
 		DeductSteps(s *big.Int) bool
 		ResetStepLimit(s *big.Int)
+		StepUsedDetails() map[state.StepType]*big.Int
 		GetEventLogs(r txresult.Receipt)
+		GetInternalTransfers(r txresult.Receipt)
 		EnterQueryMode()
 		SetCodeID(code string)
 		GetLastEIDOf(code string) int
@@ -70,17 +72,25 @@ type callContext struct {
 	frame  *callFrame
 	waiter chan interface{}
 
-	log *trace.Logger
+	log    *trace.Logger
+	tracer module.TraceFrameCallback
+
+	// stepDetails is the amount of steps applied for each type in all frames.
+	stepDetails map[state.StepType]*big.Int
 }
 
 func NewCallContext(ctx Context, limit *big.Int, isQuery bool) CallContext {
 	logger := trace.LoggerOf(ctx.Logger())
+	var tracer module.TraceFrameCallback
 	ti := ctx.TraceInfo()
 	if ti != nil {
 		var info state.TransactionInfo
 		if ctx.GetTransactionInfo(&info) {
-			if info.Group == ti.Group && int(info.Index) == ti.Index {
+			if info.Group == ti.Group && (ti.Index < 0 || int(info.Index) == ti.Index) {
 				logger = trace.NewLogger(logger.Logger, ti.Callback)
+				if tracer, _ = ti.Callback.(module.TraceFrameCallback); tracer != nil {
+					tracer.OnTransactionStart(info.Group, int(info.Index))
+				}
 			}
 		}
 	}
@@ -93,6 +103,7 @@ func NewCallContext(ctx Context, limit *big.Int, isQuery bool) CallContext {
 
 		waiter: make(chan interface{}, 8),
 		log:    logger,
+		tracer: tracer,
 	}
 }
 
@@ -115,6 +126,9 @@ func (cc *callContext) pushFrame(handler ContractHandler, limit *big.Int) *callF
 		frame.snapshot = cc.GetSnapshot()
 	}
 	cc.frame = frame
+	if cc.tracer != nil {
+		cc.tracer.OnFrameEnter(traceFrameOf(handler, limit))
+	}
 	return frame
 }
 
@@ -126,6 +140,7 @@ func (cc *callContext) popFrame(success bool) *callFrame {
 	if !frame.isQuery {
 		if success {
 			frame.parent.pushBackEventLogsOf(frame)
+			frame.parent.pushBackTransfersOf(frame)
 		} else {
 			cc.Reset(frame.snapshot)
 		}
@@ -137,6 +152,13 @@ func (cc *callContext) popFrame(success bool) *callFrame {
 	return frame
 }
 
+// traceExit notifies the end of the frame to the tracer.
+func (cc *callContext) traceExit(frame *callFrame, status error, addr module.Address) {
+	if cc.tracer != nil {
+		cc.tracer.OnFrameExit(frame.getStepUsed(), status, addr)
+	}
+}
+
 func (cc *callContext) enterQueryMode() {
 	cc.lock.Lock()
 	defer cc.lock.Unlock()
@@ -157,6 +179,9 @@ func (cc *callContext) addLogToFrame(addr module.Address, indexed [][]byte, data
 	defer cc.lock.Unlock()
 
 	cc.frame.addLog(addr, indexed, data)
+	if cc.tracer != nil {
+		cc.tracer.OnFrameEvent(addr, indexed, data)
+	}
 	return nil
 }
 
@@ -241,6 +266,7 @@ func (cc *callContext) cleanUpFrames(target *callFrame, err error) {
 	for cc.frame != nil && cc.frame.handler != nil {
 		frame := cc.frame
 		cc.frame = frame.parent
+		cc.traceExit(frame, err, nil)
 		if ach, ok := frame.handler.(AsyncContractHandler); ok {
 			achs = append(achs, ach)
 		}
@@ -274,6 +300,7 @@ func (cc *callContext) handleResult(target *callFrame, status error, result *cod
 	if current == nil {
 		return false
 	}
+	cc.traceExit(current, status, addr)
 
 	if ach, ok := current.handler.(AsyncContractHandler); ok {
 		ach.Dispose()
@@ -372,6 +399,7 @@ func (cc *callContext) ResetStepLimit(limit *big.Int) {
 
 	cc.frame.stepLimit = limit
 	cc.frame.stepUsed.SetInt64(0)
+	cc.stepDetails = nil
 }
 
 func (cc *callContext) StepAvailable() *big.Int {
@@ -385,11 +413,41 @@ func (cc *callContext) ApplySteps(t state.StepType, n int) bool {
 	cc.lock.Lock()
 	defer cc.lock.Unlock()
 	steps := big.NewInt(cc.StepsFor(t, n))
+	before := cc.frame.getStepUsed()
 	ok := cc.frame.deductSteps(steps)
 	cc.log.TSystemf("STEP apply type=%s count=%d cost=%s total=%s", t, n, steps, &cc.frame.stepUsed)
+	cc.addStepDetail(t, new(big.Int).Sub(cc.frame.getStepUsed(), before))
 	return ok
 }
 
+func (cc *callContext) addStepDetail(t state.StepType, steps *big.Int) {
+	if steps.Sign() <= 0 {
+		return
+	}
+	if cc.stepDetails == nil {
+		cc.stepDetails = make(map[state.StepType]*big.Int)
+	}
+	if v, ok := cc.stepDetails[t]; ok {
+		v.Add(v, steps)
+	} else {
+		cc.stepDetails[t] = steps
+	}
+}
+
+// StepUsedDetails returns the amount of steps applied by ApplySteps for each
+// step type. Steps deducted by DeductSteps, like the ones reported by the
+// execution environments, are not included.
+func (cc *callContext) StepUsedDetails() map[state.StepType]*big.Int {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	details := make(map[state.StepType]*big.Int, len(cc.stepDetails))
+	for t, v := range cc.stepDetails {
+		details[t] = new(big.Int).Set(v)
+	}
+	return details
+}
+
 func (cc *callContext) DeductSteps(s *big.Int) bool {
 	cc.lock.Lock()
 	defer cc.lock.Unlock()
@@ -404,6 +462,12 @@ func (cc *callContext) GetEventLogs(r txresult.Receipt) {
 	cc.frame.getEventLogs(r)
 }
 
+func (cc *callContext) GetInternalTransfers(r txresult.Receipt) {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+	cc.frame.getInternalTransfers(r)
+}
+
 func (cc *callContext) EnterQueryMode() {
 	cc.lock.Lock()
 	defer cc.lock.Unlock()
@@ -16,6 +16,13 @@ type eventLog struct {
 	Data    [][]byte
 }
 
+type internalTransfer struct {
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+	Depth int
+}
+
 type callFrame struct {
 	parent    *callFrame
 	eid       int
@@ -26,6 +33,7 @@ type callFrame struct {
 	stepUsed  big.Int
 	stepLimit *big.Int
 	eventLogs list.List
+	transfers list.List
 	code2EID  map[string]int
 }
 
@@ -39,6 +47,7 @@ func NewFrame(p *callFrame, h ContractHandler, l *big.Int, q bool) *callFrame {
 		eid:       unknownEID,
 	}
 	frame.eventLogs.Init()
+	frame.transfers.Init()
 	return frame
 }
 
@@ -96,11 +105,53 @@ func (f *callFrame) getEventLogs(r txresult.Receipt) {
 	}
 }
 
+// depth returns the depth of the call. The frame for the transaction
+// is at depth zero.
+func (f *callFrame) depth() int {
+	depth := 0
+	for p := f.parent; p != nil && p.handler != nil; p = p.parent {
+		depth++
+	}
+	return depth
+}
+
+// pushBackTransfersOf records ICX transferred by the frame if it's called by
+// another frame, then moves the ones of the frame to f.
+func (f *callFrame) pushBackTransfersOf(frame *callFrame) {
+	if f == nil {
+		return
+	}
+	if f.handler != nil {
+		switch frame.handler.(type) {
+		case *TransferHandler, *TransferAndMessageHandler, *TransferAndCallHandler:
+			ch := commonHandlerOf(frame.handler)
+			if ch.value != nil && ch.value.Sign() > 0 {
+				t := &internalTransfer{
+					Value: ch.value,
+					Depth: frame.depth(),
+				}
+				t.From.SetBytes(ch.from.Bytes())
+				t.To.SetBytes(ch.to.Bytes())
+				f.transfers.PushBack(t)
+			}
+		}
+	}
+	f.transfers.PushBackList(&frame.transfers)
+}
+
+func (f *callFrame) getInternalTransfers(r txresult.Receipt) {
+	for i := f.transfers.Front(); i != nil; i = i.Next() {
+		t := i.Value.(*internalTransfer)
+		r.AddInternalTransaction(&t.From, &t.To, t.Value, t.Depth)
+	}
+}
+
 func (f *callFrame) enterQueryMode(cc *callContext) {
 	if !f.isQuery {
 		cc.Reset(f.snapshot)
 		f.snapshot = nil
 		f.eventLogs.Init()
+		f.transfers.Init()
 		f.isQuery = true
 	}
 }
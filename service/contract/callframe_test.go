@@ -0,0 +1,69 @@
+package contract
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+func TestCallFrame_InternalTransfers(t *testing.T) {
+	user := common.NewAddressFromString("hx1111111111111111111111111111111111111111")
+	score1 := common.NewAddressFromString("cx2222222222222222222222222222222222222222")
+	score2 := common.NewAddressFromString("cx3333333333333333333333333333333333333333")
+	user2 := common.NewAddressFromString("hx4444444444444444444444444444444444444444")
+	logger := log.New()
+
+	base := NewFrame(nil, nil, nil, false)
+
+	// transfer of the transaction itself isn't an internal one
+	root := NewFrame(base, newTransferAndCallHandler(
+		newCommonHandler(user, score1, big.NewInt(100), logger),
+		newCallHandlerWithTypedObj(
+			newCommonHandler(user, score1, big.NewInt(100), logger),
+			"run", nil, false),
+	), nil, false)
+
+	call := NewFrame(root, newTransferAndCallHandler(
+		newCommonHandler(score1, score2, big.NewInt(10), logger),
+		newCallHandlerWithTypedObj(
+			newCommonHandler(score1, score2, big.NewInt(10), logger),
+			"run", nil, false),
+	), nil, false)
+	transfer := NewFrame(call, newTransferHandler(
+		newCommonHandler(score2, user2, big.NewInt(3), logger)), nil, false)
+	assert.Equal(t, 2, transfer.depth())
+	call.pushBackTransfersOf(transfer)
+	root.pushBackTransfersOf(call)
+
+	// call without value
+	zero := NewFrame(root, newTransferHandler(
+		newCommonHandler(score1, user2, big.NewInt(0), logger)), nil, false)
+	root.pushBackTransfersOf(zero)
+
+	base.pushBackTransfersOf(root)
+
+	r := txresult.NewReceipt(db.NewMapDB(), 0, score1)
+	base.getInternalTransfers(r)
+	itxs := r.InternalTransactions()
+	if assert.Len(t, itxs, 2) {
+		assert.True(t, itxs[0].From().Equal(score1))
+		assert.True(t, itxs[0].To().Equal(score2))
+		assert.EqualValues(t, 10, itxs[0].Value().Int64())
+		assert.Equal(t, 1, itxs[0].Depth())
+
+		assert.True(t, itxs[1].From().Equal(score2))
+		assert.True(t, itxs[1].To().Equal(user2))
+		assert.EqualValues(t, 3, itxs[1].Value().Int64())
+		assert.Equal(t, 2, itxs[1].Depth())
+	}
+
+	// receipt isn't changed by the internal transactions
+	r2 := txresult.NewReceipt(db.NewMapDB(), 0, score1)
+	assert.Equal(t, r2.Bytes(), r.Bytes())
+}
@@ -1236,6 +1236,18 @@ func (s *ChainScore) Ex_getScoreStatus(address module.Address) (map[string]inter
 	} else {
 		scoreStatus["disabled"] = "0x0"
 	}
+
+	// deposit for fee sharing
+	if s.cc.Revision() >= module.Revision9 {
+		deposit := as.GetDeposit()
+		proportion := as.GetFeeProportion()
+		if deposit.Sign() > 0 || proportion > 0 {
+			scoreStatus["depositInfo"] = map[string]interface{}{
+				"amount":     deposit,
+				"proportion": int64(proportion),
+			}
+		}
+	}
 	return scoreStatus, nil
 }
 
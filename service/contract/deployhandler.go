@@ -120,6 +120,10 @@ func (h *DeployHandler) ExecuteSync(cc CallContext) (error, *codec.TypedObj, mod
 
 	h.log.TSystemf("DEPLOY start to=%s", h.to)
 
+	if err := CheckContentType(cc.Revision(), h.contentType); err != nil {
+		return err, nil, nil
+	}
+
 	update := false
 	info := cc.GetInfo()
 	if info == nil {
@@ -445,6 +449,17 @@ func (h *callGetAPIHandler) SetObjGraph(flags bool, nextHash int, objGraph []byt
 	return nil
 }
 
+// CheckContentType returns an error if the content type of the SCORE isn't
+// allowed in the revision. SCOREs written in Go are allowed from Revision10,
+// so the network enables them after all validators run the Go engine.
+func CheckContentType(rev int, contentType string) error {
+	if contentType == state.CTAppGo && rev < module.Revision10 {
+		return scoreresult.InvalidParameterError.Errorf(
+			"NotAllowedContentType(type=%s,rev=%d)", contentType, rev)
+	}
+	return nil
+}
+
 func ParseDeployData(data []byte) (*DeployData, error) {
 	deploy := new(DeployData)
 	if err := json.Unmarshal(data, deploy); err != nil {
@@ -0,0 +1,45 @@
+package contract
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func TestCheckContentType(t *testing.T) {
+	for _, ct := range []string{state.CTAppZip, state.CTAppJava} {
+		assert.NoError(t, CheckContentType(module.Revision9, ct), ct)
+		assert.NoError(t, CheckContentType(module.Revision10, ct), ct)
+	}
+	assert.True(t, scoreresult.InvalidParameterError.Equals(
+		CheckContentType(module.Revision9, state.CTAppGo)))
+	assert.NoError(t, CheckContentType(module.Revision10, state.CTAppGo))
+}
+
+func TestDeployHandler_GoScoreBeforeRevision(t *testing.T) {
+	ws := state.NewWorldState(db.NewMapDB(), nil, nil)
+	sys := ws.GetAccountState(state.SystemID)
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarRevision).Set(module.Revision9))
+	ctx := NewContext(state.NewWorldContext(ws, common.NewBlockInfo(1, 0)),
+		nil, nil, nil, log.New(), nil)
+
+	from := common.NewAddressFromString("hx1111111111111111111111111111111111111111")
+	h, err := newDeployHandler(
+		newCommonHandler(from, state.SystemAddress, big.NewInt(0), log.New()),
+		[]byte(`{"contentType":"application/x.score.go","content":"0x73636f7265"}`))
+	assert.NoError(t, err)
+
+	cc := NewCallContext(ctx, big.NewInt(1000), false)
+	defer cc.Dispose()
+	status, _, _, _ := cc.Call(h, cc.StepAvailable())
+	assert.True(t, scoreresult.InvalidParameterError.Equals(status))
+}
@@ -0,0 +1,164 @@
+package contract
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const (
+	DepositActionAdd           = "add"
+	DepositActionWithdraw      = "withdraw"
+	DepositActionSetProportion = "setProportion"
+)
+
+// DepositData is the data of the transaction for the deposit of the SCORE.
+//
+//   - add : anyone can add the value of the transaction to the deposit.
+//   - withdraw : the owner withdraws amount (or all if it's omitted)
+//     from the deposit.
+//   - setProportion : the owner sets the proportion (0~100) of the fees
+//     paid with the deposit for the transactions calling the SCORE.
+type DepositData struct {
+	Action     string           `json:"action"`
+	Amount     *common.HexInt   `json:"amount,omitempty"`
+	Proportion *common.HexInt32 `json:"proportion,omitempty"`
+}
+
+type DepositHandler struct {
+	*CommonHandler
+	data *DepositData
+}
+
+func newDepositHandler(ch *CommonHandler, data []byte) (ContractHandler, error) {
+	d, err := ParseDepositData(data)
+	if err != nil {
+		return nil, err
+	}
+	return &DepositHandler{
+		CommonHandler: ch,
+		data:          d,
+	}, nil
+}
+
+func (h *DepositHandler) ExecuteSync(cc CallContext) (error, *codec.TypedObj, module.Address) {
+	if cc.Revision() < module.Revision9 {
+		return scoreresult.InvalidParameterError.New("DepositIsNotSupported"), nil, nil
+	}
+	if !cc.ApplySteps(state.StepTypeContractCall, 1) {
+		return scoreresult.ErrOutOfStep, nil, nil
+	}
+	if !h.to.IsContract() {
+		return scoreresult.InvalidParameterError.Errorf(
+			"InvalidAddress(%s)", h.to.String()), nil, nil
+	}
+	as := cc.GetAccountState(h.to.ID())
+	if !as.IsContract() || as.ActiveContract() == nil {
+		return scoreresult.ErrContractNotFound, nil, nil
+	}
+
+	var err error
+	switch h.data.Action {
+	case DepositActionAdd:
+		err = h.addDeposit(cc, as)
+	case DepositActionWithdraw:
+		err = h.withdrawDeposit(cc, as)
+	case DepositActionSetProportion:
+		err = h.setProportion(as)
+	}
+	return err, nil, nil
+}
+
+func (h *DepositHandler) addDeposit(cc CallContext, as state.AccountState) error {
+	if h.value == nil || h.value.Sign() <= 0 {
+		return scoreresult.InvalidParameterError.New("InvalidDepositAmount")
+	}
+	as1 := cc.GetAccountState(h.from.ID())
+	bal := as1.GetBalance()
+	if bal.Cmp(h.value) < 0 {
+		return scoreresult.ErrOutOfBalance
+	}
+	as1.SetBalance(new(big.Int).Sub(bal, h.value))
+	deposit := as.GetDeposit()
+	as.SetDeposit(new(big.Int).Add(deposit, h.value))
+
+	h.log.TSystemf("DEPOSIT add from=%s to=%s value=%s",
+		h.from, h.to, h.value)
+	return nil
+}
+
+func (h *DepositHandler) checkOwner(as state.AccountState) error {
+	if h.value != nil && h.value.Sign() != 0 {
+		return scoreresult.InvalidParameterError.New("ValueMustBeZero")
+	}
+	if !as.IsContractOwner(h.from) {
+		return scoreresult.AccessDeniedError.Errorf(
+			"NotContractOwner(%s)", h.from.String())
+	}
+	return nil
+}
+
+func (h *DepositHandler) withdrawDeposit(cc CallContext, as state.AccountState) error {
+	if err := h.checkOwner(as); err != nil {
+		return err
+	}
+	deposit := as.GetDeposit()
+	amount := deposit
+	if h.data.Amount != nil {
+		amount = &h.data.Amount.Int
+	}
+	if amount.Sign() <= 0 || amount.Cmp(deposit) > 0 {
+		return scoreresult.InvalidParameterError.Errorf(
+			"InvalidWithdrawAmount(amount=%s,deposit=%s)", amount, deposit)
+	}
+	as.SetDeposit(new(big.Int).Sub(deposit, amount))
+	as1 := cc.GetAccountState(h.from.ID())
+	as1.SetBalance(new(big.Int).Add(as1.GetBalance(), amount))
+
+	h.log.TSystemf("DEPOSIT withdraw from=%s to=%s amount=%s",
+		h.from, h.to, amount)
+	return nil
+}
+
+func (h *DepositHandler) setProportion(as state.AccountState) error {
+	if err := h.checkOwner(as); err != nil {
+		return err
+	}
+	as.SetFeeProportion(int(h.data.Proportion.Value))
+
+	h.log.TSystemf("DEPOSIT setProportion to=%s proportion=%d",
+		h.to, h.data.Proportion.Value)
+	return nil
+}
+
+func ParseDepositData(data []byte) (*DepositData, error) {
+	d := new(DepositData)
+	if err := json.Unmarshal(data, d); err != nil {
+		return nil, scoreresult.InvalidParameterError.Wrapf(err,
+			"InvalidJSON(json=%s)", data)
+	}
+	switch d.Action {
+	case DepositActionAdd:
+		// do nothing
+	case DepositActionWithdraw:
+		if d.Amount != nil && d.Amount.Sign() <= 0 {
+			return nil, scoreresult.InvalidParameterError.Errorf(
+				"InvalidAmount(%s)", d.Amount.String())
+		}
+	case DepositActionSetProportion:
+		if d.Proportion == nil || d.Proportion.Value < 0 ||
+			d.Proportion.Value > state.FeeProportionMax {
+			return nil, scoreresult.InvalidParameterError.Errorf(
+				"InvalidProportion(json=%s)", data)
+		}
+	default:
+		return nil, scoreresult.InvalidParameterError.Errorf(
+			"UnknownDepositAction(%s)", d.Action)
+	}
+	return d, nil
+}
@@ -0,0 +1,35 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDepositData(t *testing.T) {
+	cases := []struct {
+		data string
+		ok   bool
+	}{
+		{`{"action":"add"}`, true},
+		{`{"action":"withdraw"}`, true},
+		{`{"action":"withdraw","amount":"0x10"}`, true},
+		{`{"action":"withdraw","amount":"0x0"}`, false},
+		{`{"action":"setProportion","proportion":"0x0"}`, true},
+		{`{"action":"setProportion","proportion":"0x64"}`, true},
+		{`{"action":"setProportion","proportion":"0x65"}`, false},
+		{`{"action":"setProportion"}`, false},
+		{`{"action":"unknown"}`, false},
+		{`{}`, false},
+		{`"add"`, false},
+	}
+	for _, c := range cases {
+		d, err := ParseDepositData([]byte(c.data))
+		if c.ok {
+			assert.NoError(t, err, c.data)
+			assert.NotNil(t, d, c.data)
+		} else {
+			assert.Error(t, err, c.data)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package contract
+
+import (
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// OverrideCode replaces the code of the contract at the address. The code is
+// activated right after getting API of it without calling on_install or
+// on_update, so the storage of the contract is kept. It's used to override
+// the state for queries and estimations, so the changes must not be
+// committed.
+func OverrideCode(ctx Context, addr module.Address, code []byte, contentType string) error {
+	if !addr.IsContract() {
+		return scoreresult.InvalidParameterError.Errorf(
+			"NotContractAddress(addr=%s)", addr)
+	}
+	switch contentType {
+	case state.CTAppZip, state.CTAppJava, state.CTAppGo:
+	default:
+		return scoreresult.InvalidParameterError.Errorf(
+			"InvalidContentType(type=%s)", contentType)
+	}
+	if err := CheckContentType(ctx.Revision(), contentType); err != nil {
+		return err
+	}
+	as := ctx.GetAccountState(addr.ID())
+	if !as.IsContract() {
+		as.InitContractAccount(state.SystemAddress)
+	}
+	txHash := crypto.SHA3Sum256(append(addr.Bytes(), code...))
+	eeType := state.EETypeFromContentType(contentType)
+	if _, err := as.DeployContract(code, eeType, contentType, nil, txHash); err != nil {
+		return err
+	}
+
+	cc := NewCallContext(ctx, nil, true)
+	defer cc.Dispose()
+	handler := newCallGetAPIHandler(
+		newCommonHandler(state.SystemAddress, addr, nil, ctx.Logger()))
+	if status, _, _, _ := cc.Call(handler, nil); status != nil {
+		return status
+	}
+	return as.AcceptContract(txHash, nil)
+}
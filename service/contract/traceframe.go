@@ -0,0 +1,69 @@
+package contract
+
+import (
+	"math/big"
+
+	"github.com/icon-project/goloop/module"
+)
+
+// traceFrameOf returns the information of the handler for the tracer.
+func traceFrameOf(handler ContractHandler, limit *big.Int) *module.TraceFrame {
+	frame := &module.TraceFrame{
+		StepLimit: limit,
+	}
+	switch h := handler.(type) {
+	case *TransferAndCallHandler:
+		frame.Type = "call"
+		frame.Method = h.name
+	case *CallHandler:
+		frame.Type = "call"
+		frame.Method = h.name
+	case *TransferAndMessageHandler, *TransferHandler:
+		frame.Type = "transfer"
+	case *DeployHandler:
+		frame.Type = "deploy"
+	case *AcceptHandler:
+		frame.Type = "accept"
+	case *patchHandler:
+		frame.Type = "patch"
+	case *callGetAPIHandler:
+		frame.Type = "getAPI"
+	case *DepositHandler:
+		frame.Type = "deposit"
+	default:
+		frame.Type = "unknown"
+	}
+	if ch := commonHandlerOf(handler); ch != nil {
+		frame.From = ch.from
+		frame.To = ch.to
+		frame.Value = ch.value
+	}
+	return frame
+}
+
+// commonHandlerOf returns the CommonHandler of the handler, or nil if it
+// doesn't have one.
+func commonHandlerOf(handler ContractHandler) *CommonHandler {
+	switch h := handler.(type) {
+	case *TransferAndCallHandler:
+		return h.CommonHandler
+	case *CallHandler:
+		return h.CommonHandler
+	case *TransferAndMessageHandler:
+		return h.CommonHandler
+	case *TransferHandler:
+		return h.CommonHandler
+	case *DeployHandler:
+		return h.CommonHandler
+	case *AcceptHandler:
+		return h.CommonHandler
+	case *patchHandler:
+		return h.CommonHandler
+	case *callGetAPIHandler:
+		return h.CommonHandler
+	case *DepositHandler:
+		return h.CommonHandler
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,219 @@
+package contract
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/trace"
+)
+
+// traceTestTree builds the call tree, and counts calls of the callbacks to
+// check OnFrameEnter and OnFrameExit are paired.
+type traceTestTree struct {
+	trace.CallTree
+	depth  int
+	enters int
+	exits  int
+}
+
+func (t *traceTestTree) OnLog(level module.TraceLevel, msg string) {}
+
+func (t *traceTestTree) OnEnd(e error) {}
+
+func (t *traceTestTree) OnFrameEnter(frame *module.TraceFrame) {
+	t.depth++
+	t.enters++
+	t.CallTree.OnFrameEnter(frame)
+}
+
+func (t *traceTestTree) OnFrameExit(stepUsed *big.Int, status error, addr module.Address) {
+	t.depth--
+	t.exits++
+	t.CallTree.OnFrameExit(stepUsed, status, addr)
+}
+
+// traceTestHandler emits an event with its name, uses the steps and calls
+// the handlers in order. Then it returns the status.
+type traceTestHandler struct {
+	eeproxy.CallContext
+	name   string
+	steps  int64
+	status error
+	calls  []ContractHandler
+}
+
+var traceTestAddress = common.NewAddressFromString("cx0000000000000000000000000000000000000001")
+
+func (h *traceTestHandler) Prepare(ctx Context) (state.WorldContext, error) {
+	return ctx, nil
+}
+
+func (h *traceTestHandler) ResetLogger(logger log.Logger) {}
+
+func (h *traceTestHandler) emit(cc CallContext) {
+	cc.OnEvent(traceTestAddress, [][]byte{[]byte("Event(str)")}, [][]byte{[]byte(h.name)})
+}
+
+type traceTestSyncHandler struct {
+	*traceTestHandler
+}
+
+func (h *traceTestSyncHandler) ExecuteSync(cc CallContext) (error, *codec.TypedObj, module.Address) {
+	h.emit(cc)
+	cc.DeductSteps(big.NewInt(h.steps))
+	for _, call := range h.calls {
+		cc.Call(call, nil)
+	}
+	return h.status, nil, nil
+}
+
+type traceTestAsyncHandler struct {
+	*traceTestHandler
+}
+
+func (h *traceTestAsyncHandler) ExecuteAsync(cc CallContext) error {
+	h.emit(cc)
+	if len(h.calls) > 0 {
+		cc.OnCall(h.calls[0], nil)
+		return nil
+	}
+	cc.OnResult(h.status, big.NewInt(h.steps), nil, nil)
+	return nil
+}
+
+func (h *traceTestAsyncHandler) SendResult(status error, steps *big.Int, result *codec.TypedObj) error {
+	return nil
+}
+
+func (h *traceTestAsyncHandler) Dispose() {}
+
+func (h *traceTestAsyncHandler) EEType() state.EEType {
+	return state.PythonEE
+}
+
+func newTraceTestCallContext(tree *traceTestTree) CallContext {
+	dbase := db.NewMapDB()
+	wc := state.NewWorldContext(
+		state.NewWorldState(dbase, nil, nil),
+		common.NewBlockInfo(1, 0),
+	)
+	wc.SetTransactionInfo(&state.TransactionInfo{
+		Group: module.TransactionGroupNormal,
+		Index: 2,
+		Hash:  []byte("tx"),
+	})
+	ti := &module.TraceInfo{
+		Group:    module.TransactionGroupNormal,
+		Index:    2,
+		Callback: tree,
+	}
+	return NewCallContext(NewContext(wc, nil, nil, nil, log.New(), ti), nil, false)
+}
+
+func assertTraceTestFrame(t *testing.T, f *trace.Frame, name string, steps int64, status error, calls int) {
+	if assert.Len(t, f.Events, 1, name) {
+		assert.Equal(t, [][]byte{[]byte(name)}, f.Events[0].Data, name)
+	}
+	assert.EqualValues(t, steps, f.StepUsed.Int64(), name)
+	assert.Equal(t, status, f.Status, name)
+	assert.Len(t, f.Calls, calls, name)
+}
+
+func TestCallContext_TraceFrames(t *testing.T) {
+	failure := scoreresult.New(module.StatusReverted, "Failure")
+	a := &traceTestSyncHandler{&traceTestHandler{
+		name:  "a",
+		steps: 10,
+		calls: []ContractHandler{
+			&traceTestSyncHandler{&traceTestHandler{name: "b", steps: 100}},
+			&traceTestSyncHandler{&traceTestHandler{name: "c", steps: 50, status: failure}},
+		},
+	}}
+
+	tree := new(traceTestTree)
+	cc := newTraceTestCallContext(tree)
+	status, _, _, _ := cc.Call(a, nil)
+	assert.NoError(t, status)
+
+	assert.Equal(t, 3, tree.enters)
+	assert.Equal(t, 3, tree.exits)
+	assert.Equal(t, 0, tree.depth)
+
+	txs := tree.Transactions()
+	assert.Len(t, txs, 1)
+	assert.Equal(t, module.TransactionGroupNormal, txs[0].Group)
+	assert.Equal(t, 2, txs[0].Index)
+
+	root := txs[0].Frame
+	assert.Equal(t, "unknown", root.Type)
+	assertTraceTestFrame(t, root, "a", 10, nil, 2)
+	assertTraceTestFrame(t, root.Calls[0], "b", 100, nil, 0)
+	assertTraceTestFrame(t, root.Calls[1], "c", 50, failure, 0)
+
+	// the failure of the child is in the JSON of the tree
+	jso, err := root.ToJSON(module.JSONVersion3)
+	assert.NoError(t, err)
+	calls := jso.(map[string]interface{})["calls"].([]interface{})
+	assert.Equal(t, "0x0", calls[1].(map[string]interface{})["status"])
+}
+
+func TestCallContext_TraceCleanUpFrames(t *testing.T) {
+	failure := errors.ExecutionFailError.New("Failure")
+	c := &traceTestAsyncHandler{&traceTestHandler{name: "c", steps: 30, status: failure}}
+	b := &traceTestAsyncHandler{&traceTestHandler{name: "b", calls: []ContractHandler{c}}}
+	a := &traceTestAsyncHandler{&traceTestHandler{name: "a", calls: []ContractHandler{b}}}
+
+	tree := new(traceTestTree)
+	cc := newTraceTestCallContext(tree)
+	status, _, _, _ := cc.Call(a, nil)
+	assert.Equal(t, failure, status)
+
+	// all frames are exited by cleanUpFrames with the error
+	assert.Equal(t, 3, tree.enters)
+	assert.Equal(t, 3, tree.exits)
+	assert.Equal(t, 0, tree.depth)
+
+	txs := tree.Transactions()
+	assert.Len(t, txs, 1)
+	root := txs[0].Frame
+	assertTraceTestFrame(t, root, "a", 0, failure, 1)
+	assertTraceTestFrame(t, root.Calls[0], "b", 0, failure, 1)
+	assertTraceTestFrame(t, root.Calls[0].Calls[0], "c", 30, failure, 0)
+}
+
+func TestTraceFrameOf(t *testing.T) {
+	from := common.NewAddressFromString("hx0000000000000000000000000000000000000001")
+	to := common.NewAddressFromString("cx0000000000000000000000000000000000000002")
+	value := big.NewInt(100)
+	limit := big.NewInt(1000)
+
+	ch := newCommonHandler(from, to, value, log.New())
+	frame := traceFrameOf(newCallHandlerWithTypedObj(ch, "transfer", nil, false), limit)
+	assert.Equal(t, &module.TraceFrame{
+		Type:      "call",
+		From:      from,
+		To:        to,
+		Value:     value,
+		Method:    "transfer",
+		StepLimit: limit,
+	}, frame)
+
+	frame = traceFrameOf(newTransferHandler(ch), limit)
+	assert.Equal(t, "transfer", frame.Type)
+	assert.Equal(t, from, frame.From)
+	assert.Equal(t, "", frame.Method)
+
+	frame = traceFrameOf(&traceTestSyncHandler{&traceTestHandler{}}, nil)
+	assert.Equal(t, &module.TraceFrame{Type: "unknown"}, frame)
+}
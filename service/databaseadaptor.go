@@ -30,6 +30,10 @@ func (da *databaseAdaptor) GetBucket(id db.BucketID) (db.Bucket, error) {
 	}
 }
 
+func (da *databaseAdaptor) NewBatch() db.Batch {
+	panic("Not allowed to write database")
+}
+
 func (da *databaseAdaptor) Close() error {
 	panic("Not allowed to close database")
 	return nil
@@ -75,9 +79,27 @@ func (ba *bucketAdaptor) Delete(key []byte) error {
 	panic("Now allowed")
 }
 
+func (ba *bucketAdaptor) Iterator(prefix, start []byte) db.Iterator {
+	return &iteratorAdaptor{
+		Iterator: ba.bucket.Iterator(prefix, start),
+		database: ba.database,
+	}
+}
+
 func newBucketAdaptor(da *databaseAdaptor, bk db.Bucket) *bucketAdaptor {
 	return &bucketAdaptor{
 		database: da,
 		bucket:   bk,
 	}
 }
+
+type iteratorAdaptor struct {
+	db.Iterator
+	database *databaseAdaptor
+}
+
+func (ia *iteratorAdaptor) Value() []byte {
+	value := ia.Iterator.Value()
+	ia.database.OnRead(len(value))
+	return value
+}
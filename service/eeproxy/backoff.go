@@ -0,0 +1,31 @@
+package eeproxy
+
+import "time"
+
+const (
+	restartDelayMin = 100 * time.Millisecond
+	restartDelayMax = 10 * time.Second
+)
+
+// restartBackoff delays restarting of execution engines exponentially, so
+// an engine failing repeatedly doesn't consume the resources in tight loop.
+// It's reset when an instance is attached successfully.
+type restartBackoff struct {
+	next time.Duration
+}
+
+func (b *restartBackoff) delay() time.Duration {
+	d := b.next
+	if d < restartDelayMin {
+		d = restartDelayMin
+	}
+	b.next = d * 2
+	if b.next > restartDelayMax {
+		b.next = restartDelayMax
+	}
+	return d
+}
+
+func (b *restartBackoff) reset() {
+	b.next = 0
+}
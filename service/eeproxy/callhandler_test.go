@@ -0,0 +1,175 @@
+package eeproxy_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/goscore"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const (
+	callTestStepPrice   = 10
+	callTestStepDefault = 100
+	callTestStepCall    = 50
+	callTestStepSet     = 10
+	callTestStepUsed    = 1000
+	callTestBalance     = 1000000
+	callTestMainScore   = "mock-call-score"
+)
+
+var (
+	callTestSender = common.NewAddressFromString("hx0000000000000000000000000000000000000001")
+	callTestScore  = common.NewAddressFromString("cx0000000000000000000000000000000000000001")
+)
+
+// newCallTestCode returns the zip file having only package.json, which makes
+// the mock execution engine run the SCORE registered as main.
+func newCallTestCode(t *testing.T, main string) []byte {
+	buf := bytes.NewBuffer(nil)
+	zw := zip.NewWriter(buf)
+	w, err := zw.Create("score/package.json")
+	assert.NoError(t, err)
+	_, err = w.Write([]byte(fmt.Sprintf(`{"main_score":"%s"}`, main)))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// newCallTestContext returns the context on the state where callTestScore
+// is deployed with the code for the mock execution engine.
+func newCallTestContext(t *testing.T, cm contract.ContractManager,
+	eem eeproxy.Manager, info *scoreapi.Info) contract.Context {
+	ws := state.NewWorldState(db.NewMapDB(), nil, nil)
+	sys := ws.GetAccountState(state.SystemID)
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarRevision).Set(module.Revision9))
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarStepPrice).Set(callTestStepPrice))
+	costs := scoredb.NewDictDB(sys, state.VarStepCosts, 1)
+	for typ, cost := range map[string]int{
+		state.StepTypeDefault:      callTestStepDefault,
+		state.StepTypeContractCall: callTestStepCall,
+		state.StepTypeSet:          callTestStepSet,
+	} {
+		assert.NoError(t, scoredb.NewArrayDB(sys, state.VarStepTypes).Put(typ))
+		assert.NoError(t, costs.Set(typ, cost))
+	}
+	assert.NoError(t, scoredb.NewArrayDB(sys, state.VarStepLimitTypes).Put(transaction.LimitTypeInvoke))
+	assert.NoError(t, scoredb.NewDictDB(sys, state.VarStepLimit, 1).Set(transaction.LimitTypeInvoke, 0x100000))
+
+	ws.GetAccountState(callTestSender.ID()).SetBalance(big.NewInt(callTestBalance))
+	as := ws.GetAccountState(callTestScore.ID())
+	as.InitContractAccount(callTestSender)
+	_, err := as.DeployContract(newCallTestCode(t, callTestMainScore),
+		state.PythonEE, state.CTAppZip, nil, []byte("deploy"))
+	assert.NoError(t, err)
+	assert.NoError(t, as.AcceptContract([]byte("deploy"), nil))
+	as.SetAPIInfo(info)
+
+	ctx := contract.NewContext(state.NewWorldContext(ws, common.NewBlockInfo(1, 0)),
+		cm, eem, nil, log.New(), nil)
+	ctx.SetTransactionInfo(&state.TransactionInfo{
+		Group: module.TransactionGroupNormal,
+		Hash:  []byte("tx"),
+		From:  callTestSender,
+	})
+	return ctx
+}
+
+func executeCallTestTx(t *testing.T, ctx contract.Context, cm contract.ContractManager, method string) txresult.Receipt {
+	tx, err := transaction.NewTransactionFromJSON([]byte(fmt.Sprintf(`{
+		"version":"0x3",
+		"from":"%s",
+		"to":"%s",
+		"stepLimit":"0x100000",
+		"timestamp":"0x1",
+		"nid":"0x1",
+		"dataType":"call",
+		"data":{"method":"%s"}
+	}`, callTestSender, callTestScore, method)))
+	assert.NoError(t, err)
+	th, err := tx.GetHandler(cm)
+	assert.NoError(t, err)
+	defer th.Dispose()
+	rct, err := th.Execute(ctx, false)
+	assert.NoError(t, err)
+	return rct
+}
+
+func TestCallHandler_MockEE(t *testing.T) {
+	dir, err := ioutil.TempDir("", "callhandler")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	key, value := []byte("key"), []byte("value")
+	info := scoreapi.NewInfo([]*scoreapi.Method{
+		{Type: scoreapi.Function, Name: "store", Flags: scoreapi.FlagExternal},
+		{Type: scoreapi.Function, Name: "revert", Flags: scoreapi.FlagExternal},
+	})
+	goscore.Register(callTestMainScore, &eeproxy.MockScore{
+		API: info,
+		Methods: map[string][]eeproxy.MockOp{
+			"store": {
+				eeproxy.MockSetValue(key, value),
+				eeproxy.MockUseSteps(callTestStepUsed),
+			},
+			"revert": {
+				eeproxy.MockSetValue(key, value),
+				eeproxy.MockRevert(1, "Oops"),
+			},
+		},
+	})
+
+	logger := log.New()
+	engine, err := eeproxy.NewMockEE(logger, string(state.PythonEE), nil)
+	assert.NoError(t, err)
+	eem, err := eeproxy.NewManager("unix", filepath.Join(dir, "ee.sock"), logger, engine)
+	assert.NoError(t, err)
+	go eem.Loop()
+	defer eem.Close()
+	assert.NoError(t, eem.SetInstances(1, 1, 1))
+
+	cm, err := contract.NewContractManager(db.NewMapDB(), filepath.Join(dir, "contract"), logger)
+	assert.NoError(t, err)
+
+	// default + contractCall + set(10 * 5) + used by the SCORE
+	ctx := newCallTestContext(t, cm, eem, info)
+	rct := executeCallTestTx(t, ctx, cm, "store")
+	assert.Equal(t, module.StatusSuccess, rct.Status())
+	steps := int64(callTestStepDefault + callTestStepCall + callTestStepSet*len(value) + callTestStepUsed)
+	assert.EqualValues(t, steps, rct.StepUsed().Int64())
+	assert.EqualValues(t, callTestBalance-steps*callTestStepPrice,
+		ctx.GetAccountState(callTestSender.ID()).GetBalance().Int64())
+	stored, err := ctx.GetAccountState(callTestScore.ID()).GetValue(key)
+	assert.NoError(t, err)
+	assert.Equal(t, value, stored)
+
+	// the changes are rolled back, but the steps are charged
+	ctx = newCallTestContext(t, cm, eem, info)
+	rct = executeCallTestTx(t, ctx, cm, "revert")
+	assert.Equal(t, module.StatusReverted+1, rct.Status())
+	steps = int64(callTestStepDefault + callTestStepCall + callTestStepSet*len(value))
+	assert.EqualValues(t, steps, rct.StepUsed().Int64())
+	assert.EqualValues(t, callTestBalance-steps*callTestStepPrice,
+		ctx.GetAccountState(callTestSender.ID()).GetBalance().Int64())
+	stored, err = ctx.GetAccountState(callTestScore.ID()).GetValue(key)
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+}
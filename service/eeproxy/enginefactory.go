@@ -22,6 +22,12 @@ func AllocEngines(l log.Logger, names ...string) ([]Engine, error) {
 			} else {
 				engines[i] = engine
 			}
+		case "go":
+			if engine, err := NewGoEE(l); err != nil {
+				return nil, err
+			} else {
+				engines[i] = engine
+			}
 		default:
 			return nil, errors.IllegalArgumentError.Errorf(
 				"IllegalEngineName(name=%s)", name)
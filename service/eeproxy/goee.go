@@ -0,0 +1,497 @@
+package eeproxy
+
+import (
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/ipc"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/goscore"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/trace"
+)
+
+const (
+	GoEE = "goee"
+
+	// GoCodeFile is the file in the contract store having the name of the
+	// SCORE registered in goscore package.
+	GoCodeFile = "score.name"
+)
+
+// LocalEngine is an Engine executing SCOREs in the process. It doesn't need
+// any connection, so the manager gets a new proxy from the engine for each
+// executor.
+type LocalEngine interface {
+	Engine
+	NewProxy() Proxy
+}
+
+type goExecutionEngine struct {
+	lock   sync.Mutex
+	names  map[string]string
+	logger log.Logger
+}
+
+func (e *goExecutionEngine) Type() string {
+	return string(state.GoEE)
+}
+
+func (e *goExecutionEngine) Init(net, addr string) error {
+	return nil
+}
+
+func (e *goExecutionEngine) SetInstances(n int) error {
+	return nil
+}
+
+func (e *goExecutionEngine) OnAttach(uid string) bool {
+	return false
+}
+
+func (e *goExecutionEngine) OnEnd(uid string) bool {
+	return false
+}
+
+func (e *goExecutionEngine) Kill(uid string) (bool, error) {
+	return false, nil
+}
+
+func (e *goExecutionEngine) OnConnect(conn ipc.Connection, version uint16) error {
+	return errors.UnsupportedError.New("NoConnectionForGoEE")
+}
+
+func (e *goExecutionEngine) OnClose(conn ipc.Connection) bool {
+	return false
+}
+
+func (e *goExecutionEngine) NewProxy() Proxy {
+	return &goProxy{
+		engine: e,
+		quit:   make(chan struct{}),
+		log:    e.logger,
+	}
+}
+
+// scoreOf returns the SCORE for the code in the contract store.
+func (e *goExecutionEngine) scoreOf(code string) (goscore.Score, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	name, ok := e.names[code]
+	if !ok {
+		bs, err := ioutil.ReadFile(filepath.Join(code, GoCodeFile))
+		if err != nil {
+			return nil, errors.CriticalIOError.Wrapf(err,
+				"FailToReadScoreName(code=%s)", code)
+		}
+		name = strings.TrimSpace(string(bs))
+		e.names[code] = name
+	}
+	score, err := goscore.Lookup(name)
+	if err != nil {
+		return nil, scoreresult.IllegalFormatError.Wrap(err, "UnknownGoScore")
+	}
+	return score, nil
+}
+
+func NewGoEE(logger log.Logger) (Engine, error) {
+	return &goExecutionEngine{
+		names:  make(map[string]string),
+		logger: logger.WithFields(log.Fields{log.FieldKeyModule: GoEE}),
+	}, nil
+}
+
+type goCallResult struct {
+	status error
+	steps  *big.Int
+	result *codec.TypedObj
+}
+
+type goFrame struct {
+	ctx    CallContext
+	result chan *goCallResult
+	prev   *goFrame
+}
+
+// goProxy executes Go SCOREs with goroutines. Nested calls of the SCORE are
+// requested through the CallContext, and it waits for the result sent by
+// SendResult.
+type goProxy struct {
+	lock   sync.Mutex
+	engine *goExecutionEngine
+	frame  *goFrame
+	quit   chan struct{}
+	killed bool
+	log    log.Logger
+}
+
+func (p *goProxy) pushFrame(ctx CallContext) *goFrame {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.frame = &goFrame{
+		ctx:    ctx,
+		result: make(chan *goCallResult, 1),
+		prev:   p.frame,
+	}
+	return p.frame
+}
+
+func (p *goProxy) popFrame(frame *goFrame) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.killed {
+		return false
+	}
+	for f := &p.frame; *f != nil; f = &(*f).prev {
+		if *f == frame {
+			*f = frame.prev
+			return true
+		}
+	}
+	return false
+}
+
+func (p *goProxy) Invoke(
+	ctx CallContext, code string, isQuery bool,
+	from, to module.Address, value, limit *big.Int, method string, params *codec.TypedObj,
+	eid int, state *CodeState,
+) error {
+	logger := trace.LoggerOf(ctx.Logger())
+	logger.Tracef("GoProxy[%p].Invoke code=%s query=%v from=%v to=%v value=%v limit=%v method=%s eid=%d",
+		p, code, isQuery, from, to, value, limit, method, eid)
+
+	score, err := p.engine.scoreOf(code)
+	if err != nil {
+		return err
+	}
+	var args []interface{}
+	if params != nil {
+		if v, err := common.DecodeAny(params); err != nil {
+			return scoreresult.InvalidParameterError.Wrap(err, "InvalidParams")
+		} else if v != nil {
+			var ok bool
+			if args, ok = v.([]interface{}); !ok {
+				return scoreresult.InvalidParameterError.Errorf(
+					"InvalidParams(params=%v)", v)
+			}
+		}
+	}
+	info, err := common.DecodeAny(ctx.GetInfo())
+	if err != nil {
+		return err
+	}
+
+	gc := &goContext{
+		proxy:   p,
+		frame:   p.pushFrame(ctx),
+		isQuery: isQuery,
+		from:    from,
+		to:      to,
+		value:   value,
+		log:     logger,
+	}
+	gc.info, _ = info.(map[string]interface{})
+	gc.stepMeter.init(gc.info, limit)
+	go p.execute(gc, score, method, args)
+	return nil
+}
+
+func (p *goProxy) execute(gc *goContext, score goscore.Score, method string, params []interface{}) {
+	var result interface{}
+	status := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = scoreresult.UnknownFailureError.Errorf(
+					"PanicInScore(method=%s,err=%v)", method, r)
+			}
+		}()
+		result, err = score.Invoke(gc, method, params)
+		return
+	}()
+
+	steps := gc.StepUsed()
+	var obj *codec.TypedObj
+	if gc.failure != nil {
+		status = gc.failure
+	} else if gc.exceeded() {
+		status = scoreresult.ErrOutOfStep
+		steps.Set(gc.limit)
+	} else if status != nil {
+		status = scoreresult.Validate(status)
+	} else if obj, status = common.EncodeAny(result); status != nil {
+		status = scoreresult.UnknownFailureError.Wrapf(status,
+			"InvalidResult(method=%s,result=%v)", method, result)
+	}
+
+	gc.log.Tracef("GoProxy[%p].OnResult status=%v steps=%v", p, status, steps)
+	if p.popFrame(gc.frame) {
+		gc.frame.ctx.OnResult(status, steps, obj)
+	}
+}
+
+// call requests the call to the other contract through the context of the
+// frame, then it waits for the result.
+func (p *goProxy) call(frame *goFrame, from, to module.Address, value, limit *big.Int, method string, params *codec.TypedObj) *goCallResult {
+	frame.ctx.OnCall(from, to, value, limit, method, params)
+	select {
+	case r := <-frame.result:
+		return r
+	case <-p.quit:
+		return &goCallResult{
+			status: errors.ExecutionFailError.New("ProxyIsKilled"),
+			steps:  new(big.Int),
+		}
+	}
+}
+
+func (p *goProxy) SendResult(ctx CallContext, status error, steps *big.Int, result *codec.TypedObj, eid int, last int) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for f := p.frame; f != nil; f = f.prev {
+		if f.ctx == ctx {
+			f.result <- &goCallResult{
+				status: status,
+				steps:  steps,
+				result: result,
+			}
+			return nil
+		}
+	}
+	return errors.InvalidStateError.New("NoFrameForResult")
+}
+
+func (p *goProxy) GetAPI(ctx CallContext, code string) error {
+	score, err := p.engine.scoreOf(code)
+	if err != nil {
+		return err
+	}
+	go ctx.OnAPI(nil, score.GetAPI())
+	return nil
+}
+
+func (p *goProxy) Release() {
+	// do nothing
+}
+
+// Kill stops waiting for the results of the calls. It can't stop the SCOREs
+// running, but their results are ignored.
+func (p *goProxy) Kill() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if !p.killed {
+		p.log.Warnf("GoProxy[%p].Kill()", p)
+		p.killed = true
+		p.frame = nil
+		close(p.quit)
+	}
+	return nil
+}
+
+// stepMeter charges steps for the operations of the SCORE with the step
+// costs in the information of the invocation.
+type stepMeter struct {
+	costs map[string]*big.Int
+	used  big.Int
+	limit *big.Int
+}
+
+func (m *stepMeter) init(info map[string]interface{}, limit *big.Int) {
+	m.costs = make(map[string]*big.Int)
+	m.limit = limit
+	if costs, ok := info[state.InfoStepCosts].(map[string]interface{}); ok {
+		for k, v := range costs {
+			if cost, ok := v.(*common.HexInt); ok {
+				m.costs[k] = &cost.Int
+			}
+		}
+	}
+}
+
+func (m *stepMeter) applySteps(t string, n int) error {
+	if cost, ok := m.costs[t]; ok && n > 0 {
+		m.used.Add(&m.used, new(big.Int).Mul(cost, big.NewInt(int64(n))))
+	}
+	if m.exceeded() {
+		return scoreresult.OutOfStepError.Errorf("OutOfStep(type=%s)", t)
+	}
+	return nil
+}
+
+func (m *stepMeter) addSteps(steps *big.Int) {
+	if steps != nil {
+		m.used.Add(&m.used, steps)
+	}
+}
+
+func (m *stepMeter) stepAvailable() *big.Int {
+	return new(big.Int).Sub(m.limit, &m.used)
+}
+
+func (m *stepMeter) exceeded() bool {
+	return m.used.Cmp(m.limit) > 0
+}
+
+func (m *stepMeter) StepUsed() *big.Int {
+	return new(big.Int).Set(&m.used)
+}
+
+// eventBytes converts values of the event to bytes. It also returns the
+// size of them for charging steps.
+func eventBytes(indexed, data []interface{}) ([][]byte, [][]byte, int) {
+	var size int
+	toBytes := func(values []interface{}) [][]byte {
+		bss := make([][]byte, len(values))
+		for i, v := range values {
+			if v != nil {
+				bss[i] = scoredb.ToBytes(v)
+				size += len(bss[i])
+			}
+		}
+		return bss
+	}
+	ib, db := toBytes(indexed), toBytes(data)
+	return ib, db, size
+}
+
+// goContext implements goscore.Context for a frame.
+type goContext struct {
+	stepMeter
+
+	proxy *goProxy
+	frame *goFrame
+
+	isQuery  bool
+	from, to module.Address
+	value    *big.Int
+	info     map[string]interface{}
+	failure  error
+	log      *trace.Logger
+}
+
+// check records the error from the context if it's not a result of SCORE,
+// so it's returned regardless of the result of the SCORE.
+func (c *goContext) check(err error) error {
+	if err != nil && !scoreresult.IsValid(err) && c.failure == nil {
+		c.failure = err
+	}
+	return err
+}
+
+func (c *goContext) GetValue(key []byte) ([]byte, error) {
+	value, err := c.frame.ctx.GetValue(key)
+	if err != nil {
+		return nil, c.check(err)
+	}
+	c.log.TSystemf("GETVALUE key=<%x> value=<%x>", key, value)
+	if err := c.applySteps(state.StepTypeGet, len(value)); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (c *goContext) SetValue(key []byte, value []byte) ([]byte, error) {
+	old, err := c.frame.ctx.SetValue(key, value)
+	if err != nil {
+		return nil, c.check(err)
+	}
+	c.log.TSystemf("SETVALUE key=<%x> value=<%x> old=<%x>", key, value, old)
+	if old != nil {
+		err = c.applySteps(state.StepTypeReplace, len(value))
+	} else {
+		err = c.applySteps(state.StepTypeSet, len(value))
+	}
+	return old, err
+}
+
+func (c *goContext) DeleteValue(key []byte) ([]byte, error) {
+	old, err := c.frame.ctx.DeleteValue(key)
+	if err != nil {
+		return nil, c.check(err)
+	}
+	c.log.TSystemf("DELETE key=<%x> old=<%x>", key, old)
+	return old, c.applySteps(state.StepTypeDelete, len(old))
+}
+
+func (c *goContext) Address() module.Address {
+	return c.to
+}
+
+func (c *goContext) From() module.Address {
+	return c.from
+}
+
+func (c *goContext) Value() *big.Int {
+	return new(big.Int).Set(c.value)
+}
+
+func (c *goContext) IsQuery() bool {
+	return c.isQuery
+}
+
+func (c *goContext) Info() map[string]interface{} {
+	return c.info
+}
+
+func (c *goContext) GetBalance(addr module.Address) *big.Int {
+	return c.frame.ctx.GetBalance(addr)
+}
+
+func (c *goContext) Emit(indexed []interface{}, data []interface{}) error {
+	if c.isQuery {
+		return scoreresult.AccessDeniedError.New("EventLogInQuery")
+	}
+	if len(indexed) == 0 {
+		return scoreresult.InvalidParameterError.New("NoEventSignature")
+	}
+	ib, db, size := eventBytes(indexed, data)
+	if err := c.applySteps(state.StepTypeEventLog, size); err != nil {
+		return err
+	}
+	c.frame.ctx.OnEvent(c.to, ib, db)
+	return nil
+}
+
+func (c *goContext) Call(to module.Address, value *big.Int, method string, params ...interface{}) (interface{}, error) {
+	if value == nil {
+		value = new(big.Int)
+	}
+	if params == nil {
+		params = []interface{}{}
+	}
+	obj, err := common.EncodeAny(params)
+	if err != nil {
+		return nil, scoreresult.InvalidParameterError.Wrap(err, "InvalidParams")
+	}
+	limit := c.stepAvailable()
+	if limit.Sign() <= 0 {
+		return nil, scoreresult.OutOfStepError.New("OutOfStep(type=contractCall)")
+	}
+	r := c.proxy.call(c.frame, c.to, to, value, limit, method, obj)
+	c.addSteps(r.steps)
+	if r.status != nil {
+		return nil, c.check(r.status)
+	}
+	result, err := common.DecodeAny(r.result)
+	if err != nil {
+		return nil, scoreresult.UnknownFailureError.Wrap(err, "InvalidResult")
+	}
+	return result, nil
+}
+
+func (c *goContext) Logger() log.Logger {
+	return c.log
+}
@@ -0,0 +1,269 @@
+package eeproxy
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/goscore"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+)
+
+type testCounter struct{}
+
+func (s *testCounter) GetAPI() *scoreapi.Info {
+	return scoreapi.NewInfo([]*scoreapi.Method{
+		{
+			Type:   scoreapi.Function,
+			Name:   "on_install",
+			Inputs: []scoreapi.Parameter{{Name: "initial", Type: scoreapi.Integer}},
+		},
+		{
+			Type:   scoreapi.Function,
+			Name:   "increase",
+			Flags:  scoreapi.FlagExternal,
+			Inputs: []scoreapi.Parameter{{Name: "amount", Type: scoreapi.Integer}},
+		},
+		{
+			Type:    scoreapi.Function,
+			Name:    "get",
+			Flags:   scoreapi.FlagReadOnly,
+			Outputs: []scoreapi.DataType{scoreapi.Integer},
+		},
+		{
+			Type:   scoreapi.Function,
+			Name:   "relay",
+			Flags:  scoreapi.FlagExternal,
+			Inputs: []scoreapi.Parameter{{Name: "to", Type: scoreapi.Address}},
+		},
+		{
+			Type:   scoreapi.Event,
+			Name:   "Increased",
+			Inputs: []scoreapi.Parameter{{Name: "count", Type: scoreapi.Integer}},
+		},
+	})
+}
+
+func (s *testCounter) Invoke(ctx goscore.Context, method string, params []interface{}) (interface{}, error) {
+	count := scoredb.NewVarDB(ctx, "count")
+	switch method {
+	case "on_install":
+		return nil, count.Set(params[0])
+	case "increase":
+		value := new(big.Int).Add(big.NewInt(count.Int64()), &params[0].(*common.HexInt).Int)
+		if err := count.Set(value); err != nil {
+			return nil, err
+		}
+		return nil, ctx.Emit([]interface{}{"Increased(int)"}, []interface{}{value})
+	case "get":
+		return count.Int64(), nil
+	case "relay":
+		return ctx.Call(params[0].(module.Address), nil, "increase", 1)
+	default:
+		return nil, scoreresult.ErrMethodNotFound
+	}
+}
+
+type testResult struct {
+	status error
+	steps  *big.Int
+	result *codec.TypedObj
+}
+
+type testCall struct {
+	to     module.Address
+	limit  *big.Int
+	method string
+	params *codec.TypedObj
+}
+
+type testCallContext struct {
+	store    map[string][]byte
+	events   [][][]byte
+	nextHash int
+	objGraph []byte
+	result   chan *testResult
+	calls    chan *testCall
+}
+
+func newTestCallContext(store map[string][]byte) *testCallContext {
+	return &testCallContext{
+		store:  store,
+		result: make(chan *testResult, 1),
+		calls:  make(chan *testCall, 1),
+	}
+}
+
+func (c *testCallContext) GetValue(key []byte) ([]byte, error) {
+	return c.store[string(key)], nil
+}
+
+func (c *testCallContext) SetValue(key []byte, value []byte) ([]byte, error) {
+	old := c.store[string(key)]
+	c.store[string(key)] = value
+	return old, nil
+}
+
+func (c *testCallContext) DeleteValue(key []byte) ([]byte, error) {
+	old := c.store[string(key)]
+	delete(c.store, string(key))
+	return old, nil
+}
+
+func (c *testCallContext) GetInfo() *codec.TypedObj {
+	return common.MustEncodeAny(map[string]interface{}{
+		state.InfoStepCosts: map[string]int64{
+			state.StepTypeGet:      1,
+			state.StepTypeSet:      10,
+			state.StepTypeReplace:  5,
+			state.StepTypeDelete:   2,
+			state.StepTypeEventLog: 100,
+		},
+	})
+}
+
+func (c *testCallContext) GetBalance(addr module.Address) *big.Int {
+	return new(big.Int)
+}
+
+func (c *testCallContext) OnEvent(addr module.Address, indexed, data [][]byte) {
+	c.events = append(c.events, append(indexed, data...))
+}
+
+func (c *testCallContext) OnResult(status error, steps *big.Int, result *codec.TypedObj) {
+	c.result <- &testResult{status, steps, result}
+}
+
+func (c *testCallContext) OnCall(from, to module.Address, value, limit *big.Int, method string, params *codec.TypedObj) {
+	c.calls <- &testCall{to, limit, method, params}
+}
+
+func (c *testCallContext) OnAPI(status error, info *scoreapi.Info) {
+	c.result <- &testResult{status: status, result: common.MustEncodeAny(info != nil)}
+}
+
+func (c *testCallContext) SetCode(code []byte) error {
+	return nil
+}
+
+func (c *testCallContext) GetObjGraph(bool) (int, []byte, []byte, error) {
+	return c.nextHash, nil, c.objGraph, nil
+}
+
+func (c *testCallContext) SetObjGraph(flags bool, nextHash int, objGraph []byte) error {
+	c.nextHash, c.objGraph = nextHash, objGraph
+	return nil
+}
+
+func (c *testCallContext) Logger() log.Logger {
+	return log.GlobalLogger()
+}
+
+func TestGoEE_Invoke(t *testing.T) {
+	goscore.Register("test-counter", &testCounter{})
+
+	dir, err := ioutil.TempDir("", "goee")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, GoCodeFile),
+		[]byte("test-counter"), 0644))
+
+	engine, err := NewGoEE(log.GlobalLogger())
+	assert.NoError(t, err)
+	p := engine.(LocalEngine).NewProxy()
+	from := common.NewAddressFromString("hx0000000000000000000000000000000000000001")
+	to := common.NewAddressFromString("cx0000000000000000000000000000000000000001")
+	limit := big.NewInt(10000)
+	store := make(map[string][]byte)
+
+	ctx := newTestCallContext(store)
+	assert.NoError(t, p.GetAPI(ctx, dir))
+	r := <-ctx.result
+	assert.NoError(t, r.status)
+
+	ctx = newTestCallContext(store)
+	params := common.MustEncodeAny([]interface{}{big.NewInt(3)})
+	assert.NoError(t, p.Invoke(ctx, dir, false, from, to, new(big.Int), limit,
+		"on_install", params, 0, nil))
+	r = <-ctx.result
+	assert.NoError(t, r.status)
+	assert.EqualValues(t, 10, r.steps.Int64())
+
+	// replace(5) + get(1) + eventLog(100 * (14 + 1))
+	ctx = newTestCallContext(store)
+	params = common.MustEncodeAny([]interface{}{big.NewInt(2)})
+	assert.NoError(t, p.Invoke(ctx, dir, false, from, to, new(big.Int), limit,
+		"increase", params, 0, nil))
+	r = <-ctx.result
+	assert.NoError(t, r.status)
+	assert.EqualValues(t, 1506, r.steps.Int64())
+	assert.Equal(t, [][][]byte{{[]byte("Increased(int)"), {5}}}, ctx.events)
+
+	ctx = newTestCallContext(store)
+	assert.NoError(t, p.Invoke(ctx, dir, true, from, to, new(big.Int), limit,
+		"get", common.MustEncodeAny([]interface{}{}), 0, nil))
+	r = <-ctx.result
+	assert.NoError(t, r.status)
+	count, err := common.DecodeAny(r.result)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, count.(*common.HexInt).Int64())
+
+	ctx = newTestCallContext(store)
+	params = common.MustEncodeAny([]interface{}{big.NewInt(2)})
+	assert.NoError(t, p.Invoke(ctx, dir, false, from, to, new(big.Int), big.NewInt(100),
+		"increase", params, 0, nil))
+	r = <-ctx.result
+	assert.True(t, scoreresult.OutOfStepError.Equals(r.status))
+	assert.EqualValues(t, 100, r.steps.Int64())
+}
+
+func TestGoEE_Call(t *testing.T) {
+	goscore.Register("test-counter2", &testCounter{})
+
+	dir, err := ioutil.TempDir("", "goee")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, GoCodeFile),
+		[]byte("test-counter2"), 0644))
+
+	engine, err := NewGoEE(log.GlobalLogger())
+	assert.NoError(t, err)
+	p := engine.(LocalEngine).NewProxy()
+	from := common.NewAddressFromString("hx0000000000000000000000000000000000000001")
+	score1 := common.NewAddressFromString("cx0000000000000000000000000000000000000001")
+	score2 := common.NewAddressFromString("cx0000000000000000000000000000000000000002")
+
+	ctx1 := newTestCallContext(make(map[string][]byte))
+	params := common.MustEncodeAny([]interface{}{score2})
+	assert.NoError(t, p.Invoke(ctx1, dir, false, from, score1, new(big.Int), big.NewInt(5000),
+		"relay", params, 0, nil))
+
+	call := <-ctx1.calls
+	assert.True(t, score2.Equal(call.to))
+	assert.Equal(t, "increase", call.method)
+	assert.EqualValues(t, 5000, call.limit.Int64())
+
+	// set(10) + eventLog(100 * (14 + 1))
+	ctx2 := newTestCallContext(make(map[string][]byte))
+	assert.NoError(t, p.Invoke(ctx2, dir, false, score1, score2, new(big.Int), call.limit,
+		call.method, call.params, 1, nil))
+	r := <-ctx2.result
+	assert.NoError(t, r.status)
+	assert.EqualValues(t, 1510, r.steps.Int64())
+
+	assert.NoError(t, p.SendResult(ctx1, r.status, r.steps, r.result, 2, 1))
+	r = <-ctx1.result
+	assert.NoError(t, r.status)
+	assert.EqualValues(t, 1510, r.steps.Int64())
+}
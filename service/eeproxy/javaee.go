@@ -32,6 +32,7 @@ type javaExecutionEngine struct {
 	net, addr    string
 	cmd          *exec.Cmd
 	timer        *time.Timer
+	backoff      restartBackoff
 
 	conn   ipc.Connection
 	logger log.Logger
@@ -125,6 +126,7 @@ func (e *javaExecutionEngine) OnAttach(uid string) bool {
 
 	if is, ok := e.instances[uid]; ok {
 		is.status = instanceOnline
+		e.backoff.reset()
 		return true
 	}
 	e.logger.Debugf("Invalid UID(%s)\n", uid)
@@ -175,9 +177,15 @@ func (e *javaExecutionEngine) OnEnd(uid string) bool {
 		e.logger.Infof("OnEnd uid(%s) status(%s)\n", uid, is.status)
 		if is.status == instanceOnline {
 			e.term(is)
-			if err := e.runInstances(); err != nil {
-				return false
-			}
+			delay := e.backoff.delay()
+			e.logger.Infof("Restart executor after %v", delay)
+			time.AfterFunc(delay, func() {
+				e.lock.Lock()
+				defer e.lock.Unlock()
+				if err := e.runInstances(); err != nil {
+					e.logger.Warnf("Failed to restart executor. err(%+v)\n", err)
+				}
+			})
 			return true
 		} else {
 			// if the proxy with the uid is not connected, do not retry run
@@ -210,9 +218,15 @@ func (e *javaExecutionEngine) OnClose(conn ipc.Connection) bool {
 		e.term(i)
 	}
 
-	if err := e.start(); err != nil {
-		e.logger.Panicf("Failed to start Java EEManager. err(%s)\n", err)
-	}
+	delay := e.backoff.delay()
+	e.logger.Infof("Restart Java EEManager after %v", delay)
+	e.timer = time.AfterFunc(delay, func() {
+		e.lock.Lock()
+		defer e.lock.Unlock()
+		if err := e.start(); err != nil {
+			e.logger.Panicf("Failed to start Java EEManager. err(%s)\n", err)
+		}
+	})
 	return true
 }
 
@@ -2,11 +2,14 @@ package eeproxy
 
 import (
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/ipc"
 	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/server/metric"
 )
 
 type RequestPriority int
@@ -19,6 +22,17 @@ const (
 	numberOfPriorities = 2
 )
 
+func (pr RequestPriority) String() string {
+	switch pr {
+	case ForTransaction:
+		return "transaction"
+	case ForQuery:
+		return "query"
+	default:
+		return "unknown"
+	}
+}
+
 const (
 	errorBase                  = errors.CodeService + 300
 	ScaleDownError errors.Code = iota + errorBase
@@ -29,10 +43,41 @@ const (
 type Manager interface {
 	GetExecutor(pr RequestPriority) *Executor
 	SetInstances(total, tx, query int) error
+
+	// SetInvokeTimeout sets the deadline for the response of the execution
+	// engine. The engine which doesn't respond in time is killed and
+	// restarted. Zero means no deadline.
+	SetInvokeTimeout(d time.Duration)
+	Health() *Health
 	Loop() error
 	Close() error
 }
 
+type EngineHealth struct {
+	Type      string `json:"type"`
+	Healthy   bool   `json:"healthy"`
+	Active    int    `json:"active"`
+	Ready     int    `json:"ready"`
+	Using     int    `json:"using"`
+	Restarts  int64  `json:"restarts"`
+	Timeouts  int64  `json:"timeouts"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+type ExecutorHealth struct {
+	Priority string `json:"priority"`
+	Limit    int    `json:"limit"`
+	Assigned int    `json:"assigned"`
+	Waiting  int    `json:"waiting"`
+}
+
+// Health is the status of the execution engines and the executors.
+type Health struct {
+	InvokeTimeout int64            `json:"invokeTimeout"`
+	Engines       []EngineHealth   `json:"engines"`
+	Executors     []ExecutorHealth `json:"executors"`
+}
+
 type Engine interface {
 	Type() string
 	Init(net, addr string) error
@@ -49,9 +94,13 @@ type Executor struct {
 	manager  *executorManager
 	typeMap  map[string]int
 	proxies  []*proxy
+	locals   map[string]Proxy
 }
 
 func (e *Executor) Get(name string) Proxy {
+	if p, ok := e.locals[name]; ok {
+		return p
+	}
 	t, ok := e.typeMap[name]
 	if !ok {
 		return nil
@@ -64,12 +113,18 @@ func (e *Executor) Release() {
 	for _, p := range e.proxies {
 		p.Release()
 	}
+	for _, p := range e.locals {
+		p.Release()
+	}
 }
 
 func (e *Executor) Kill() {
 	for _, p := range e.proxies {
 		p.Kill()
 	}
+	for _, p := range e.locals {
+		p.Kill()
+	}
 	e.Release()
 }
 
@@ -78,6 +133,19 @@ type engine struct {
 	active int
 	ready  *proxy
 	using  *proxy
+
+	restarts  int64
+	timeouts  int64
+	lastError string
+	metric    *metric.EEMetric
+}
+
+func countProxies(p *proxy) int {
+	cnt := 0
+	for ; p != nil; p = p.next {
+		cnt += 1
+	}
+	return cnt
 }
 
 type executorState struct {
@@ -85,6 +153,7 @@ type executorState struct {
 	assigned int
 	waiter   *sync.Cond
 	waiting  int
+	metric   *metric.EEQueueMetric
 }
 
 type executorManager struct {
@@ -94,10 +163,15 @@ type executorManager struct {
 
 	typeMap map[string]int
 	engines []*engine
+	locals  []LocalEngine
 
 	executorLimit  int
 	executorStates [numberOfPriorities]executorState
 
+	// timeout is the deadline for the response of the execution engine in
+	// nanoseconds. It's accessed by proxies without the lock.
+	timeout int64
+
 	log log.Logger
 }
 
@@ -149,6 +223,76 @@ func (em *executorManager) kill(u string) error {
 	return errors.New("NoEntry")
 }
 
+func (em *executorManager) invokeTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&em.timeout))
+}
+
+func (em *executorManager) SetInvokeTimeout(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	atomic.StoreInt64(&em.timeout, int64(d))
+}
+
+func (em *executorManager) onInvoke(t string, d time.Duration) {
+	if i, ok := em.typeMap[t]; ok {
+		em.engines[i].metric.OnInvoke(d)
+	}
+}
+
+func (em *executorManager) onTimeout(t string, err error) {
+	em.lock.Lock()
+	defer em.lock.Unlock()
+
+	if i, ok := em.typeMap[t]; ok {
+		e := em.engines[i]
+		e.timeouts += 1
+		e.lastError = err.Error()
+		e.metric.OnTimeout()
+	}
+}
+
+// onLostInLock is called when the proxy in use is closed unexpectedly.
+// The engine restarts the instance for it.
+func (em *executorManager) onLostInLock(e *engine, p *proxy) {
+	em.log.Warnf("Lost proxy=%s-%s (active=%d)", e.engine.Type(), p.uid, e.active)
+	e.restarts += 1
+	e.metric.OnRestart()
+}
+
+func (em *executorManager) Health() *Health {
+	em.lock.Lock()
+	defer em.lock.Unlock()
+
+	h := &Health{
+		InvokeTimeout: int64(em.invokeTimeout() / time.Millisecond),
+		Engines:       make([]EngineHealth, 0, len(em.engines)),
+		Executors:     make([]ExecutorHealth, 0, len(em.executorStates)),
+	}
+	for _, e := range em.engines {
+		h.Engines = append(h.Engines, EngineHealth{
+			Type:      e.engine.Type(),
+			Healthy:   e.active >= em.executorLimit,
+			Active:    e.active,
+			Ready:     countProxies(e.ready),
+			Using:     countProxies(e.using),
+			Restarts:  e.restarts,
+			Timeouts:  e.timeouts,
+			LastError: e.lastError,
+		})
+	}
+	for i := range em.executorStates {
+		s := &em.executorStates[i]
+		h.Executors = append(h.Executors, ExecutorHealth{
+			Priority: RequestPriority(i).String(),
+			Limit:    s.limit,
+			Assigned: s.assigned,
+			Waiting:  s.waiting,
+		})
+	}
+	return h
+}
+
 func (em *executorManager) OnConnect(c ipc.Connection) error {
 	_ = newEEConnection(em, em.log, c)
 	return nil
@@ -163,6 +307,7 @@ func (em *executorManager) OnClose(c ipc.Connection) {
 			if p.conn == c {
 				p.detach()
 				e.active -= 1
+				em.onLostInLock(e, p)
 				return
 			}
 		}
@@ -173,6 +318,7 @@ func (em *executorManager) OnClose(c ipc.Connection) {
 					p.OnClose()
 				})
 				e.active -= 1
+				em.onLostInLock(e, p)
 				return
 			}
 		}
@@ -202,11 +348,16 @@ func (em *executorManager) createExecutorInLock(pr RequestPriority) *Executor {
 		p.attachTo(&em.engines[i].using)
 		p.reserve()
 	}
+	locals := make(map[string]Proxy, len(em.locals))
+	for _, e := range em.locals {
+		locals[e.Type()] = e.NewProxy()
+	}
 	return &Executor{
 		priority: pr,
 		manager:  em,
 		proxies:  ps,
 		typeMap:  em.typeMap,
+		locals:   locals,
 	}
 }
 
@@ -223,12 +374,14 @@ func (em *executorManager) GetExecutor(pr RequestPriority) *Executor {
 
 	es := &em.executorStates[pr]
 	es.waiting += 1
+	start := time.Now()
 	for {
 		if es.assigned < es.limit {
 			e := em.createExecutorInLock(pr)
 			if e != nil {
 				es.assigned += 1
 				es.waiting -= 1
+				es.metric.OnWait(time.Since(start))
 				return e
 			}
 		}
@@ -305,16 +458,25 @@ func NewManager(net, addr string, l log.Logger, engines ...Engine) (Manager, err
 
 	for i := 0; i < len(em.executorStates); i++ {
 		em.executorStates[i].waiter = sync.NewCond(&em.lock)
+		em.executorStates[i].metric = metric.NewEEQueueMetric(
+			metric.DefaultMetricContext(), RequestPriority(i).String())
 	}
 
-	em.engines = make([]*engine, len(engines))
+	em.engines = make([]*engine, 0, len(engines))
 	em.typeMap = make(map[string]int)
-	for i, e := range engines {
+	for _, e := range engines {
 		if err := e.Init(net, addr); err != nil {
 			return nil, err
 		}
-		em.engines[i] = &engine{engine: e}
-		em.typeMap[e.Type()] = i
+		if le, ok := e.(LocalEngine); ok {
+			em.locals = append(em.locals, le)
+			continue
+		}
+		em.typeMap[e.Type()] = len(em.engines)
+		em.engines = append(em.engines, &engine{
+			engine: e,
+			metric: metric.NewEEMetric(metric.DefaultMetricContext(), e.Type()),
+		})
 	}
 	return em, nil
 }
@@ -0,0 +1,232 @@
+package eeproxy
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/goscore"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+func newMockScoreAPI(names ...string) *scoreapi.Info {
+	methods := make([]*scoreapi.Method, len(names))
+	for i, name := range names {
+		methods[i] = &scoreapi.Method{
+			Type:  scoreapi.Function,
+			Name:  name,
+			Flags: scoreapi.FlagExternal,
+		}
+	}
+	return scoreapi.NewInfo(methods)
+}
+
+func TestMockEE_Invoke(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mockee")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	from := common.NewAddressFromString("hx0000000000000000000000000000000000000001")
+	score1 := common.NewAddressFromString("cx0000000000000000000000000000000000000001")
+	score2 := common.NewAddressFromString("cx0000000000000000000000000000000000000002")
+	key, value := []byte("key"), []byte("value")
+
+	goscore.Register("mock-score1", &MockScore{
+		API: newMockScoreAPI("store", "revert", "relay", "graph"),
+		Methods: map[string][]MockOp{
+			"store": {
+				MockSetValue(key, value),
+				MockGetValue(key),
+				MockEvent([]interface{}{"Stored(bytes)"}, []interface{}{value}),
+				MockUseSteps(1000),
+			},
+			"revert": {
+				MockSetValue(key, value),
+				MockRevert(1, "Oops"),
+			},
+			"relay": {
+				MockCall(score2, nil, "echo"),
+			},
+			"graph": {
+				MockSetObjGraph(3, []byte("graph")),
+				MockGetObjGraph(),
+			},
+		},
+	})
+	code1 := filepath.Join(dir, "score1")
+	assert.NoError(t, os.MkdirAll(code1, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(code1, "package.json"),
+		[]byte(`{"main_score":"mock-score1"}`), 0644))
+
+	goscore.Register("mock-score2", &MockScore{
+		API: newMockScoreAPI("echo"),
+		Methods: map[string][]MockOp{
+			"echo": {
+				MockUseSteps(7),
+				MockReturn("pong"),
+			},
+		},
+	})
+	code2 := filepath.Join(dir, "score2")
+	assert.NoError(t, os.MkdirAll(code2, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(code2, "package.json"),
+		[]byte(`{"main_score":"mock-score2"}`), 0644))
+
+	engine, err := NewMockEE(log.GlobalLogger(), "python", nil)
+	assert.NoError(t, err)
+	mgr, err := NewManager("unix", filepath.Join(dir, "ee.sock"), log.GlobalLogger(), engine)
+	assert.NoError(t, err)
+	go mgr.Loop()
+	defer mgr.Close()
+	assert.NoError(t, mgr.SetInstances(1, 1, 1))
+
+	ex := mgr.GetExecutor(ForTransaction)
+	defer ex.Release()
+	p := ex.Get("python")
+	assert.NotNil(t, p)
+	limit := big.NewInt(10000)
+	noParams := common.MustEncodeAny([]interface{}{})
+
+	ctx := newTestCallContext(make(map[string][]byte))
+	assert.NoError(t, p.GetAPI(ctx, code1))
+	r := <-ctx.result
+	assert.NoError(t, r.status)
+
+	// set(10 * 5) + get(1 * 5) + eventLog(100 * (13 + 5)) + 1000
+	ctx = newTestCallContext(make(map[string][]byte))
+	assert.NoError(t, p.Invoke(ctx, code1, false, from, score1, new(big.Int), limit,
+		"store", noParams, 0, nil))
+	r = <-ctx.result
+	assert.NoError(t, r.status)
+	assert.EqualValues(t, 2855, r.steps.Int64())
+	result, err := common.DecodeAny(r.result)
+	assert.NoError(t, err)
+	assert.Equal(t, value, result)
+	assert.Equal(t, value, ctx.store[string(key)])
+	assert.Equal(t, [][][]byte{{[]byte("Stored(bytes)"), value}}, ctx.events)
+
+	ctx = newTestCallContext(make(map[string][]byte))
+	assert.NoError(t, p.Invoke(ctx, code1, false, from, score1, new(big.Int), limit,
+		"revert", noParams, 0, nil))
+	r = <-ctx.result
+	status, _ := scoreresult.StatusOf(r.status)
+	assert.Equal(t, module.StatusReverted+1, status)
+	assert.EqualValues(t, 50, r.steps.Int64())
+
+	ctx = newTestCallContext(make(map[string][]byte))
+	assert.NoError(t, p.Invoke(ctx, code1, false, from, score1, new(big.Int), big.NewInt(500),
+		"store", noParams, 0, nil))
+	r = <-ctx.result
+	assert.True(t, scoreresult.OutOfStepError.Equals(r.status))
+	assert.EqualValues(t, 500, r.steps.Int64())
+
+	ctx1 := newTestCallContext(make(map[string][]byte))
+	assert.NoError(t, p.Invoke(ctx1, code1, false, from, score1, new(big.Int), limit,
+		"relay", noParams, 0, nil))
+	call := <-ctx1.calls
+	assert.True(t, score2.Equal(call.to))
+	assert.Equal(t, "echo", call.method)
+
+	ctx2 := newTestCallContext(make(map[string][]byte))
+	assert.NoError(t, p.Invoke(ctx2, code2, false, score1, score2, new(big.Int), call.limit,
+		call.method, call.params, 1, nil))
+	r = <-ctx2.result
+	assert.NoError(t, r.status)
+	assert.EqualValues(t, 7, r.steps.Int64())
+
+	assert.NoError(t, p.SendResult(ctx1, r.status, r.steps, r.result, 2, 1))
+	r = <-ctx1.result
+	assert.NoError(t, r.status)
+	assert.EqualValues(t, 7, r.steps.Int64())
+	result, err = common.DecodeAny(r.result)
+	assert.NoError(t, err)
+	assert.Equal(t, "pong", result)
+
+	ctx = newTestCallContext(make(map[string][]byte))
+	assert.NoError(t, p.Invoke(ctx, code1, false, from, score1, new(big.Int), limit,
+		"graph", noParams, 0, nil))
+	r = <-ctx.result
+	assert.NoError(t, r.status)
+	assert.Equal(t, 3, ctx.nextHash)
+	result, err = common.DecodeAny(r.result)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("graph"), result)
+}
+
+func TestMockEE_InvokeTimeout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mockee")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	from := common.NewAddressFromString("hx0000000000000000000000000000000000000001")
+	score := common.NewAddressFromString("cx0000000000000000000000000000000000000001")
+
+	release := make(chan struct{})
+	goscore.Register("mock-hang", &MockScore{
+		API: newMockScoreAPI("hang", "echo"),
+		Methods: map[string][]MockOp{
+			"hang": {
+				func(ctx MockContext, params []interface{}) (interface{}, error) {
+					<-release
+					return nil, nil
+				},
+			},
+			"echo": {
+				MockReturn("pong"),
+			},
+		},
+	})
+	code := filepath.Join(dir, "score")
+	assert.NoError(t, os.MkdirAll(code, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(code, "package.json"),
+		[]byte(`{"main_score":"mock-hang"}`), 0644))
+
+	engine, err := NewMockEE(log.GlobalLogger(), "python", nil)
+	assert.NoError(t, err)
+	mgr, err := NewManager("unix", filepath.Join(dir, "ee.sock"), log.GlobalLogger(), engine)
+	assert.NoError(t, err)
+	go mgr.Loop()
+	defer mgr.Close()
+	mgr.SetInvokeTimeout(100 * time.Millisecond)
+	assert.NoError(t, mgr.SetInstances(1, 1, 1))
+
+	limit := big.NewInt(10000)
+	noParams := common.MustEncodeAny([]interface{}{})
+
+	ex := mgr.GetExecutor(ForTransaction)
+	ctx := newTestCallContext(make(map[string][]byte))
+	assert.NoError(t, ex.Get("python").Invoke(ctx, code, false, from, score, new(big.Int), limit,
+		"hang", noParams, 0, nil))
+	r := <-ctx.result
+	assert.True(t, scoreresult.TimeoutError.Equals(r.status))
+	ex.Release()
+
+	h := mgr.Health()
+	assert.Equal(t, int64(100), h.InvokeTimeout)
+	assert.Equal(t, int64(1), h.Engines[0].Timeouts)
+	assert.NotEmpty(t, h.Engines[0].LastError)
+	close(release)
+
+	// the killed instance is replaced with new one
+	ex = mgr.GetExecutor(ForTransaction)
+	defer ex.Release()
+	ctx = newTestCallContext(make(map[string][]byte))
+	assert.NoError(t, ex.Get("python").Invoke(ctx, code, false, from, score, new(big.Int), limit,
+		"echo", noParams, 0, nil))
+	r = <-ctx.result
+	assert.NoError(t, r.status)
+
+	h = mgr.Health()
+	assert.Equal(t, int64(1), h.Engines[0].Restarts)
+	assert.Equal(t, 1, h.Engines[0].Active)
+	assert.Equal(t, 1, h.Executors[ForTransaction].Assigned)
+}
@@ -0,0 +1,666 @@
+package eeproxy
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/ipc"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/goscore"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const (
+	MockEE = "mockee"
+
+	mockEEVersion = 1
+)
+
+// MockScoreResolver returns the SCORE for the code in the contract store.
+type MockScoreResolver func(code string) (goscore.Score, error)
+
+// ResolveMockScore returns the SCORE registered in goscore package with
+// "main_score" of package.json in the contract store. So tests can deploy
+// a zip file having only package.json like a SCORE for pyee.
+func ResolveMockScore(code string) (goscore.Score, error) {
+	bs, err := ioutil.ReadFile(filepath.Join(code, "package.json"))
+	if err != nil {
+		return nil, errors.CriticalIOError.Wrapf(err,
+			"FailToReadPackage(code=%s)", code)
+	}
+	var pkg struct {
+		MainScore string `json:"main_score"`
+	}
+	if err := json.Unmarshal(bs, &pkg); err != nil {
+		return nil, scoreresult.IllegalFormatError.Wrap(err, "InvalidPackage")
+	}
+	score, err := goscore.Lookup(pkg.MainScore)
+	if err != nil {
+		return nil, scoreresult.IllegalFormatError.Wrap(err, "UnknownMockScore")
+	}
+	return score, nil
+}
+
+// MockContext is the context of the SCORE executed by the mock execution
+// engine. It has the functions available only through the protocol.
+type MockContext interface {
+	goscore.Context
+
+	// UseSteps charges the steps for the execution.
+	UseSteps(steps int64) error
+
+	GetObjGraph(withObject bool) (int, []byte, []byte, error)
+	SetObjGraph(includeObject bool, nextHash int, graph []byte) error
+	SetCode(code []byte) error
+}
+
+// MockOp is an operation of MockScore. The result of the last operation
+// returning non-nil value is the result of the method.
+type MockOp func(ctx MockContext, params []interface{}) (interface{}, error)
+
+// MockScore is a SCORE running scripted operations for each method. It's
+// used with the mock execution engine to test the transactions without
+// real execution engines.
+type MockScore struct {
+	API     *scoreapi.Info
+	Methods map[string][]MockOp
+}
+
+func (s *MockScore) GetAPI() *scoreapi.Info {
+	return s.API
+}
+
+func (s *MockScore) Invoke(ctx goscore.Context, method string, params []interface{}) (interface{}, error) {
+	ops, ok := s.Methods[method]
+	if !ok {
+		return nil, scoreresult.MethodNotFoundError.Errorf(
+			"MethodNotFound(%s)", method)
+	}
+	mc, ok := ctx.(MockContext)
+	if !ok {
+		return nil, scoreresult.UnknownFailureError.Errorf(
+			"NotMockContext(%T)", ctx)
+	}
+	var result interface{}
+	for _, op := range ops {
+		if ret, err := op(mc, params); err != nil {
+			return nil, err
+		} else if ret != nil {
+			result = ret
+		}
+	}
+	return result, nil
+}
+
+func MockGetValue(key []byte) MockOp {
+	return func(ctx MockContext, params []interface{}) (interface{}, error) {
+		value, err := ctx.GetValue(key)
+		if err != nil || value == nil {
+			return nil, err
+		}
+		return value, nil
+	}
+}
+
+func MockSetValue(key, value []byte) MockOp {
+	return func(ctx MockContext, params []interface{}) (interface{}, error) {
+		_, err := ctx.SetValue(key, value)
+		return nil, err
+	}
+}
+
+func MockDeleteValue(key []byte) MockOp {
+	return func(ctx MockContext, params []interface{}) (interface{}, error) {
+		_, err := ctx.DeleteValue(key)
+		return nil, err
+	}
+}
+
+func MockEvent(indexed []interface{}, data []interface{}) MockOp {
+	return func(ctx MockContext, params []interface{}) (interface{}, error) {
+		return nil, ctx.Emit(indexed, data)
+	}
+}
+
+// MockCall calls the method of the contract with the parameters of the
+// method itself.
+func MockCall(to module.Address, value *big.Int, method string) MockOp {
+	return func(ctx MockContext, params []interface{}) (interface{}, error) {
+		return ctx.Call(to, value, method, params...)
+	}
+}
+
+func MockUseSteps(steps int64) MockOp {
+	return func(ctx MockContext, params []interface{}) (interface{}, error) {
+		return nil, ctx.UseSteps(steps)
+	}
+}
+
+func MockRevert(code int, msg string) MockOp {
+	return func(ctx MockContext, params []interface{}) (interface{}, error) {
+		return nil, scoreresult.WithStatus(errors.New(msg),
+			module.StatusReverted+module.Status(code))
+	}
+}
+
+func MockReturn(result interface{}) MockOp {
+	return func(ctx MockContext, params []interface{}) (interface{}, error) {
+		return result, nil
+	}
+}
+
+func MockSetObjGraph(nextHash int, graph []byte) MockOp {
+	return func(ctx MockContext, params []interface{}) (interface{}, error) {
+		return nil, ctx.SetObjGraph(true, nextHash, graph)
+	}
+}
+
+// MockGetObjGraph returns the object graph of the contract.
+func MockGetObjGraph() MockOp {
+	return func(ctx MockContext, params []interface{}) (interface{}, error) {
+		_, _, graph, err := ctx.GetObjGraph(true)
+		if err != nil || graph == nil {
+			return nil, err
+		}
+		return graph, nil
+	}
+}
+
+type mockInstance struct {
+	uid  string
+	conn ipc.Connection
+
+	// result is the result of the call waiting for it.
+	result *resultMessage
+}
+
+// mockExecutionEngine runs execution engines in the process. They connect
+// to the manager and execute SCOREs with the protocol same as the other
+// execution engines.
+type mockExecutionEngine struct {
+	lock      sync.Mutex
+	eeType    string
+	target    int
+	instances map[string]*mockInstance
+	net, addr string
+	resolve   MockScoreResolver
+	logger    log.Logger
+}
+
+func (e *mockExecutionEngine) Type() string {
+	return e.eeType
+}
+
+func (e *mockExecutionEngine) Init(net, addr string) error {
+	e.net = net
+	e.addr = addr
+	return nil
+}
+
+func (e *mockExecutionEngine) SetInstances(n int) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if n < 0 {
+		return errors.ErrIllegalArgument
+	}
+
+	e.target = n
+	for e.target > len(e.instances) {
+		if err := e.startNew(); err != nil {
+			e.logger.Errorf("Fail to start execution engine err=%+v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *mockExecutionEngine) startNew() error {
+	conn, err := ipc.Dial(e.net, e.addr)
+	if err != nil {
+		return err
+	}
+	is := &mockInstance{
+		uid:  newUID(),
+		conn: conn,
+	}
+	e.instances[is.uid] = is
+	conn.SetHandler(msgINVOKE, e)
+	conn.SetHandler(msgGETAPI, e)
+	conn.SetHandler(msgRESULT, e)
+	conn.SetHandler(msgCLOSE, e)
+	if err := conn.Send(msgVERSION, &versionMessage{
+		Version: mockEEVersion,
+		UID:     is.uid,
+		Type:    e.eeType,
+	}); err != nil {
+		delete(e.instances, is.uid)
+		_ = conn.Close()
+		return err
+	}
+	go e.run(is)
+	return nil
+}
+
+func (e *mockExecutionEngine) run(is *mockInstance) {
+	for {
+		if err := is.conn.HandleMessage(); err != nil {
+			e.logger.Tracef("End the instance uid=%s err=%+v", is.uid, err)
+			break
+		}
+	}
+	_ = is.conn.Close()
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	delete(e.instances, is.uid)
+	for e.target > len(e.instances) {
+		if err := e.startNew(); err != nil {
+			e.logger.Errorf("Fail to start execution engine err=%+v", err)
+			return
+		}
+	}
+}
+
+func (e *mockExecutionEngine) instanceOf(c ipc.Connection) *mockInstance {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for _, is := range e.instances {
+		if is.conn == c {
+			return is
+		}
+	}
+	return nil
+}
+
+func (e *mockExecutionEngine) OnAttach(uid string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	_, ok := e.instances[uid]
+	return ok
+}
+
+func (e *mockExecutionEngine) OnEnd(uid string) bool {
+	return true
+}
+
+func (e *mockExecutionEngine) Kill(uid string) (bool, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if is, ok := e.instances[uid]; ok {
+		return true, is.conn.Close()
+	}
+	return false, nil
+}
+
+func (e *mockExecutionEngine) OnConnect(conn ipc.Connection, version uint16) error {
+	return common.ErrUnsupported
+}
+
+func (e *mockExecutionEngine) OnClose(conn ipc.Connection) bool {
+	return false
+}
+
+func (e *mockExecutionEngine) HandleMessage(c ipc.Connection, msg uint, data []byte) error {
+	is := e.instanceOf(c)
+	if is == nil {
+		return errors.InvalidStateError.New("UnknownInstance")
+	}
+	switch msg {
+	case msgINVOKE:
+		var m invokeMessage
+		if _, err := codec.MP.UnmarshalFromBytes(data, &m); err != nil {
+			return err
+		}
+		return e.invoke(is, &m)
+
+	case msgGETAPI:
+		var code string
+		if _, err := codec.MP.UnmarshalFromBytes(data, &code); err != nil {
+			return err
+		}
+		var m getAPIMessage
+		if score, err := e.resolve(code); err != nil {
+			e.logger.Warnf("FailToGetAPI(code=%s,err=%+v)", code, err)
+			m.Status = errors.CodeOf(scoreresult.Validate(err))
+		} else {
+			m.Status = errors.Success
+			m.Info = score.GetAPI()
+		}
+		return c.Send(msgGETAPI, &m)
+
+	case msgRESULT:
+		var m resultMessage
+		if _, err := codec.MP.UnmarshalFromBytes(data, &m); err != nil {
+			return err
+		}
+		if is.result != nil {
+			return errors.InvalidStateError.New("DuplicateResult")
+		}
+		is.result = &m
+		return nil
+
+	case msgCLOSE:
+		return c.Close()
+
+	default:
+		return errors.ErrIllegalArgument
+	}
+}
+
+func (e *mockExecutionEngine) invoke(is *mockInstance, m *invokeMessage) error {
+	var status error
+	var result interface{}
+	mc := &mockContext{
+		instance: is,
+		isQuery:  m.IsQry,
+		to:       &m.To,
+		value:    &m.Value.Int,
+		log:      e.logger,
+	}
+	if m.From != nil {
+		mc.from = m.From
+	}
+	info, err := common.DecodeAny(m.Info)
+	if err != nil {
+		return err
+	}
+	mc.info, _ = info.(map[string]interface{})
+	mc.stepMeter.init(mc.info, &m.Limit.Int)
+
+	if score, err := e.resolve(m.Code); err != nil {
+		status = err
+	} else if params, err := common.DecodeAny(m.Params); err != nil {
+		status = scoreresult.InvalidParameterError.Wrap(err, "InvalidParams")
+	} else {
+		args, _ := params.([]interface{})
+		status = func() (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = scoreresult.UnknownFailureError.Errorf(
+						"PanicInScore(method=%s,err=%v)", m.Method, r)
+				}
+			}()
+			result, err = score.Invoke(mc, m.Method, args)
+			return
+		}()
+	}
+	if mc.failure != nil {
+		return mc.failure
+	}
+
+	var r resultMessage
+	r.EID = m.EID
+	if mc.exceeded() {
+		status = scoreresult.ErrOutOfStep
+		r.StepUsed.Set(mc.limit)
+	} else {
+		r.StepUsed.Set(&mc.used)
+	}
+	if status == nil {
+		if obj, err := common.EncodeAny(result); err != nil {
+			status = scoreresult.UnknownFailureError.Wrapf(err,
+				"InvalidResult(method=%s,result=%v)", m.Method, result)
+		} else {
+			r.Status = errors.Success
+			r.Result = obj
+		}
+	}
+	if status != nil {
+		r.Status = errors.CodeOf(scoreresult.Validate(status))
+		r.Result = common.MustEncodeAny(status.Error())
+	}
+	return is.conn.Send(msgRESULT, &r)
+}
+
+// mockContext implements MockContext with the messages for the manager.
+type mockContext struct {
+	stepMeter
+
+	instance *mockInstance
+	isQuery  bool
+	from, to module.Address
+	value    *big.Int
+	info     map[string]interface{}
+
+	// failure is the error of the connection. The instance stops on it.
+	failure error
+	log     log.Logger
+}
+
+func (c *mockContext) fail(err error) error {
+	if c.failure == nil {
+		c.failure = err
+	}
+	return err
+}
+
+func (c *mockContext) getValue(key []byte) ([]byte, error) {
+	var m getValueMessage
+	if err := c.instance.conn.SendAndReceive(msgGETVALUE, key, &m); err != nil {
+		return nil, c.fail(err)
+	}
+	if !m.Success {
+		return nil, nil
+	}
+	return m.Value, nil
+}
+
+func (c *mockContext) GetValue(key []byte) ([]byte, error) {
+	value, err := c.getValue(key)
+	if err != nil {
+		return nil, err
+	}
+	return value, c.applySteps(state.StepTypeGet, len(value))
+}
+
+// SetValue sets the value with the message. The message delivers only
+// whether it had the old value, so it always returns nil for the old value.
+func (c *mockContext) SetValue(key []byte, value []byte) ([]byte, error) {
+	if c.isQuery {
+		return nil, scoreresult.AccessDeniedError.New("SetValueInQuery")
+	}
+	var old oldValueMessage
+	if err := c.instance.conn.SendAndReceive(msgSETVALUE, &setValueMessage{
+		Key:   key,
+		Flag:  flagOLDVALUE,
+		Value: value,
+	}, &old); err != nil {
+		return nil, c.fail(err)
+	}
+	if old.HasOld {
+		return nil, c.applySteps(state.StepTypeReplace, len(value))
+	}
+	return nil, c.applySteps(state.StepTypeSet, len(value))
+}
+
+// DeleteValue gets the old value before deleting it, so it can return the
+// old value.
+func (c *mockContext) DeleteValue(key []byte) ([]byte, error) {
+	if c.isQuery {
+		return nil, scoreresult.AccessDeniedError.New("DeleteValueInQuery")
+	}
+	old, err := c.getValue(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.instance.conn.Send(msgSETVALUE, &setValueMessage{
+		Key:  key,
+		Flag: flagDELETE,
+	}); err != nil {
+		return nil, c.fail(err)
+	}
+	return old, c.applySteps(state.StepTypeDelete, len(old))
+}
+
+func (c *mockContext) Address() module.Address {
+	return c.to
+}
+
+func (c *mockContext) From() module.Address {
+	return c.from
+}
+
+func (c *mockContext) Value() *big.Int {
+	return new(big.Int).Set(c.value)
+}
+
+func (c *mockContext) IsQuery() bool {
+	return c.isQuery
+}
+
+func (c *mockContext) Info() map[string]interface{} {
+	return c.info
+}
+
+func (c *mockContext) GetBalance(addr module.Address) *big.Int {
+	var balance common.HexInt
+	if err := c.instance.conn.SendAndReceive(msgGETBALANCE,
+		common.NewAddress(addr.Bytes()), &balance); err != nil {
+		c.fail(err)
+		return new(big.Int)
+	}
+	return &balance.Int
+}
+
+func (c *mockContext) Emit(indexed []interface{}, data []interface{}) error {
+	if c.isQuery {
+		return scoreresult.AccessDeniedError.New("EventLogInQuery")
+	}
+	if len(indexed) == 0 {
+		return scoreresult.InvalidParameterError.New("NoEventSignature")
+	}
+	ib, db, size := eventBytes(indexed, data)
+	if err := c.applySteps(state.StepTypeEventLog, size); err != nil {
+		return err
+	}
+	if err := c.instance.conn.Send(msgEVENT, &eventMessage{
+		Indexed: ib,
+		Data:    db,
+	}); err != nil {
+		return c.fail(err)
+	}
+	return nil
+}
+
+// Call sends the request for the call, then it handles the messages until
+// the result arrives. The manager may invoke the other SCOREs in the engine
+// before it.
+func (c *mockContext) Call(to module.Address, value *big.Int, method string, params ...interface{}) (interface{}, error) {
+	if value == nil {
+		value = new(big.Int)
+	}
+	if params == nil {
+		params = []interface{}{}
+	}
+	obj, err := common.EncodeAny(params)
+	if err != nil {
+		return nil, scoreresult.InvalidParameterError.Wrap(err, "InvalidParams")
+	}
+	limit := c.stepAvailable()
+	if limit.Sign() <= 0 {
+		return nil, scoreresult.OutOfStepError.New("OutOfStep(type=contractCall)")
+	}
+
+	var m callMessage
+	m.To.SetBytes(to.Bytes())
+	m.Value.Set(value)
+	m.Limit.Set(limit)
+	m.Method = method
+	m.Params = obj
+	is := c.instance
+	if err := is.conn.Send(msgCALL, &m); err != nil {
+		return nil, c.fail(err)
+	}
+	for is.result == nil {
+		if err := is.conn.HandleMessage(); err != nil {
+			return nil, c.fail(err)
+		}
+	}
+	r := is.result
+	is.result = nil
+
+	c.addSteps(&r.StepUsed.Int)
+	if r.Status != errors.Success {
+		return nil, r.Status.New(common.DecodeAsString(r.Result, ""))
+	}
+	result, err := common.DecodeAny(r.Result)
+	if err != nil {
+		return nil, scoreresult.UnknownFailureError.Wrap(err, "InvalidResult")
+	}
+	return result, nil
+}
+
+func (c *mockContext) Logger() log.Logger {
+	return c.log
+}
+
+func (c *mockContext) UseSteps(steps int64) error {
+	c.addSteps(big.NewInt(steps))
+	if c.exceeded() {
+		return scoreresult.ErrOutOfStep
+	}
+	return nil
+}
+
+func (c *mockContext) GetObjGraph(withObject bool) (int, []byte, []byte, error) {
+	var flags int
+	if withObject {
+		flags = 1
+	}
+	var m getObjGraphMessage
+	if err := c.instance.conn.SendAndReceive(msgGETOBJGRAPH, flags, &m); err != nil {
+		return 0, nil, nil, c.fail(err)
+	}
+	return m.NextHash, m.GraphHash, m.ObjectGraph, nil
+}
+
+func (c *mockContext) SetObjGraph(includeObject bool, nextHash int, graph []byte) error {
+	var m setObjGraphMessage
+	if includeObject {
+		m.Flags = 1
+	}
+	m.NextHash = nextHash
+	m.ObjectGraph = graph
+	if err := c.instance.conn.Send(msgSETOBJGRAPH, &m); err != nil {
+		return c.fail(err)
+	}
+	return nil
+}
+
+func (c *mockContext) SetCode(code []byte) error {
+	if err := c.instance.conn.Send(msgSETCODE, code); err != nil {
+		return c.fail(err)
+	}
+	return nil
+}
+
+// NewMockEE returns the execution engine running SCOREs in the process
+// through the protocol for the execution engines. It's registered as eeType,
+// so it can replace pyee or javaee in tests. If resolve is nil, it uses
+// ResolveMockScore.
+func NewMockEE(logger log.Logger, eeType string, resolve MockScoreResolver) (Engine, error) {
+	if resolve == nil {
+		resolve = ResolveMockScore
+	}
+	return &mockExecutionEngine{
+		eeType:    eeType,
+		instances: make(map[string]*mockInstance),
+		resolve:   resolve,
+		logger: logger.WithFields(log.Fields{
+			log.FieldKeyModule: MockEE,
+		}),
+	}, nil
+}
@@ -3,6 +3,7 @@ package eeproxy
 import (
 	"math/big"
 	"sync"
+	"time"
 
 	"github.com/gofrs/uuid"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/icon-project/goloop/common/ipc"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/scoreresult"
 )
 
 type Message uint
@@ -84,12 +86,16 @@ type Proxy interface {
 type proxyManager interface {
 	onReady(t string, p *proxy) error
 	kill(u string) error
+	invokeTimeout() time.Duration
+	onInvoke(t string, d time.Duration)
+	onTimeout(t string, err error)
 }
 
 type callFrame struct {
-	addr module.Address
-	ctx  CallContext
-	log  *trace.Logger
+	addr  module.Address
+	ctx   CallContext
+	log   *trace.Logger
+	start time.Time
 
 	prev *callFrame
 }
@@ -109,6 +115,11 @@ type proxy struct {
 
 	frame *callFrame
 
+	// deadline is increased whenever the timer for the response of
+	// the execution engine is set or cleared to ignore expired ones.
+	deadline int
+	timer    *time.Timer
+
 	next  *proxy
 	pprev **proxy
 }
@@ -233,13 +244,18 @@ func (p *proxy) Invoke(
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	p.frame = &callFrame{
-		addr: to,
-		ctx:  ctx,
-		log:  p.log,
-		prev: p.frame,
+		addr:  to,
+		ctx:   ctx,
+		log:   p.log,
+		start: time.Now(),
+		prev:  p.frame,
 	}
 	p.log = logger
-	return p.conn.Send(msgINVOKE, &m)
+	if err := p.conn.Send(msgINVOKE, &m); err != nil {
+		return err
+	}
+	p.setDeadlineInLock()
+	return nil
 }
 
 func (p *proxy) GetAPI(ctx CallContext, code string) error {
@@ -250,13 +266,18 @@ func (p *proxy) GetAPI(ctx CallContext, code string) error {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	p.frame = &callFrame{
-		addr: nil,
-		ctx:  ctx,
-		log:  p.log,
-		prev: p.frame,
+		addr:  nil,
+		ctx:   ctx,
+		log:   p.log,
+		start: time.Now(),
+		prev:  p.frame,
 	}
 	p.log = logger
-	return p.conn.Send(msgGETAPI, code)
+	if err := p.conn.Send(msgGETAPI, code); err != nil {
+		return err
+	}
+	p.setDeadlineInLock()
+	return nil
 }
 
 type resultMessage struct {
@@ -308,13 +329,73 @@ func (p *proxy) SendResult(ctx CallContext, status error, steps *big.Int, result
 	}
 	m.EID = eid
 	m.PrevEID = last
-	return p.conn.Send(msgRESULT, &m)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if err := p.conn.Send(msgRESULT, &m); err != nil {
+		return err
+	}
+	p.setDeadlineInLock()
+	return nil
+}
+
+// setDeadlineInLock sets the timer for the response of the execution engine.
+// If the execution engine doesn't respond in time, it's killed and the
+// current frame gets scoreresult.TimeoutError.
+func (p *proxy) setDeadlineInLock() {
+	p.clearDeadlineInLock()
+	d := p.mgr.invokeTimeout()
+	if d <= 0 {
+		return
+	}
+	deadline := p.deadline
+	p.timer = time.AfterFunc(d, func() {
+		p.onDeadline(deadline, d)
+	})
+}
+
+func (p *proxy) clearDeadlineInLock() {
+	p.deadline += 1
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+}
+
+func (p *proxy) onDeadline(deadline int, d time.Duration) {
+	l := common.LockForAutoCall(&p.lock)
+	defer l.Unlock()
+
+	if deadline != p.deadline || p.frame == nil || p.state >= stateStopped {
+		return
+	}
+	frame := p.frame
+	p.frame = nil
+	p.timer = nil
+	p.state = stateStopped
+	p.log.Warnf("Proxy[%p].onDeadline type=%s uid=%s timeout=%v",
+		p, p.scoreType, p.uid, d)
+
+	status := scoreresult.TimeoutError.Errorf(
+		"ExecutionTimeout(type=%s,uid=%s,timeout=%v)", p.scoreType, p.uid, d)
+	l.CallAfterUnlock(func() {
+		p.mgr.onTimeout(p.scoreType, status)
+		if err := p.mgr.kill(p.uid); err != nil {
+			p.log.Warnf("Proxy[%p].onDeadline fail to kill err=%+v", p, err)
+		}
+		if frame.addr == nil {
+			frame.ctx.OnAPI(status, nil)
+		} else {
+			frame.ctx.OnResult(status, new(big.Int), nil)
+		}
+	})
 }
 
 func (p *proxy) popFrame() *callFrame {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
+	p.clearDeadlineInLock()
 	if p.frame != nil {
 		frame := p.frame
 		p.log = frame.log
@@ -369,6 +450,7 @@ func (p *proxy) HandleMessage(c ipc.Connection, msg uint, data []byte) error {
 			status = m.Status.New(msg)
 			result = nil
 		}
+		p.mgr.onInvoke(p.scoreType, time.Since(frame.start))
 		frame.ctx.OnResult(status, &m.StepUsed.Int, result)
 
 		return p.tryToBeReady()
@@ -431,6 +513,9 @@ func (p *proxy) HandleMessage(c ipc.Connection, msg uint, data []byte) error {
 		}
 		p.log.Tracef("Proxy[%p].OnCall from=%v to=%v value=%v steplimit=%v method=%s",
 			p, p.frame.addr, &m.To, &m.Value.Int, &m.Limit.Int, m.Method)
+		p.lock.Lock()
+		p.clearDeadlineInLock()
+		p.lock.Unlock()
 		p.frame.ctx.OnCall(p.frame.addr,
 			&m.To, &m.Value.Int, &m.Limit.Int, m.Method, m.Params)
 		return nil
@@ -557,6 +642,7 @@ func (p *proxy) OnClose() {
 	l := common.LockForAutoCall(&p.lock)
 	defer l.Unlock()
 
+	p.clearDeadlineInLock()
 	if p.frame != nil && p.state == stateReserved {
 		frame := p.frame
 		status := errors.ExecutionFailError.New("ProxyIsClosed")
@@ -571,6 +657,7 @@ func (p *proxy) Kill() error {
 	defer p.lock.Unlock()
 
 	p.log.Warnf("Proxy[%p].Kill() type=%s uid=%s", p, p.scoreType, p.uid)
+	p.clearDeadlineInLock()
 	p.state = stateStopped
 	return p.mgr.kill(p.uid)
 }
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"sync"
+	"time"
 
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/errors"
@@ -54,6 +55,7 @@ type pythonExecutionEngine struct {
 	args      []string
 	target    int
 	instances map[string]*pythonInstance
+	backoff   restartBackoff
 	net, addr string
 	logger    log.Logger
 }
@@ -106,6 +108,7 @@ func (e *pythonExecutionEngine) OnAttach(uid string) bool {
 
 	if is, ok := e.instances[uid]; ok {
 		is.status = instanceOnline
+		e.backoff.reset()
 		return true
 	}
 	return false
@@ -147,6 +150,16 @@ func (e *pythonExecutionEngine) run(is *pythonInstance) {
 			is.uid, err)
 		e.term(is)
 
+		delay := e.backoff.delay()
+		e.lock.Unlock()
+		e.logger.Infof("Restart instance after %v", delay)
+		time.Sleep(delay)
+		e.lock.Lock()
+		if len(e.instances) >= e.target {
+			e.lock.Unlock()
+			return
+		}
+
 		e.init(is)
 		if err := e.start(is); err != nil {
 			e.logger.Errorf("Fail to start instance err=%+v", err)
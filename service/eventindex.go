@@ -0,0 +1,284 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"encoding/binary"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	keyEventIndexStart = "service.eventIndexStart"
+
+	eventTermSize     = 32
+	eventLocationSize = 16
+
+	// eventIndexPruneChunk is number of entries to delete at once.
+	eventIndexPruneChunk = 1000
+)
+
+// EventTermOf returns the term of the index for events of the signature.
+// Events emitted by any address are selected if addr is nil, and events
+// having the value as the indexed argument at idx are selected if idx is
+// positive.
+func EventTermOf(addr module.Address, sig []byte, idx int, value []byte) []byte {
+	var ab []byte
+	if addr != nil {
+		ab = addr.Bytes()
+	}
+	return crypto.SHA3Sum256(codec.BC.MustMarshalToBytes(
+		[]interface{}{ab, sig, idx, value}))
+}
+
+// eventTermsOf returns all terms of the event. An event can be found by
+// its signature, and its indexed arguments, with or without its address.
+func eventTermsOf(ev module.EventLog) [][]byte {
+	indexed := ev.Indexed()
+	if len(indexed) == 0 {
+		return nil
+	}
+	sig := indexed[0]
+	addr := ev.Address()
+	terms := make([][]byte, 0, len(indexed)*2)
+	terms = append(terms,
+		EventTermOf(nil, sig, 0, nil),
+		EventTermOf(addr, sig, 0, nil),
+	)
+	for i := 1; i < len(indexed); i++ {
+		terms = append(terms,
+			EventTermOf(nil, sig, i, indexed[i]),
+			EventTermOf(addr, sig, i, indexed[i]),
+		)
+	}
+	return terms
+}
+
+func eventLocationToBytes(bs []byte, loc *module.EventLocation) []byte {
+	binary.BigEndian.PutUint64(bs[0:8], uint64(loc.Height))
+	binary.BigEndian.PutUint32(bs[8:12], uint32(loc.TxIndex))
+	binary.BigEndian.PutUint32(bs[12:16], uint32(loc.EventIndex))
+	return bs
+}
+
+func eventLocationFromBytes(bs []byte) module.EventLocation {
+	return module.EventLocation{
+		Height:     int64(binary.BigEndian.Uint64(bs[0:8])),
+		TxIndex:    int(binary.BigEndian.Uint32(bs[8:12])),
+		EventIndex: int(binary.BigEndian.Uint32(bs[12:16])),
+	}
+}
+
+func eventIndexKey(term []byte, loc *module.EventLocation) []byte {
+	key := make([]byte, eventTermSize+eventLocationSize)
+	copy(key, term)
+	eventLocationToBytes(key[eventTermSize:], loc)
+	return key
+}
+
+// eventIndex maps terms of events to the locations of them, so events can
+// be found without scanning all blocks. Locations are in the keys, so the
+// entries of a term are sorted by the locations.
+//
+//	key   : term(32) | height(8) | txIndex(4) | eventIndex(4)
+//	value : empty
+//
+// The height of the first indexed block is stored in the chain property.
+type eventIndex struct {
+	database db.Database
+	bucket   db.Bucket
+	props    db.Bucket
+}
+
+func newEventIndex(database db.Database) (*eventIndex, error) {
+	bk, err := database.GetBucket(db.EventLogIndex)
+	if err != nil {
+		return nil, err
+	}
+	props, err := database.GetBucket(db.ChainProperty)
+	if err != nil {
+		return nil, err
+	}
+	return &eventIndex{
+		database: database,
+		bucket:   bk,
+		props:    props,
+	}, nil
+}
+
+// clearEventIndexStart removes the height of the first indexed block, so
+// the index starts again from the next block when it's enabled later.
+func clearEventIndexStart(database db.Database) error {
+	props, err := database.GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	return props.Delete([]byte(keyEventIndexStart))
+}
+
+// StartHeight returns the height of the first indexed block. It returns
+// false if no block has been indexed.
+func (ei *eventIndex) StartHeight() (int64, bool, error) {
+	bs, err := ei.props.Get([]byte(keyEventIndexStart))
+	if err != nil || bs == nil {
+		return 0, false, err
+	}
+	var height int64
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &height); err != nil {
+		return 0, false, err
+	}
+	return height, true, nil
+}
+
+// Add writes entries for the events in the receipts of the transactions in
+// the block of the height.
+func (ei *eventIndex) Add(height int64, rl module.ReceiptList) error {
+	batch := ei.database.NewBatch()
+	if _, ok, err := ei.StartHeight(); err != nil {
+		return err
+	} else if !ok {
+		batch.Set(db.ChainProperty, []byte(keyEventIndexStart),
+			codec.BC.MustMarshalToBytes(height))
+	}
+	if rl != nil {
+		txIndex := 0
+		for it := rl.Iterator(); it.Has(); it.Next() {
+			rct, err := it.Get()
+			if err != nil {
+				return err
+			}
+			eventIndex := 0
+			for eit := rct.EventLogIterator(); eit.Has(); eit.Next() {
+				ev, err := eit.Get()
+				if err != nil {
+					return err
+				}
+				loc := module.EventLocation{
+					Height:     height,
+					TxIndex:    txIndex,
+					EventIndex: eventIndex,
+				}
+				for _, term := range eventTermsOf(ev) {
+					batch.Set(db.EventLogIndex, eventIndexKey(term, &loc), []byte{})
+				}
+				eventIndex++
+			}
+			txIndex++
+		}
+	}
+	return batch.Write()
+}
+
+// Find returns locations of the events of the term in the blocks from the
+// height to the height. It returns up to limit locations after the location
+// if after isn't nil.
+func (ei *eventIndex) Find(term []byte, from, to int64, after *module.EventLocation, limit int) ([]module.EventLocation, error) {
+	if len(term) != eventTermSize {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidTerm(term=%#x)", term)
+	}
+	start := eventIndexKey(term, &module.EventLocation{Height: from})
+	if after != nil {
+		start = eventIndexKey(term, after)
+	}
+	iter := ei.bucket.Iterator(term, start)
+	defer iter.Release()
+
+	var locs []module.EventLocation
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != eventTermSize+eventLocationSize {
+			continue
+		}
+		loc := eventLocationFromBytes(key[eventTermSize:])
+		if after != nil && loc == *after {
+			continue
+		}
+		if loc.Height > to || len(locs) >= limit {
+			break
+		}
+		locs = append(locs, loc)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return locs, nil
+}
+
+// Prune removes entries for the blocks below the height. Entries are sorted
+// by terms, so it scans all entries of the index. The start height is moved
+// first, so the entries being removed aren't used for queries, and the
+// entries left by the interruption are removed by the next call.
+func (ei *eventIndex) Prune(height int64) error {
+	start, ok, err := ei.StartHeight()
+	if err != nil || !ok || start >= height {
+		return err
+	}
+	err = ei.props.Set([]byte(keyEventIndexStart),
+		codec.BC.MustMarshalToBytes(height))
+	if err != nil {
+		return err
+	}
+	var from []byte
+	for {
+		keys, next, err := ei.keysBelow(height, from)
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			batch := ei.database.NewBatch()
+			for _, key := range keys {
+				batch.Delete(db.EventLogIndex, key)
+			}
+			if err := batch.Write(); err != nil {
+				return err
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		from = next
+	}
+}
+
+// keysBelow returns up to eventIndexPruneChunk keys of the entries for the
+// blocks below the height from the key. It returns the key to continue
+// with, or nil if it reaches the end.
+func (ei *eventIndex) keysBelow(height int64, from []byte) ([][]byte, []byte, error) {
+	iter := ei.bucket.Iterator(nil, from)
+	defer iter.Release()
+
+	var keys [][]byte
+	var next []byte
+	for iter.Next() {
+		key := iter.Key()
+		if len(keys) >= eventIndexPruneChunk {
+			next = append([]byte{}, key...)
+			break
+		}
+		if len(key) != eventTermSize+eventLocationSize {
+			continue
+		}
+		if loc := eventLocationFromBytes(key[eventTermSize:]); loc.Height < height {
+			keys = append(keys, append([]byte{}, key...))
+		}
+	}
+	return keys, next, iter.Error()
+}
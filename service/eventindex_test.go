@@ -0,0 +1,134 @@
+package service
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+func TestEventIndex_AddAndFind(t *testing.T) {
+	database := db.NewMapDB()
+	ei, err := newEventIndex(database)
+	assert.NoError(t, err)
+
+	_, ok, err := ei.StartHeight()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	score := common.NewAddressFromString("cx0000000000000000000000000000000000000001")
+	from1 := common.NewAddressFromString("hx0000000000000000000000000000000000000001")
+	from2 := common.NewAddressFromString("hx0000000000000000000000000000000000000002")
+	sig := []byte("Transfer(Address,int)")
+
+	for height := int64(10); height < 15; height++ {
+		r1 := txresult.NewReceipt(database, module.LatestRevision, score)
+		r1.AddLog(score, [][]byte{sig, from1.Bytes()}, [][]byte{{0x01}})
+		r1.AddLog(score, [][]byte{sig, from2.Bytes()}, [][]byte{{0x02}})
+		r2 := txresult.NewReceipt(database, module.LatestRevision, score)
+		r2.AddLog(score, [][]byte{sig, from1.Bytes()}, [][]byte{{0x03}})
+		r1.SetResult(module.StatusSuccess, big.NewInt(100), big.NewInt(10), nil)
+		r2.SetResult(module.StatusSuccess, big.NewInt(100), big.NewInt(10), nil)
+		rl := txresult.NewReceiptListFromSlice(database, []txresult.Receipt{r1, r2})
+		assert.NoError(t, ei.Add(height, rl))
+	}
+
+	start, ok, err := ei.StartHeight()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 10, start)
+
+	// all events of the signature
+	locs, err := ei.Find(EventTermOf(nil, sig, 0, nil), 10, 14, nil, 100)
+	assert.NoError(t, err)
+	assert.Len(t, locs, 15)
+
+	// events of the indexed argument in the range
+	term := EventTermOf(score, sig, 1, from1.Bytes())
+	locs, err = ei.Find(term, 11, 12, nil, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []module.EventLocation{
+		{Height: 11, TxIndex: 0, EventIndex: 0},
+		{Height: 11, TxIndex: 1, EventIndex: 0},
+		{Height: 12, TxIndex: 0, EventIndex: 0},
+		{Height: 12, TxIndex: 1, EventIndex: 0},
+	}, locs)
+
+	// pages with the limit
+	locs, err = ei.Find(term, 11, 12, nil, 3)
+	assert.NoError(t, err)
+	assert.Len(t, locs, 3)
+	locs, err = ei.Find(term, 11, 12, &locs[2], 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []module.EventLocation{
+		{Height: 12, TxIndex: 1, EventIndex: 0},
+	}, locs)
+
+	// events from other address
+	other := common.NewAddressFromString("cx0000000000000000000000000000000000000002")
+	locs, err = ei.Find(EventTermOf(other, sig, 0, nil), 10, 14, nil, 100)
+	assert.NoError(t, err)
+	assert.Len(t, locs, 0)
+}
+
+func TestEventIndex_Prune(t *testing.T) {
+	database := db.NewMapDB()
+	ei, err := newEventIndex(database)
+	assert.NoError(t, err)
+
+	// nothing is indexed
+	assert.NoError(t, ei.Prune(10))
+	_, ok, err := ei.StartHeight()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	score := common.NewAddressFromString("cx0000000000000000000000000000000000000001")
+	sig := []byte("Event(int)")
+	for height := int64(10); height < 20; height++ {
+		var rs []txresult.Receipt
+		for i := 0; i < 50; i++ {
+			r := txresult.NewReceipt(database, module.LatestRevision, score)
+			r.AddLog(score, [][]byte{sig, {byte(i)}}, nil)
+			r.SetResult(module.StatusSuccess, big.NewInt(100), big.NewInt(10), nil)
+			rs = append(rs, r)
+		}
+		rl := txresult.NewReceiptListFromSlice(database, rs)
+		assert.NoError(t, ei.Add(height, rl))
+	}
+
+	countEntries := func() int {
+		iter := ei.bucket.Iterator(nil, nil)
+		defer iter.Release()
+		n := 0
+		for iter.Next() {
+			n++
+		}
+		return n
+	}
+	// 4 terms for each event
+	assert.Equal(t, 10*50*4, countEntries())
+
+	term := EventTermOf(nil, sig, 0, nil)
+	assert.NoError(t, ei.Prune(15))
+	start, ok, err := ei.StartHeight()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 15, start)
+	assert.Equal(t, 5*50*4, countEntries())
+	locs, err := ei.Find(term, 0, 19, nil, 1000)
+	assert.NoError(t, err)
+	assert.Len(t, locs, 5*50)
+	assert.EqualValues(t, 15, locs[0].Height)
+
+	// the start height isn't moved backward
+	assert.NoError(t, ei.Prune(12))
+	start, _, err = ei.StartHeight()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 15, start)
+	assert.Equal(t, 5*50*4, countEntries())
+}
@@ -0,0 +1,99 @@
+package goscore
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/scoredb"
+)
+
+// Score is a SCORE written in Go. It's executed in the process by the Go
+// execution engine, so it must not keep any state in the memory. All the
+// states should be stored through the Context.
+//
+// The API must have methods for "on_install" and "on_update" if it accepts
+// parameters on deploy.
+type Score interface {
+	GetAPI() *scoreapi.Info
+
+	// Invoke executes the method with the parameters. Parameters are decoded
+	// values of the types in the API (*common.HexInt, string, []byte, bool,
+	// module.Address, []interface{} or map[string]interface{}), and nil for
+	// the omitted ones.
+	Invoke(ctx Context, method string, params []interface{}) (interface{}, error)
+}
+
+// Context is the environment for executing the method of the SCORE. Steps
+// for accessing the storage and emitting events are charged to the caller.
+// It returns scoreresult.ErrOutOfStep if the steps exceed the limit.
+type Context interface {
+	scoredb.StateStore
+
+	// Address returns the address of the SCORE.
+	Address() module.Address
+
+	// From returns the address of the caller.
+	From() module.Address
+
+	// Value returns the value transferred to the SCORE.
+	Value() *big.Int
+
+	// IsQuery returns whether it's executed for read-only method.
+	IsQuery() bool
+
+	// Info returns information of the block and the transaction. Keys are
+	// same as the ones in state package (e.g. state.InfoBlockHeight).
+	Info() map[string]interface{}
+
+	GetBalance(addr module.Address) *big.Int
+
+	// Emit emits the event. The first one of indexed values is the signature
+	// of the event (e.g. "Transfer(Address,Address,int)").
+	Emit(indexed []interface{}, data []interface{}) error
+
+	// Call calls the method of the other contract with the parameters, and
+	// returns the result of it. If value is not zero, it's transferred to
+	// the contract.
+	Call(to module.Address, value *big.Int, method string, params ...interface{}) (interface{}, error)
+
+	// StepUsed returns the steps used so far including the calls.
+	StepUsed() *big.Int
+
+	Logger() log.Logger
+}
+
+var scoreRegistry = struct {
+	lock   sync.Mutex
+	scores map[string]Score
+}{
+	scores: make(map[string]Score),
+}
+
+// Register registers the SCORE with the name. The name is used as the content
+// of the deploy transaction with "application/x.score.go" content type. It's
+// usually called in init() of the package implementing the SCORE, so it
+// panics if the name is already registered.
+func Register(name string, score Score) {
+	scoreRegistry.lock.Lock()
+	defer scoreRegistry.lock.Unlock()
+
+	if _, ok := scoreRegistry.scores[name]; ok {
+		log.Panicf("DuplicateScore(name=%s)", name)
+	}
+	scoreRegistry.scores[name] = score
+}
+
+// Lookup returns the SCORE registered with the name.
+func Lookup(name string) (Score, error) {
+	scoreRegistry.lock.Lock()
+	defer scoreRegistry.lock.Unlock()
+
+	if score, ok := scoreRegistry.scores[name]; ok {
+		return score, nil
+	}
+	return nil, errors.NotFoundError.Errorf("ScoreNotRegistered(name=%s)", name)
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const keyInternalTxStart = "service.internalTxStart"
+
+// internalTxStore keeps internal transactions of the normal transactions
+// apart from the receipts, so they don't affect the result of the blocks.
+//
+//	key   : hash of the transaction
+//	value : list of internal transactions
+//
+// Only the transactions having internal transactions have entries. The
+// height of the first recorded block is stored in the chain property.
+type internalTxStore struct {
+	database db.Database
+	bucket   db.Bucket
+	props    db.Bucket
+}
+
+func newInternalTxStore(database db.Database) (*internalTxStore, error) {
+	bk, err := database.GetBucket(db.InternalTransactionsByHash)
+	if err != nil {
+		return nil, err
+	}
+	props, err := database.GetBucket(db.ChainProperty)
+	if err != nil {
+		return nil, err
+	}
+	return &internalTxStore{
+		database: database,
+		bucket:   bk,
+		props:    props,
+	}, nil
+}
+
+// clearInternalTxStart removes the height of the first recorded block, so
+// the recording starts again from the next block when it's enabled later.
+func clearInternalTxStart(database db.Database) error {
+	props, err := database.GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	return props.Delete([]byte(keyInternalTxStart))
+}
+
+// StartHeight returns the height of the first recorded block. It returns
+// false if no block has been recorded.
+func (s *internalTxStore) StartHeight() (int64, bool, error) {
+	bs, err := s.props.Get([]byte(keyInternalTxStart))
+	if err != nil || bs == nil {
+		return 0, false, err
+	}
+	var height int64
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &height); err != nil {
+		return 0, false, err
+	}
+	return height, true, nil
+}
+
+// Add writes internal transactions of the normal transactions in the block
+// of the height. itxs is indexed by the index of the transaction.
+func (s *internalTxStore) Add(height int64, txs module.TransactionList, itxs [][]module.InternalTransaction) error {
+	batch := s.database.NewBatch()
+	if _, ok, err := s.StartHeight(); err != nil {
+		return err
+	} else if !ok {
+		batch.Set(db.ChainProperty, []byte(keyInternalTxStart),
+			codec.BC.MustMarshalToBytes(height))
+	}
+	for idx, l := range itxs {
+		if len(l) == 0 {
+			continue
+		}
+		tx, err := txs.Get(idx)
+		if err != nil {
+			return err
+		}
+		batch.Set(db.InternalTransactionsByHash, tx.ID(),
+			txresult.InternalTransactionsToBytes(l))
+	}
+	return batch.Write()
+}
+
+// Get returns internal transactions of the transaction.
+func (s *internalTxStore) Get(id []byte) ([]module.InternalTransaction, error) {
+	bs, err := s.bucket.Get(id)
+	if err != nil || bs == nil {
+		return nil, err
+	}
+	return txresult.InternalTransactionsFromBytes(bs)
+}
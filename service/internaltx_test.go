@@ -0,0 +1,60 @@
+package service
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+func TestInternalTxStore_AddAndGet(t *testing.T) {
+	database := db.NewMapDB()
+	s, err := newInternalTxStore(database)
+	assert.NoError(t, err)
+
+	_, ok, err := s.StartHeight()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	addr1 := common.NewAddressFromString("hx1111111111111111111111111111111111111111")
+	score := common.NewAddressFromString("cx3333333333333333333333333333333333333333")
+
+	tx1 := newMockTransaction([]byte{1}, addr1, 0)
+	tx2 := newMockTransaction([]byte{2}, addr1, 0)
+	itxs := [][]module.InternalTransaction{
+		nil,
+		{
+			txresult.NewInternalTransaction(score, addr1, big.NewInt(7), 1),
+		},
+	}
+	assert.NoError(t, s.Add(10, mockTransactionList{tx1, tx2}, itxs))
+	assert.NoError(t, s.Add(11, mockTransactionList{}, nil))
+
+	start, ok, err := s.StartHeight()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 10, start)
+
+	l, err := s.Get(tx1.ID())
+	assert.NoError(t, err)
+	assert.Len(t, l, 0)
+
+	l, err = s.Get(tx2.ID())
+	assert.NoError(t, err)
+	if assert.Len(t, l, 1) {
+		assert.True(t, l[0].From().Equal(score))
+		assert.True(t, l[0].To().Equal(addr1))
+		assert.EqualValues(t, 7, l[0].Value().Int64())
+		assert.Equal(t, 1, l[0].Depth())
+	}
+
+	assert.NoError(t, clearInternalTxStart(database))
+	_, ok, err = s.StartHeight()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -50,6 +50,11 @@ type manager struct {
 	trc       *transitionResultCache
 	tsc       *TxTimestampChecker
 	syncer    *ssync.Manager
+	ei        *eventIndex
+	ti        *txIndex
+	its       *internalTxStore
+	sds       *stepDetailsStore
+	qc        *queryCache
 
 	log log.Logger
 
@@ -76,9 +81,9 @@ func NewManager(chain module.Chain, nm module.NetworkManager,
 		return nil, err
 	}
 	pTxPool := NewTransactionPool(module.TransactionGroupPatch,
-		chain.PatchTxPoolSize(), bk, pMetric, logger)
+		chain.PatchTxPoolSize(), TxOrderFIFO, bk, pMetric, logger)
 	nTxPool := NewTransactionPool(module.TransactionGroupNormal,
-		chain.NormalTxPoolSize(), bk, nMetric, logger)
+		chain.NormalTxPoolSize(), chain.TxPoolOrder(), bk, nMetric, logger)
 	tsc := NewTimestampChecker()
 	tm := NewTransactionManager(chain.NID(), tsc, pTxPool, nTxPool, bk, logger)
 	syncm := ssync.NewSyncManager(chain.Database(), chain.NetworkManager(), logger)
@@ -98,10 +103,40 @@ func NewManager(chain module.Chain, nm module.NetworkManager,
 			logger),
 		log: logger,
 		tsc: tsc,
+		qc: newQueryCache(chain.QueryCacheSize(),
+			metric.NewQueryCacheMetric(chain.MetricContext())),
 	}
 	if nm != nil {
 		mgr.txReactor = NewTransactionReactor(nm, tm)
 	}
+	if chain.EventIndex() {
+		if mgr.ei, err = newEventIndex(chain.Database()); err != nil {
+			return nil, err
+		}
+	} else if err := clearEventIndexStart(chain.Database()); err != nil {
+		return nil, err
+	}
+	if chain.TxIndex() {
+		if mgr.ti, err = newTxIndex(chain.Database()); err != nil {
+			return nil, err
+		}
+	} else if err := clearTxIndexStart(chain.Database()); err != nil {
+		return nil, err
+	}
+	if chain.InternalTx() {
+		if mgr.its, err = newInternalTxStore(chain.Database()); err != nil {
+			return nil, err
+		}
+	} else if err := clearInternalTxStart(chain.Database()); err != nil {
+		return nil, err
+	}
+	if chain.StepDetails() {
+		if mgr.sds, err = newStepDetailsStore(chain.Database()); err != nil {
+			return nil, err
+		}
+	} else if err := clearStepDetailsStart(chain.Database()); err != nil {
+		return nil, err
+	}
 	return mgr, nil
 }
 
@@ -278,6 +313,26 @@ func (m *manager) Finalize(t module.Transition, opt int) error {
 			if err := tst.finalizeResult(); err != nil {
 				return err
 			}
+			if m.ei != nil {
+				if err := m.ei.Add(tst.bi.Height(), tst.normalReceipts); err != nil {
+					return err
+				}
+			}
+			if m.ti != nil {
+				if err := m.ti.Add(tst.bi.Height(), tst.normalTransactions); err != nil {
+					return err
+				}
+			}
+			if m.its != nil && tst.syncer == nil {
+				if err := m.its.Add(tst.bi.Height(), tst.normalTransactions, tst.internalTxs); err != nil {
+					return err
+				}
+			}
+			if m.sds != nil && tst.syncer == nil {
+				if err := m.sds.Add(tst.bi.Height(), tst.normalTransactions, tst.stepDetails); err != nil {
+					return err
+				}
+			}
 			m.tm.NotifyFinalized(tst.patchTransactions, tst.patchReceipts, tst.normalTransactions, tst.normalReceipts)
 			now := time.Now()
 			m.patchMetric.OnFinalize(tst.patchTransactions.Hash(), now)
@@ -393,6 +448,18 @@ func (m *manager) WaitTransactionResult(id []byte) (<-chan interface{}, error) {
 	return m.tm.WaitResult(id)
 }
 
+func (m *manager) SubscribeTransactionPool(size int) (<-chan *module.TransactionPoolEvent, func()) {
+	return m.tm.Subscribe(size)
+}
+
+func (m *manager) GetPendingTransactions(from, to module.Address, limit int) []module.Transaction {
+	return m.tm.Transactions(from, to, limit)
+}
+
+func (m *manager) GetTransactionPoolStatus() []*module.TransactionPoolStatus {
+	return m.tm.Status()
+}
+
 func (m *manager) SendTransaction(txi interface{}) ([]byte, error) {
 	newTx, err := newTransaction(txi)
 	if err != nil {
@@ -415,9 +482,10 @@ func (m *manager) Call(resultHash []byte,
 	vl module.ValidatorList, js []byte, bi module.BlockInfo,
 ) (interface{}, error) {
 	type callJSON struct {
-		To       common.Address  `json:"to"`
-		DataType *string         `json:"dataType"`
-		Data     json.RawMessage `json:"data"`
+		To            common.Address  `json:"to"`
+		DataType      *string         `json:"dataType"`
+		Data          json.RawMessage `json:"data"`
+		StateOverride json.RawMessage `json:"stateOverride"`
 	}
 
 	var jso callJSON
@@ -428,10 +496,37 @@ func (m *manager) Call(resultHash []byte,
 		return nil, InvalidQueryError.New("InvalidDataType")
 	}
 
-	var wc state.WorldContext
-	if wss, err := m.trc.GetWorldSnapshot(resultHash, vl.Hash()); err == nil {
-		ws := state.NewReadOnlyWorldState(wss)
-		wc = state.NewWorldContext(ws, bi)
+	so, err := parseStateOverride(jso.StateOverride)
+	if err != nil {
+		return nil, err
+	}
+	if err := so.checkNoCode(); err != nil {
+		return nil, err
+	}
+
+	// results with overridden states are not cached
+	var key string
+	if m.qc != nil && len(so) == 0 {
+		key = m.queryCacheKeyOf(resultHash, vl, &jso.To, jso.Data)
+		if len(key) > 0 {
+			if value, ok := m.qc.Get(bi.Height(), key); ok {
+				return value, nil
+			}
+		}
+	}
+
+	var ctx contract.Context
+	if wss, release, err := m.getQueryWorldSnapshot(resultHash, vl.Hash()); err == nil {
+		defer release()
+		if len(so) > 0 {
+			if ctx, err = m.newContextWithOverride(wss, bi, so); err != nil {
+				return nil, err
+			}
+		} else {
+			ws := state.NewReadOnlyWorldState(wss)
+			wc := state.NewWorldContext(ws, bi)
+			ctx = contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil)
+		}
 	} else {
 		return nil, err
 	}
@@ -440,7 +535,29 @@ func (m *manager) Call(resultHash []byte,
 	if err != nil {
 		return nil, err
 	}
-	return qh.Query(contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil))
+	value, err := qh.Query(ctx)
+	if err == nil && len(key) > 0 {
+		m.qc.Put(bi.Height(), key, value)
+	}
+	return value, err
+}
+
+// queryCacheKeyOf returns the key of the query cache for the call on
+// the result. It returns empty string if the call can't be cached.
+func (m *manager) queryCacheKeyOf(result []byte, vl module.ValidatorList, to module.Address, data []byte) string {
+	tr, err := newTransitionResultFromBytes(result)
+	if err != nil || len(tr.StateHash) == 0 {
+		return ""
+	}
+	var vh []byte
+	if vl != nil {
+		vh = vl.Hash()
+	}
+	key, err := queryCacheKey(tr.StateHash, vh, to, data)
+	if err != nil {
+		return ""
+	}
+	return key
 }
 
 func (m *manager) ValidatorListFromHash(hash []byte) module.ValidatorList {
@@ -448,11 +565,72 @@ func (m *manager) ValidatorListFromHash(hash []byte) module.ValidatorList {
 	return valList
 }
 
+// getQueryWorldSnapshot returns the world snapshot of the result for queries.
+// If the database supports snapshots, then the world snapshot is built on
+// a point-in-time view of the database, so that writes during the query
+// can't affect it. Node caches of the database are used for the snapshot
+// too. The returned function should be called after use.
+func (m *manager) getQueryWorldSnapshot(result []byte, vh []byte) (state.WorldSnapshot, func(), error) {
+	if err := m.checkStateOfResult(result); err != nil {
+		return nil, nil, err
+	}
+	snapshot, err := db.NewSnapshot(m.db)
+	if err != nil {
+		wss, err := m.trc.GetWorldSnapshot(result, vh)
+		return wss, func() {}, err
+	}
+	wss, err := func() (state.WorldSnapshot, error) {
+		tr, err := newTransitionResultFromBytes(result)
+		if err != nil {
+			return nil, err
+		}
+		wss := state.NewWorldSnapshot(snapshot, tr.StateHash, nil)
+		if len(vh) > 0 {
+			vss, err := state.ValidatorSnapshotFromHash(snapshot, vh)
+			if err != nil {
+				return nil, err
+			}
+			wss = state.NewWorldSnapshotWithNewValidators(snapshot, wss, vss)
+		}
+		return wss, nil
+	}()
+	if err != nil {
+		snapshot.Release()
+		return nil, nil, err
+	}
+	return wss, snapshot.Release, nil
+}
+
+// checkStateOfResult returns PrunedStateError if the world state of the
+// result is not in the database. Old states may be removed by pruning.
+func (m *manager) checkStateOfResult(result []byte) error {
+	if len(result) == 0 {
+		return nil
+	}
+	tr, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return err
+	}
+	if len(tr.StateHash) == 0 {
+		return nil
+	}
+	bk, err := m.db.GetBucket(db.MerkleTrie)
+	if err != nil {
+		return err
+	}
+	if !bk.Has(tr.StateHash) {
+		return PrunedStateError.Errorf(
+			"StateIsPruned(root=%#x)", tr.StateHash)
+	}
+	return nil
+}
+
 func (m *manager) GetBalance(result []byte, addr module.Address) (*big.Int, error) {
-	wss, err := m.trc.GetWorldSnapshot(result, nil)
+	wss, release, err := m.getQueryWorldSnapshot(result, nil)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	ass := wss.GetAccountSnapshot(addr.ID())
 	if ass == nil {
 		return big.NewInt(0), nil
@@ -461,10 +639,11 @@ func (m *manager) GetBalance(result []byte, addr module.Address) (*big.Int, erro
 }
 
 func (m *manager) GetTotalSupply(result []byte) (*big.Int, error) {
-	wss, err := m.trc.GetWorldSnapshot(result, nil)
+	wss, release, err := m.getQueryWorldSnapshot(result, nil)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	ass := wss.GetAccountSnapshot(state.SystemID)
 	as := scoredb.NewStateStoreWith(ass)
 	tsVar := scoredb.NewVarDB(as, state.VarTotalSupply)
@@ -475,6 +654,28 @@ func (m *manager) GetTotalSupply(result []byte) (*big.Int, error) {
 	return big.NewInt(0), nil
 }
 
+func (m *manager) GetProofForAccount(result []byte, addr module.Address) ([]byte, [][]byte, error) {
+	wss, release, err := m.getQueryWorldSnapshot(result, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	return state.AccountProofOf(wss, addr.ID())
+}
+
+func (m *manager) GetProofForStorage(result []byte, addr module.Address, key []byte) ([]byte, [][]byte, error) {
+	wss, release, err := m.getQueryWorldSnapshot(result, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	ass := wss.GetAccountSnapshot(addr.ID())
+	if ass == nil {
+		return nil, nil, errors.NotFoundError.Errorf("AccountNotFound(addr=%s)", addr)
+	}
+	return state.StorageProofOf(ass, key)
+}
+
 func (m *manager) GetNetworkID(result []byte) (int64, error) {
 	wss, err := m.trc.GetWorldSnapshot(result, nil)
 	if err != nil {
@@ -507,10 +708,11 @@ func (m *manager) GetAPIInfo(result []byte, addr module.Address) (module.APIInfo
 	if !addr.IsContract() {
 		return nil, NotContractAddressError.Errorf("Given Address(%s) isn't contract", addr)
 	}
-	wss, err := m.trc.GetWorldSnapshot(result, nil)
+	wss, release, err := m.getQueryWorldSnapshot(result, nil)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	ass := wss.GetAccountSnapshot(addr.ID())
 	if ass == nil {
 		return nil, NoActiveContractError.Errorf("No account for %s", addr)
@@ -561,6 +763,90 @@ func (m *manager) GetMinimizeBlockGen(result []byte) bool {
 	return scoredb.NewVarDB(as, state.VarMinimizeBlockGen).Bool()
 }
 
+func (m *manager) GetEventIndexStart() (int64, error) {
+	if m.ei == nil {
+		return 0, errors.UnsupportedError.New("EventIndexDisabled")
+	}
+	height, ok, err := m.ei.StartHeight()
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, errors.NotFoundError.New("NoIndexedBlock")
+	}
+	return height, nil
+}
+
+func (m *manager) FindEvents(term []byte, from, to int64, after *module.EventLocation, limit int) ([]module.EventLocation, error) {
+	if m.ei == nil {
+		return nil, errors.UnsupportedError.New("EventIndexDisabled")
+	}
+	return m.ei.Find(term, from, to, after, limit)
+}
+
+func (m *manager) GetTransactionIndexStart() (int64, error) {
+	if m.ti == nil {
+		return 0, errors.UnsupportedError.New("TransactionIndexDisabled")
+	}
+	height, ok, err := m.ti.StartHeight()
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, errors.NotFoundError.New("NoIndexedBlock")
+	}
+	return height, nil
+}
+
+func (m *manager) FindTransactions(addr module.Address, before *module.TransactionLocation, limit int) ([]module.TransactionLocation, error) {
+	if m.ti == nil {
+		return nil, errors.UnsupportedError.New("TransactionIndexDisabled")
+	}
+	return m.ti.Find(addr, before, limit)
+}
+
+func (m *manager) PruneTransactionIndex(height int64) error {
+	if m.ti == nil {
+		return nil
+	}
+	return m.ti.Prune(height, m.chain.BlockManager())
+}
+
+func (m *manager) PruneEventIndex(height int64) error {
+	if m.ei == nil {
+		return nil
+	}
+	return m.ei.Prune(height)
+}
+
+func (m *manager) GetInternalTransactions(height int64, id []byte) ([]module.InternalTransaction, error) {
+	if m.its == nil {
+		return nil, errors.UnsupportedError.New("InternalTransactionDisabled")
+	}
+	start, ok, err := m.its.StartHeight()
+	if err != nil {
+		return nil, err
+	}
+	if !ok || height < start {
+		return nil, errors.NotFoundError.Errorf("NotRecorded(height=%d)", height)
+	}
+	return m.its.Get(id)
+}
+
+func (m *manager) GetStepUsedDetails(height int64, id []byte) (map[string]*big.Int, error) {
+	if m.sds == nil {
+		return nil, errors.UnsupportedError.New("StepDetailsDisabled")
+	}
+	start, ok, err := m.sds.StartHeight()
+	if err != nil {
+		return nil, err
+	}
+	if !ok || height < start {
+		return nil, errors.NotFoundError.Errorf("NotRecorded(height=%d)", height)
+	}
+	return m.sds.Get(id)
+}
+
 func (m *manager) HasTransaction(id []byte) bool {
 	return m.tm.HasTx(id)
 }
@@ -602,6 +888,10 @@ func (m *manager) ImportResult(result []byte, vh []byte, src db.Database) error
 }
 
 func (m *manager) ExecuteTransaction(result []byte, vh []byte, js []byte, bi module.BlockInfo) (module.Receipt, error) {
+	js, so, err := splitStateOverride(js)
+	if err != nil {
+		return nil, err
+	}
 	tx, err := transaction.NewTransactionFromJSON(js)
 	if err != nil {
 		return nil, err
@@ -616,17 +906,23 @@ func (m *manager) ExecuteTransaction(result []byte, vh []byte, js []byte, bi mod
 	}
 	defer txh.Dispose()
 
-	var wc state.WorldContext
+	var ctx contract.Context
 	if wss, err := m.trc.GetWorldSnapshot(result, vh); err == nil {
-		ws, err := state.WorldStateFromSnapshot(wss)
-		if err != nil {
-			return nil, err
+		if len(so) > 0 {
+			if ctx, err = m.newContextWithOverride(wss, bi, so); err != nil {
+				return nil, err
+			}
+		} else {
+			ws, err := state.WorldStateFromSnapshot(wss)
+			if err != nil {
+				return nil, err
+			}
+			wc := state.NewWorldContext(ws, bi)
+			ctx = contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil)
 		}
-		wc = state.NewWorldContext(ws, bi)
 	} else {
 		return nil, err
 	}
-	ctx := contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil)
 	ctx.SetTransactionInfo(&state.TransactionInfo{
 		Group:     module.TransactionGroupNormal,
 		Index:     0,
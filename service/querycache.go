@@ -0,0 +1,131 @@
+package service
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/json"
+	"sync"
+
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+// queryCacheItemOverhead is the estimated memory for an item of the cache
+// except the key and the value.
+const queryCacheItemOverhead = 128
+
+type queryCacheItem struct {
+	key   string
+	value interface{}
+	size  int
+}
+
+// queryCache keeps the results of read-only calls for the last block in
+// LRU order. Results may depend on the block, so it drops all the items
+// whenever a call for the new block is requested. Calls for the previous
+// blocks are not cached.
+type queryCache struct {
+	lock   sync.Mutex
+	budget int
+	used   int
+	height int64
+	lru    list.List
+	items  map[string]*list.Element
+	metric *metric.QueryCacheMetric
+}
+
+// queryCacheKey returns the key for the call on the state. Whitespaces in
+// the data are removed, so the same calls share the result.
+func queryCacheKey(root, vh []byte, to module.Address, data []byte) (string, error) {
+	buf := bytes.NewBuffer(nil)
+	buf.Write(root)
+	buf.Write(vh)
+	buf.Write(to.Bytes())
+	if err := json.Compact(buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// checkHeightInLock returns whether the height is for the last block.
+// It drops all the items if the height is for the new block.
+func (c *queryCache) checkHeightInLock(height int64) bool {
+	if height < c.height {
+		return false
+	}
+	if height > c.height {
+		c.height = height
+		c.lru.Init()
+		c.items = make(map[string]*list.Element)
+		c.used = 0
+		c.metric.OnBytes(c.used)
+	}
+	return true
+}
+
+// Get returns the result of the call. The result is shared, so it must
+// not be modified.
+func (c *queryCache) Get(height int64, key string) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.checkHeightInLock(height) {
+		if e, ok := c.items[key]; ok {
+			c.lru.MoveToBack(e)
+			c.metric.OnHit()
+			return e.Value.(*queryCacheItem).value, true
+		}
+	}
+	c.metric.OnMiss()
+	return nil, false
+}
+
+func (c *queryCache) Put(height int64, key string, value interface{}) {
+	bs, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	size := len(key) + len(bs) + queryCacheItemOverhead
+	if size > c.budget {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.checkHeightInLock(height) {
+		return
+	}
+	if _, ok := c.items[key]; ok {
+		return
+	}
+	c.items[key] = c.lru.PushBack(&queryCacheItem{
+		key:   key,
+		value: value,
+		size:  size,
+	})
+	c.used += size
+	for c.used > c.budget {
+		e := c.lru.Front()
+		item := e.Value.(*queryCacheItem)
+		c.lru.Remove(e)
+		delete(c.items, item.key)
+		c.used -= item.size
+	}
+	c.metric.OnBytes(c.used)
+}
+
+// newQueryCache returns the cache using the memory up to budget bytes.
+// It returns nil if the budget is not positive.
+func newQueryCache(budget int, m *metric.QueryCacheMetric) *queryCache {
+	if budget <= 0 {
+		return nil
+	}
+	c := &queryCache{
+		budget: budget,
+		items:  make(map[string]*list.Element),
+		metric: m,
+	}
+	c.lru.Init()
+	return c
+}
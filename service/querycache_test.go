@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+func TestQueryCacheKey(t *testing.T) {
+	to := common.NewAddressFromString("cx0000000000000000000000000000000000000001")
+	root, vh := []byte{1, 2}, []byte{3}
+
+	k1, err := queryCacheKey(root, vh, to, []byte(`{"method":"get","params":{"a":"0x1"}}`))
+	assert.NoError(t, err)
+	k2, err := queryCacheKey(root, vh, to, []byte("{\n \"method\": \"get\",\n \"params\": { \"a\": \"0x1\" }\n}"))
+	assert.NoError(t, err)
+	assert.Equal(t, k1, k2)
+
+	k3, err := queryCacheKey([]byte{1, 3}, vh, to, []byte(`{"method":"get","params":{"a":"0x1"}}`))
+	assert.NoError(t, err)
+	assert.NotEqual(t, k1, k3)
+
+	_, err = queryCacheKey(root, vh, to, []byte(`{"method":`))
+	assert.Error(t, err)
+}
+
+func TestQueryCache_Basic(t *testing.T) {
+	assert.Nil(t, newQueryCache(0, nil))
+
+	qc := newQueryCache(1024, metric.NewQueryCacheMetric(metric.DefaultMetricContext()))
+
+	_, ok := qc.Get(10, "k1")
+	assert.False(t, ok)
+	qc.Put(10, "k1", "v1")
+	v, ok := qc.Get(10, "k1")
+	assert.True(t, ok)
+	assert.Equal(t, "v1", v)
+
+	// calls for the previous block are not cached
+	_, ok = qc.Get(9, "k1")
+	assert.False(t, ok)
+	qc.Put(9, "k2", "v2")
+	_, ok = qc.Get(10, "k2")
+	assert.False(t, ok)
+
+	// the new block drops all the results
+	_, ok = qc.Get(11, "k1")
+	assert.False(t, ok)
+	assert.Equal(t, 0, qc.used)
+	qc.Put(10, "k1", "v1")
+	_, ok = qc.Get(11, "k1")
+	assert.False(t, ok)
+}
+
+func TestQueryCache_Budget(t *testing.T) {
+	size := len("k1") + len(`"v1"`) + queryCacheItemOverhead
+	qc := newQueryCache(size*2, metric.NewQueryCacheMetric(metric.DefaultMetricContext()))
+
+	qc.Put(1, "k1", "v1")
+	qc.Put(1, "k2", "v2")
+	assert.Equal(t, size*2, qc.used)
+
+	// k1 is used recently, so k2 is evicted
+	_, ok := qc.Get(1, "k1")
+	assert.True(t, ok)
+	qc.Put(1, "k3", "v3")
+	assert.Equal(t, size*2, qc.used)
+	_, ok = qc.Get(1, "k2")
+	assert.False(t, ok)
+	_, ok = qc.Get(1, "k1")
+	assert.True(t, ok)
+	_, ok = qc.Get(1, "k3")
+	assert.True(t, ok)
+
+	// too large result isn't cached
+	qc.Put(1, "k4", string(make([]byte, size*2)))
+	_, ok = qc.Get(1, "k4")
+	assert.False(t, ok)
+	assert.Equal(t, 2, qc.lru.Len())
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"math/big"
+
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+// balanceTracker keeps the addresses related to the transactions in the
+// order of appearance to report changes of their balances.
+type balanceTracker struct {
+	addrs []module.Address
+	seen  map[string]bool
+}
+
+func (bt *balanceTracker) add(addrs ...module.Address) {
+	for _, addr := range addrs {
+		if addr == nil {
+			continue
+		}
+		key := string(addr.Bytes())
+		if bt.seen[key] {
+			continue
+		}
+		if bt.seen == nil {
+			bt.seen = make(map[string]bool)
+		}
+		bt.seen[key] = true
+		bt.addrs = append(bt.addrs, addr)
+	}
+}
+
+func (bt *balanceTracker) addReceipt(rct txresult.Receipt) {
+	bt.add(rct.To(), rct.SCOREAddress())
+	for _, itx := range rct.InternalTransactions() {
+		bt.add(itx.From(), itx.To())
+	}
+}
+
+// changes returns the changes of the balances from the snapshot.
+func (bt *balanceTracker) changes(wss state.WorldSnapshot, ws state.WorldState) []module.BalanceChange {
+	var changes []module.BalanceChange
+	for _, addr := range bt.addrs {
+		before := new(big.Int)
+		if ass := wss.GetAccountSnapshot(addr.ID()); ass != nil {
+			before.Set(ass.GetBalance())
+		}
+		after := new(big.Int).Set(ws.GetAccountState(addr.ID()).GetBalance())
+		if before.Cmp(after) != 0 {
+			changes = append(changes, module.BalanceChange{
+				Address: addr,
+				Before:  before,
+				After:   after,
+			})
+		}
+	}
+	return changes
+}
+
+func (m *manager) simulateTransaction(ctx contract.Context, idx int, js []byte) (txresult.Receipt, module.Address, error) {
+	tx, err := transaction.NewTransactionFromJSON(js)
+	if err != nil {
+		return nil, nil, InvalidQueryError.Wrapf(err, "InvalidTransaction(index=%d)", idx)
+	}
+	if err := tx.Verify(); err != nil && !transaction.InvalidSignatureError.Equals(err) {
+		return nil, nil, InvalidQueryError.Wrapf(err, "InvalidTransaction(index=%d)", idx)
+	}
+
+	txh, err := tx.GetHandler(m.cm)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer txh.Dispose()
+
+	ctx.SetTransactionInfo(&state.TransactionInfo{
+		Group:     module.TransactionGroupNormal,
+		Index:     int32(idx),
+		Hash:      tx.ID(),
+		From:      tx.From(),
+		Timestamp: tx.Timestamp(),
+		Nonce:     tx.Nonce(),
+	})
+	ctx.UpdateSystemInfo()
+
+	rct, err := txh.Execute(ctx, true)
+	return rct, tx.From(), err
+}
+
+func (m *manager) SimulateTransactions(result []byte, vh []byte, txs [][]byte, bi module.BlockInfo) (*module.SimulationResult, error) {
+	wss, err := m.trc.GetWorldSnapshot(result, vh)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := state.WorldStateFromSnapshot(wss)
+	if err != nil {
+		return nil, err
+	}
+	ctx := contract.NewContext(state.NewWorldContext(ws, bi),
+		m.cm, m.eem, m.chain, m.log, nil)
+
+	var bt balanceTracker
+	receipts := make([]module.Receipt, 0, len(txs))
+	cumulativeSteps := new(big.Int)
+	gatheredFee := new(big.Int)
+	for idx, js := range txs {
+		rct, from, err := m.simulateTransaction(ctx, idx, js)
+		if err != nil {
+			return nil, err
+		}
+		used := rct.StepUsed()
+		cumulativeSteps.Add(cumulativeSteps, used)
+		rct.SetCumulativeStepUsed(cumulativeSteps)
+		gatheredFee.Add(gatheredFee, new(big.Int).Mul(rct.StepPrice(), used))
+
+		bt.add(from)
+		bt.addReceipt(rct)
+		receipts = append(receipts, rct)
+	}
+
+	// save gathered fee to treasury as the transition does
+	treasury := ctx.Treasury()
+	tr := ctx.GetAccountState(treasury.ID())
+	tr.SetBalance(new(big.Int).Add(tr.GetBalance(), gatheredFee))
+	bt.add(treasury)
+
+	return &module.SimulationResult{
+		Receipts: receipts,
+		Changes:  bt.changes(wss, ws),
+	}, nil
+}
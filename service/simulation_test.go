@@ -0,0 +1,166 @@
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+func TestBalanceTracker_Changes(t *testing.T) {
+	addr1 := common.NewAddressFromString("hx1111111111111111111111111111111111111111")
+	addr2 := common.NewAddressFromString("hx2222222222222222222222222222222222222222")
+	addr3 := common.NewAddressFromString("hx3333333333333333333333333333333333333333")
+
+	ws := state.NewWorldState(db.NewMapDB(), nil, nil)
+	ws.GetAccountState(addr1.ID()).SetBalance(big.NewInt(100))
+	ws.GetAccountState(addr3.ID()).SetBalance(big.NewInt(5))
+	wss := ws.GetSnapshot()
+
+	ws.GetAccountState(addr1.ID()).SetBalance(big.NewInt(70))
+	ws.GetAccountState(addr2.ID()).SetBalance(big.NewInt(30))
+
+	var bt balanceTracker
+	bt.add(addr2, nil, addr1)
+	bt.add(addr3, addr2)
+	changes := bt.changes(wss, ws)
+
+	// unchanged one is skipped, and the order of appearance is kept
+	if assert.Len(t, changes, 2) {
+		assert.True(t, changes[0].Address.Equal(addr2))
+		assert.EqualValues(t, 0, changes[0].Before.Int64())
+		assert.EqualValues(t, 30, changes[0].After.Int64())
+		assert.True(t, changes[1].Address.Equal(addr1))
+		assert.EqualValues(t, 100, changes[1].Before.Int64())
+		assert.EqualValues(t, 70, changes[1].After.Int64())
+	}
+}
+
+const (
+	simulationTestStepPrice = 10
+	simulationTestSteps     = 100
+	simulationTestFee       = simulationTestStepPrice * simulationTestSteps
+)
+
+// newSimulationTestManager returns the manager with the result of the state
+// having the balances. Each transaction uses simulationTestSteps.
+func newSimulationTestManager(t *testing.T, dir string, balances map[module.Address]int64) (*manager, []byte) {
+	database := db.NewMapDB()
+	ws := state.NewWorldState(database, nil, nil)
+	sys := ws.GetAccountState(state.SystemID)
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarRevision).Set(module.LatestRevision))
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarStepPrice).Set(simulationTestStepPrice))
+	assert.NoError(t, scoredb.NewArrayDB(sys, state.VarStepTypes).Put(state.StepTypeDefault))
+	assert.NoError(t, scoredb.NewDictDB(sys, state.VarStepCosts, 1).Set(state.StepTypeDefault, simulationTestSteps))
+	assert.NoError(t, scoredb.NewArrayDB(sys, state.VarStepLimitTypes).Put(transaction.LimitTypeInvoke))
+	assert.NoError(t, scoredb.NewDictDB(sys, state.VarStepLimit, 1).Set(transaction.LimitTypeInvoke, 100000))
+	for addr, balance := range balances {
+		ws.GetAccountState(addr.ID()).SetBalance(big.NewInt(balance))
+	}
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+
+	logger := log.New()
+	cm, err := contract.NewContractManager(database, dir, logger)
+	assert.NoError(t, err)
+	m := &manager{
+		cm:  cm,
+		trc: newTransitionResultCache(database, 2, 10, logger),
+		log: logger,
+	}
+	result := &transitionResult{StateHash: wss.StateHash()}
+	return m, result.Bytes()
+}
+
+func simulationTestTransfer(from, to module.Address, value int64) []byte {
+	return []byte(fmt.Sprintf(`{
+		"version":"0x3",
+		"from":"%s",
+		"to":"%s",
+		"value":"%#x",
+		"stepLimit":"0x100000",
+		"timestamp":"0x1",
+		"nid":"0x1"
+	}`, from, to, value))
+}
+
+func TestManager_SimulateTransactions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "simulation")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	addr1 := common.NewAddressFromString("hx1111111111111111111111111111111111111111")
+	addr2 := common.NewAddressFromString("hx2222222222222222222222222222222222222222")
+	addr3 := common.NewAddressFromString("hx3333333333333333333333333333333333333333")
+	m, result := newSimulationTestManager(t, dir, map[module.Address]int64{
+		addr1: 10000,
+	})
+
+	res, err := m.SimulateTransactions(result, nil, [][]byte{
+		simulationTestTransfer(addr1, addr2, 6000),
+		// it fails for the balance, but the fee is charged
+		simulationTestTransfer(addr2, addr3, 9999),
+		// it uses the balance transferred by the first one
+		simulationTestTransfer(addr2, addr3, 3000),
+	}, common.NewBlockInfo(2, 0))
+	assert.NoError(t, err)
+
+	if assert.Len(t, res.Receipts, 3) {
+		for i, status := range []module.Status{
+			module.StatusSuccess,
+			module.StatusOutOfBalance,
+			module.StatusSuccess,
+		} {
+			rct := res.Receipts[i]
+			assert.Equal(t, status, rct.Status(), i)
+			assert.EqualValues(t, simulationTestSteps, rct.StepUsed().Int64(), i)
+			assert.EqualValues(t, simulationTestStepPrice, rct.StepPrice().Int64(), i)
+			assert.EqualValues(t, simulationTestSteps*(i+1), rct.CumulativeStepUsed().Int64(), i)
+		}
+	}
+
+	// changes are in the order of appearance with the fee of the treasury
+	treasury := common.NewAddressFromString("hx1000000000000000000000000000000000000000")
+	expected := []struct {
+		addr          module.Address
+		before, after int64
+	}{
+		{addr1, 10000, 10000 - 6000 - simulationTestFee},
+		{addr2, 0, 6000 - 3000 - 2*simulationTestFee},
+		{addr3, 0, 3000},
+		{treasury, 0, 3 * simulationTestFee},
+	}
+	if assert.Len(t, res.Changes, len(expected)) {
+		for i, e := range expected {
+			c := res.Changes[i]
+			assert.True(t, c.Address.Equal(e.addr), i)
+			assert.EqualValues(t, e.before, c.Before.Int64(), i)
+			assert.EqualValues(t, e.after, c.After.Int64(), i)
+		}
+	}
+
+	// the state of the result isn't changed
+	wss, err := m.trc.GetWorldSnapshot(result, nil)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 10000, wss.GetAccountSnapshot(addr1.ID()).GetBalance().Int64())
+	assert.Nil(t, wss.GetAccountSnapshot(addr2.ID()))
+
+	// an invalid transaction fails the simulation with its index
+	_, err = m.SimulateTransactions(result, nil, [][]byte{
+		simulationTestTransfer(addr1, addr2, 1),
+		simulationTestTransfer(addr1, addr2, -1),
+	}, common.NewBlockInfo(2, 0))
+	assert.True(t, InvalidQueryError.Equals(err))
+}
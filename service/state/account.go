@@ -47,6 +47,8 @@ type AccountSnapshot interface {
 	IsDisabled() bool
 	IsBlocked() bool
 	ContractOwner() module.Address
+	GetDeposit() *big.Int
+	GetFeeProportion() int
 
 	GetObjGraph(flags bool) (int, []byte, []byte, error)
 }
@@ -83,6 +85,10 @@ type AccountState interface {
 	SetBlock(b bool)
 	IsBlocked() bool
 	ContractOwner() module.Address
+	GetDeposit() *big.Int
+	SetDeposit(v *big.Int)
+	GetFeeProportion() int
+	SetFeeProportion(p int)
 
 	GetObjGraph(flags bool) (int, []byte, []byte, error)
 	SetObjGraph(flags bool, nextHash int, objGraph []byte) error
@@ -102,12 +108,21 @@ type accountSnapshotImpl struct {
 	nextContract  *contractSnapshotImpl
 
 	objGraph *objectGraph
+	deposit  *depositInfo
 }
 
 func (s *accountSnapshotImpl) ContractOwner() module.Address {
 	return s.contractOwner
 }
 
+func (s *accountSnapshotImpl) GetDeposit() *big.Int {
+	return s.deposit.amount()
+}
+
+func (s *accountSnapshotImpl) GetFeeProportion() int {
+	return s.deposit.proportion()
+}
+
 func (s *accountSnapshotImpl) Version() int {
 	return s.version
 }
@@ -235,6 +250,9 @@ func (s *accountSnapshotImpl) Equal(object trie.Object) bool {
 		if s.objGraph.Equal(s2.objGraph) == false {
 			return false
 		}
+		if s.deposit.Equal(s2.deposit) == false {
+			return false
+		}
 		if s.store == s2.store {
 			return true
 		}
@@ -355,6 +373,16 @@ func (s *accountSnapshotImpl) RLPEncodeSelf(e codec.Encoder) error {
 		); err != nil {
 			return err
 		}
+	} else if s.deposit != nil {
+		// placeholder of the object graph for the deposit
+		if err := e2.EncodeMulti(0, nil); err != nil {
+			return err
+		}
+	}
+	if s.deposit != nil {
+		if err := e2.Encode(s.deposit); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -388,11 +416,20 @@ func (s *accountSnapshotImpl) RLPDecodeSelf(d codec.Decoder) error {
 		return errors.Wrap(err, "Fail to decode accountSnapshot")
 	}
 
+	var deposit *depositInfo
 	if n, err := d2.DecodeMulti(
 		&objGraph.nextHash,
 		&objGraph.graphHash,
+		&deposit,
 	); err == nil || err == io.EOF {
-		if n == 2 {
+		if n == 3 {
+			if len(objGraph.graphHash) > 0 {
+				s.objGraph = &objGraph
+			} else {
+				s.objGraph = nil
+			}
+			s.deposit = deposit
+		} else if n == 2 {
 			s.objGraph = &objGraph
 		} else if n == 0 {
 			s.objGraph = nil
@@ -445,6 +482,7 @@ type accountStateImpl struct {
 	store         trie.Mutable
 
 	objGraph *objectGraph
+	deposit  *depositInfo
 }
 
 type objectGraph struct {
@@ -545,6 +583,22 @@ func (s *accountStateImpl) ContractOwner() module.Address {
 	return s.contractOwner
 }
 
+func (s *accountStateImpl) GetDeposit() *big.Int {
+	return s.deposit.amount()
+}
+
+func (s *accountStateImpl) SetDeposit(v *big.Int) {
+	s.deposit = newDepositInfo(v, s.deposit.proportion())
+}
+
+func (s *accountStateImpl) GetFeeProportion() int {
+	return s.deposit.proportion()
+}
+
+func (s *accountStateImpl) SetFeeProportion(p int) {
+	s.deposit = newDepositInfo(s.deposit.amount(), p)
+}
+
 func (s *accountStateImpl) Version() int {
 	return s.version
 }
@@ -728,6 +782,7 @@ func (s *accountStateImpl) GetSnapshot() AccountSnapshot {
 		curContract:   curContract,
 		nextContract:  nextContract,
 		objGraph:      s.objGraph,
+		deposit:       s.deposit,
 	}
 }
 
@@ -764,6 +819,7 @@ func (s *accountStateImpl) Reset(isnapshot AccountSnapshot) error {
 		s.nextContract.reset(snapshot.nextContract)
 	}
 	s.objGraph = snapshot.objGraph
+	s.deposit = snapshot.deposit
 	if snapshot.store == nil {
 		s.store = nil
 		return nil
@@ -788,6 +844,7 @@ func (s *accountStateImpl) Clear() {
 	s.curContract = nil
 	s.nextContract = nil
 	s.store = nil
+	s.deposit = nil
 }
 
 func (s *accountStateImpl) GetValue(k []byte) ([]byte, error) {
@@ -886,6 +943,14 @@ func (a *accountROState) SetBalance(v *big.Int) {
 	log.Panic("accountROState().SetBalance() is invoked")
 }
 
+func (a *accountROState) SetDeposit(v *big.Int) {
+	log.Panic("accountROState().SetDeposit() is invoked")
+}
+
+func (a *accountROState) SetFeeProportion(p int) {
+	log.Panic("accountROState().SetFeeProportion() is invoked")
+}
+
 func (a *accountROState) SetValue(k, v []byte) ([]byte, error) {
 	return nil, errors.InvalidStateError.New("ReadOnlyState")
 }
@@ -76,3 +76,32 @@ func TestAccountSnapshot_Bytes(t *testing.T) {
 	tv2, _ := s2.GetValue(tv)
 	assert.Equal(t, tv, tv2)
 }
+
+func TestAccountSnapshot_Deposit(t *testing.T) {
+	database := db.NewMapDB()
+	as := newAccountState(database, nil, nil, false)
+	as.SetBalance(big.NewInt(3000))
+	s1 := as.GetSnapshot()
+
+	as.SetDeposit(big.NewInt(1000))
+	as.SetFeeProportion(50)
+	s2 := as.GetSnapshot()
+	assert.False(t, s1.Equal(s2))
+	assert.EqualValues(t, 1000, s2.GetDeposit().Int64())
+	assert.Equal(t, 50, s2.GetFeeProportion())
+
+	serialized := s2.Bytes()
+	s3 := new(accountSnapshotImpl)
+	assert.NoError(t, s3.Reset(database, serialized))
+	assert.EqualValues(t, 1000, s3.GetDeposit().Int64())
+	assert.Equal(t, 50, s3.GetFeeProportion())
+	assert.Equal(t, serialized, s3.Bytes())
+	assert.Nil(t, s3.objGraph)
+
+	// the account is same as before without deposit and proportion
+	as.SetDeposit(big.NewInt(0))
+	as.SetFeeProportion(0)
+	s4 := as.GetSnapshot()
+	assert.True(t, s1.Equal(s4))
+	assert.Equal(t, s1.Bytes(), s4.Bytes())
+}
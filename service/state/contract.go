@@ -50,6 +50,7 @@ const (
 	CTAppZip    = "application/zip"
 	CTAppJava   = "application/java"
 	CTAppSystem = "application/x.score.system"
+	CTAppGo     = "application/x.score.go"
 )
 
 type ContractSnapshot interface {
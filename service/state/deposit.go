@@ -0,0 +1,58 @@
+package state
+
+import (
+	"math/big"
+
+	"github.com/icon-project/goloop/common"
+)
+
+const (
+	// FeeProportionMax is the proportion for paying all the fees of the
+	// transactions with the deposit.
+	FeeProportionMax = 100
+)
+
+// depositInfo is the deposit of the contract for paying fees of the
+// transactions calling it, and the proportion of the fees paid with it.
+// Values of it shouldn't be changed after creation, so it can be shared
+// between snapshots and states.
+type depositInfo struct {
+	Amount     common.HexInt
+	Proportion int
+}
+
+// newDepositInfo returns the information of the deposit. It returns nil if
+// both of the amount and the proportion are zero, so the accounts without
+// them are kept same as before.
+func newDepositInfo(amount *big.Int, proportion int) *depositInfo {
+	if amount.Sign() == 0 && proportion == 0 {
+		return nil
+	}
+	d := &depositInfo{Proportion: proportion}
+	d.Amount.Set(amount)
+	return d
+}
+
+func (d *depositInfo) amount() *big.Int {
+	if d == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(&d.Amount.Int)
+}
+
+func (d *depositInfo) proportion() int {
+	if d == nil {
+		return 0
+	}
+	return d.Proportion
+}
+
+func (d *depositInfo) Equal(d2 *depositInfo) bool {
+	if d == d2 {
+		return true
+	}
+	if d == nil || d2 == nil {
+		return false
+	}
+	return d.Amount.Cmp(&d2.Amount.Int) == 0 && d.Proportion == d2.Proportion
+}
@@ -10,6 +10,7 @@ const (
 	PythonEE EEType = "python"
 	JavaEE   EEType = "java"
 	SystemEE EEType = "system"
+	GoEE     EEType = "go"
 )
 
 var (
@@ -17,11 +18,13 @@ var (
 		PythonEE: "on_install",
 		JavaEE:   "<init>",
 		SystemEE: "<Install>",
+		GoEE:     "on_install",
 	}
 	updateMethods = map[EEType]string{
 		PythonEE: "on_update",
 		JavaEE:   "",
 		SystemEE: "",
+		GoEE:     "on_update",
 	}
 )
 
@@ -59,7 +62,8 @@ func (e EEType) String() string {
 	return string(e)
 }
 
-// Only "application/zip" and "application/java" are allowed as contentType by server validator.
+// Only "application/zip", "application/java" and "application/x.score.go"
+// are allowed as contentType by server validator.
 func EETypeFromContentType(ct string) EEType {
 	switch ct {
 	case CTAppZip:
@@ -68,6 +72,8 @@ func EETypeFromContentType(ct string) EEType {
 		return JavaEE
 	case CTAppSystem:
 		return SystemEE
+	case CTAppGo:
+		return GoEE
 	default:
 		log.Errorf("Unexpected contentType(%s)\n", ct)
 		return ""
@@ -0,0 +1,59 @@
+package state
+
+import (
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// AccountProofOf returns the encoded account and the merkle proof of it in
+// the world state. The proof can be verified with the state hash.
+func AccountProofOf(wss WorldSnapshot, id []byte) ([]byte, [][]byte, error) {
+	ws, ok := wss.(*worldSnapshotImpl)
+	if !ok {
+		return nil, nil, errors.UnsupportedError.Errorf(
+			"UnsupportedWorldSnapshot(type=%T)", wss)
+	}
+	key := addressIDToKey(id)
+	obj, err := ws.accounts.Get(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if obj == nil {
+		return nil, nil, errors.NotFoundError.Errorf(
+			"AccountNotFound(id=%#x)", id)
+	}
+	proof := ws.accounts.GetProof(key)
+	if proof == nil {
+		return nil, nil, errors.NotFoundError.Errorf(
+			"NoProofForAccount(id=%#x)", id)
+	}
+	return obj.Bytes(), proof, nil
+}
+
+// StorageProofOf returns the value and the merkle proof of it in the storage
+// of the account. The proof can be verified with the storage hash of the
+// account.
+func StorageProofOf(ass AccountSnapshot, key []byte) ([]byte, [][]byte, error) {
+	as, ok := ass.(*accountSnapshotImpl)
+	if !ok {
+		return nil, nil, errors.UnsupportedError.Errorf(
+			"UnsupportedAccountSnapshot(type=%T)", ass)
+	}
+	if as.store == nil {
+		return nil, nil, errors.NotFoundError.Errorf(
+			"ValueNotFound(key=%#x)", key)
+	}
+	value, err := as.store.Get(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if value == nil {
+		return nil, nil, errors.NotFoundError.Errorf(
+			"ValueNotFound(key=%#x)", key)
+	}
+	proof := as.store.GetProof(key)
+	if proof == nil {
+		return nil, nil, errors.NotFoundError.Errorf(
+			"NoProofForValue(key=%#x)", key)
+	}
+	return value, proof, nil
+}
@@ -0,0 +1,58 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/trie/ompt"
+)
+
+func TestAccountProofOf(t *testing.T) {
+	database := db.NewMapDB()
+	ws := NewWorldState(database, nil, nil)
+
+	addr1 := common.NewAddressFromString("cx0000000000000000000000000000000000000001")
+	addr2 := common.NewAddressFromString("hx0000000000000000000000000000000000000002")
+	key := crypto.SHA3Sum256([]byte("key"))
+	value := []byte("value")
+
+	as1 := ws.GetAccountState(addr1.ID())
+	as1.SetBalance(big.NewInt(100))
+	_, err := as1.SetValue(key, value)
+	assert.NoError(t, err)
+	ws.GetAccountState(addr2.ID()).SetBalance(big.NewInt(200))
+
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+
+	bs, proof, err := AccountProofOf(wss, addr1.ID())
+	assert.NoError(t, err)
+
+	verified, err := ompt.NewMPTForBytes(db.NewMapDB(), wss.StateHash()).
+		Prove(crypto.SHA3Sum256(addr1.ID()), proof)
+	assert.NoError(t, err)
+	assert.Equal(t, bs, verified)
+
+	ass := wss.GetAccountSnapshot(addr1.ID())
+	v, proof, err := StorageProofOf(ass, key)
+	assert.NoError(t, err)
+	assert.Equal(t, value, v)
+
+	storeHash := ass.(*accountSnapshotImpl).store.Hash()
+	verified, err = ompt.NewMPTForBytes(db.NewMapDB(), storeHash).Prove(key, proof)
+	assert.NoError(t, err)
+	assert.Equal(t, value, verified)
+
+	_, _, err = StorageProofOf(ass, crypto.SHA3Sum256([]byte("unknown")))
+	assert.True(t, errors.NotFoundError.Equals(err))
+
+	addr3 := common.NewAddressFromString("hx0000000000000000000000000000000000000003")
+	_, _, err = AccountProofOf(wss, addr3.ID())
+	assert.True(t, errors.NotFoundError.Equals(err))
+}
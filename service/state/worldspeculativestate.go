@@ -0,0 +1,207 @@
+package state
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/icon-project/goloop/common/db"
+)
+
+const (
+	// ValidatorsIDStr is used in read and write sets of WorldSpeculativeState
+	// for the validators. It can't be an ID of an account.
+	ValidatorsIDStr = "validators"
+)
+
+// WorldSpeculativeState is a WorldVirtualState for executing a transaction
+// speculatively on the snapshot of the world. It records IDs of accounts
+// read or written by the transaction, so changes of the transaction can be
+// applied to the other world state without execution if none of them were
+// changed after the snapshot.
+type WorldSpeculativeState interface {
+	WorldVirtualState
+
+	// ReadSet returns sorted IDs of all accounts accessed by the transaction
+	// including ones written by it.
+	ReadSet() []string
+
+	// WriteSet returns sorted IDs of accounts changed by the transaction.
+	WriteSet() []string
+
+	// Apply applies changes of the transaction to the world state.
+	Apply(ws WorldState) error
+}
+
+type worldSpeculativeState struct {
+	mutex sync.Mutex
+
+	base WorldSnapshot
+	real WorldState
+
+	accounts   map[string]AccountState
+	reads      map[string]bool
+	validators bool
+}
+
+func (wss *worldSpeculativeState) GetAccountState(id []byte) AccountState {
+	wss.mutex.Lock()
+	defer wss.mutex.Unlock()
+
+	as := wss.real.GetAccountState(id)
+	wss.accounts[string(id)] = as
+	return as
+}
+
+func (wss *worldSpeculativeState) GetAccountSnapshot(id []byte) AccountSnapshot {
+	wss.mutex.Lock()
+	defer wss.mutex.Unlock()
+
+	wss.reads[string(id)] = true
+	return wss.real.GetAccountSnapshot(id)
+}
+
+func (wss *worldSpeculativeState) GetSnapshot() WorldSnapshot {
+	return wss.real.GetSnapshot()
+}
+
+func (wss *worldSpeculativeState) GetValidatorState() ValidatorState {
+	wss.mutex.Lock()
+	defer wss.mutex.Unlock()
+
+	wss.validators = true
+	return wss.real.GetValidatorState()
+}
+
+func (wss *worldSpeculativeState) Reset(snapshot WorldSnapshot) error {
+	return wss.real.Reset(snapshot)
+}
+
+func (wss *worldSpeculativeState) ClearCache() {
+	// It's used only for one transaction, so we don't need to support
+	// this feature.
+}
+
+func (wss *worldSpeculativeState) EnableNodeCache() {
+	wss.real.EnableNodeCache()
+}
+
+func (wss *worldSpeculativeState) NodeCacheEnabled() bool {
+	return wss.real.NodeCacheEnabled()
+}
+
+func (wss *worldSpeculativeState) Database() db.Database {
+	return wss.real.Database()
+}
+
+// GetFuture returns itself. Nobody else shares the state, so it doesn't need
+// to lock anything.
+func (wss *worldSpeculativeState) GetFuture(reqs []LockRequest) WorldVirtualState {
+	return wss
+}
+
+func (wss *worldSpeculativeState) Ensure() {
+	// do nothing
+}
+
+func (wss *worldSpeculativeState) Commit() {
+	// do nothing
+}
+
+func (wss *worldSpeculativeState) Realize() {
+	// do nothing
+}
+
+func (wss *worldSpeculativeState) ReadSet() []string {
+	wss.mutex.Lock()
+	defer wss.mutex.Unlock()
+
+	ids := make([]string, 0, len(wss.accounts)+len(wss.reads)+1)
+	for id := range wss.accounts {
+		ids = append(ids, id)
+	}
+	for id := range wss.reads {
+		if _, ok := wss.accounts[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	if wss.validators {
+		ids = append(ids, ValidatorsIDStr)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// changes returns snapshots of the accounts changed from the base, and the
+// snapshot of the validators if they are changed.
+func (wss *worldSpeculativeState) changes() (map[string]AccountSnapshot, ValidatorSnapshot) {
+	wss.mutex.Lock()
+	defer wss.mutex.Unlock()
+
+	accounts := make(map[string]AccountSnapshot)
+	for id, as := range wss.accounts {
+		ass := as.GetSnapshot()
+		base := wss.base.GetAccountSnapshot([]byte(id))
+		if base == nil {
+			if ass.IsEmpty() {
+				continue
+			}
+		} else if ass.Equal(base) {
+			continue
+		}
+		accounts[id] = ass
+	}
+	var validators ValidatorSnapshot
+	if wss.validators {
+		vss := wss.real.GetValidatorState().GetSnapshot()
+		if !bytes.Equal(vss.Hash(), wss.base.GetValidatorSnapshot().Hash()) {
+			validators = vss
+		}
+	}
+	return accounts, validators
+}
+
+func (wss *worldSpeculativeState) WriteSet() []string {
+	accounts, validators := wss.changes()
+	ids := make([]string, 0, len(accounts)+1)
+	for id := range accounts {
+		ids = append(ids, id)
+	}
+	if validators != nil {
+		ids = append(ids, ValidatorsIDStr)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (wss *worldSpeculativeState) Apply(ws WorldState) error {
+	accounts, validators := wss.changes()
+	for id, ass := range accounts {
+		as := ws.GetAccountState([]byte(id))
+		// Reset keeps the contracts and the owner if the snapshot doesn't
+		// have them, so clear it first to make it same as the snapshot.
+		as.Clear()
+		if err := as.Reset(ass); err != nil {
+			return err
+		}
+	}
+	if validators != nil {
+		ws.GetValidatorState().Reset(validators)
+	}
+	return nil
+}
+
+// NewWorldSpeculativeState returns a new WorldSpeculativeState for executing
+// a transaction on the snapshot.
+func NewWorldSpeculativeState(base WorldSnapshot) (WorldSpeculativeState, error) {
+	ws, err := WorldStateFromSnapshot(base)
+	if err != nil {
+		return nil, err
+	}
+	return &worldSpeculativeState{
+		base:     base,
+		real:     ws,
+		accounts: make(map[string]AccountState),
+		reads:    make(map[string]bool),
+	}, nil
+}
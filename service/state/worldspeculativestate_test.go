@@ -0,0 +1,126 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+)
+
+func TestWorldSpeculativeState_ReadWriteSet(t *testing.T) {
+	database := db.NewMapDB()
+	ws := NewWorldState(database, nil, nil)
+	ws.GetAccountState([]byte("a")).SetBalance(big.NewInt(100))
+	ws.GetAccountState([]byte("b")).SetBalance(big.NewInt(50))
+
+	wss, err := NewWorldSpeculativeState(ws.GetSnapshot())
+	assert.NoError(t, err)
+
+	_ = wss.GetAccountSnapshot([]byte("c"))
+	as := wss.GetAccountState([]byte("a"))
+	as.SetBalance(big.NewInt(90))
+	_, err = as.SetValue([]byte("key"), []byte("value"))
+	assert.NoError(t, err)
+	_ = wss.GetAccountState([]byte("b")).GetBalance()
+	_ = wss.GetAccountState([]byte("d"))
+
+	assert.Equal(t, []string{"a", "b", "c", "d"}, wss.ReadSet())
+	assert.Equal(t, []string{"a"}, wss.WriteSet())
+
+	assert.NoError(t, wss.Apply(ws))
+	as2 := ws.GetAccountSnapshot([]byte("a"))
+	assert.EqualValues(t, 90, as2.GetBalance().Int64())
+	assert.True(t, as2.Equal(as.GetSnapshot()))
+	assert.Equal(t, wss.GetSnapshot().StateHash(), ws.GetSnapshot().StateHash())
+}
+
+func TestWorldSpeculativeState_Validators(t *testing.T) {
+	ws := NewWorldState(db.NewMapDB(), nil, nil)
+	wss, err := NewWorldSpeculativeState(ws.GetSnapshot())
+	assert.NoError(t, err)
+
+	v, err := ValidatorFromAddress(common.NewAddressFromString("hx1111111111111111111111111111111111111111"))
+	assert.NoError(t, err)
+	assert.NoError(t, wss.GetValidatorState().Add(v))
+
+	assert.Equal(t, []string{ValidatorsIDStr}, wss.ReadSet())
+	assert.Equal(t, []string{ValidatorsIDStr}, wss.WriteSet())
+
+	assert.NoError(t, wss.Apply(ws))
+	assert.Equal(t, 1, ws.GetValidatorState().Len())
+}
+
+type testTransfer struct {
+	from, to string
+	amount   int64
+}
+
+func (tx *testTransfer) execute(ws WorldState) {
+	from := ws.GetAccountState([]byte(tx.from))
+	bal := from.GetBalance()
+	if bal.Int64() < tx.amount {
+		return
+	}
+	from.SetBalance(new(big.Int).Sub(bal, big.NewInt(tx.amount)))
+	to := ws.GetAccountState([]byte(tx.to))
+	to.SetBalance(new(big.Int).Add(to.GetBalance(), big.NewInt(tx.amount)))
+}
+
+// TestWorldSpeculativeState_Differential executes transactions on the
+// snapshot, then it applies them in order or executes them again for
+// conflicts. The result should be same as the sequential execution.
+func TestWorldSpeculativeState_Differential(t *testing.T) {
+	txs := []*testTransfer{
+		{"a", "b", 10},
+		{"c", "d", 10},
+		{"b", "e", 45}, // conflicts with the first one
+		{"f", "g", 5},
+		{"d", "a", 20}, // conflicts with the second one
+		{"h", "i", 1000},
+	}
+	database := db.NewMapDB()
+	ws := NewWorldState(database, nil, nil)
+	for i, id := range []string{"a", "b", "c", "d", "f", "h"} {
+		ws.GetAccountState([]byte(id)).SetBalance(big.NewInt(int64(i+1) * 20))
+	}
+	base := ws.GetSnapshot()
+
+	seq, err := WorldStateFromSnapshot(base)
+	assert.NoError(t, err)
+	for _, tx := range txs {
+		tx.execute(seq)
+	}
+
+	specs := make([]WorldSpeculativeState, len(txs))
+	for i, tx := range txs {
+		specs[i], err = NewWorldSpeculativeState(base)
+		assert.NoError(t, err)
+		tx.execute(specs[i])
+	}
+
+	par, err := WorldStateFromSnapshot(base)
+	assert.NoError(t, err)
+	var conflicts int
+	written := make(map[string]bool)
+	for i, tx := range txs {
+		wss := specs[i]
+		for _, id := range wss.ReadSet() {
+			if written[id] {
+				conflicts++
+				wss, err = NewWorldSpeculativeState(par.GetSnapshot())
+				assert.NoError(t, err)
+				tx.execute(wss)
+				break
+			}
+		}
+		assert.NoError(t, wss.Apply(par))
+		for _, id := range wss.WriteSet() {
+			written[id] = true
+		}
+	}
+	assert.Equal(t, 2, conflicts)
+	assert.Equal(t, seq.GetSnapshot().StateHash(), par.GetSnapshot().StateHash())
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const keyStateOverride = "stateOverride"
+
+// accountOverride replaces the state of an account. Storage maps raw keys
+// of the storage to the values, and null value deletes the entry. Code
+// replaces the code of the contract with ContentType.
+type accountOverride struct {
+	Balance     *common.HexInt              `json:"balance"`
+	Storage     map[string]*common.HexBytes `json:"storage"`
+	Code        common.HexBytes             `json:"code"`
+	ContentType string                      `json:"contentType"`
+}
+
+// stateOverride maps addresses to the overrides of the accounts.
+type stateOverride map[string]*accountOverride
+
+func parseStateOverride(js json.RawMessage) (stateOverride, error) {
+	if len(js) == 0 {
+		return nil, nil
+	}
+	var so stateOverride
+	if err := json.Unmarshal(js, &so); err != nil {
+		return nil, InvalidQueryError.Wrapf(err,
+			"InvalidStateOverride(%s)", string(js))
+	}
+	for s := range so {
+		if _, err := parseOverrideAddress(s); err != nil {
+			return nil, err
+		}
+	}
+	return so, nil
+}
+
+// parseOverrideAddress parses the address of the override. It accepts only
+// the canonical form of the address as the other parameters of the API.
+func parseOverrideAddress(s string) (*common.Address, error) {
+	addr := new(common.Address)
+	if err := addr.SetString(s); err != nil {
+		return nil, InvalidQueryError.Wrapf(err, "InvalidAddress(addr=%s)", s)
+	}
+	if addr.String() != s {
+		return nil, InvalidQueryError.Errorf("InvalidAddress(addr=%s)", s)
+	}
+	return addr, nil
+}
+
+// splitStateOverride removes the state override from the JSON object, and
+// returns the rest of it with the state override.
+func splitStateOverride(js []byte) ([]byte, stateOverride, error) {
+	var jso map[string]json.RawMessage
+	if err := json.Unmarshal(js, &jso); err != nil {
+		return nil, nil, InvalidQueryError.Errorf("FailToParse(%s)", string(js))
+	}
+	sjs, ok := jso[keyStateOverride]
+	if !ok {
+		return js, nil, nil
+	}
+	so, err := parseStateOverride(sjs)
+	if err != nil {
+		return nil, nil, err
+	}
+	delete(jso, keyStateOverride)
+	if js, err = json.Marshal(jso); err != nil {
+		return nil, nil, err
+	}
+	return js, so, nil
+}
+
+func parseStorageKey(s string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(key) == 0 {
+		return nil, InvalidQueryError.Errorf("InvalidStorageKey(key=%s)", s)
+	}
+	return key, nil
+}
+
+// checkNoCode returns an error if any override replaces the code. Code
+// overrides prepare the code in the contract store, so they are allowed
+// only for the debug API.
+func (so stateOverride) checkNoCode() error {
+	for addr, o := range so {
+		if o != nil && len(o.Code) > 0 {
+			return InvalidQueryError.Errorf("CodeOverrideNotAllowed(addr=%s)", addr)
+		}
+	}
+	return nil
+}
+
+// apply applies the overrides in the order of the addresses. The context
+// should be on a virtual state, which is discarded after the use.
+func (so stateOverride) apply(ctx contract.Context) error {
+	addrs := make([]string, 0, len(so))
+	for addr := range so {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	for _, s := range addrs {
+		o := so[s]
+		if o == nil {
+			continue
+		}
+		addr, err := parseOverrideAddress(s)
+		if err != nil {
+			return err
+		}
+		if len(o.Code) > 0 {
+			if err := contract.OverrideCode(ctx, addr, o.Code, o.ContentType); err != nil {
+				return err
+			}
+		}
+		as := ctx.GetAccountState(addr.ID())
+		if o.Balance != nil {
+			if o.Balance.Sign() < 0 {
+				return InvalidQueryError.Errorf(
+					"NegativeBalance(addr=%s,balance=%s)", s, o.Balance)
+			}
+			as.SetBalance(&o.Balance.Int)
+		}
+		for k, v := range o.Storage {
+			key, err := parseStorageKey(k)
+			if err != nil {
+				return err
+			}
+			if v == nil {
+				_, err = as.DeleteValue(key)
+			} else {
+				_, err = as.SetValue(key, v.Bytes())
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// newContextWithOverride returns a context on a virtual state of the world
+// snapshot with the state override applied.
+func (m *manager) newContextWithOverride(wss state.WorldSnapshot, bi module.BlockInfo, so stateOverride) (contract.Context, error) {
+	ws, err := state.WorldStateFromSnapshot(wss)
+	if err != nil {
+		return nil, err
+	}
+	wvs := state.NewWorldVirtualState(ws, []state.LockRequest{
+		{ID: state.WorldIDStr, Lock: state.AccountWriteLock},
+	})
+	ctx := contract.NewContext(state.NewWorldContext(wvs, bi),
+		m.cm, m.eem, m.chain, m.log, nil)
+	if err := so.apply(ctx); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
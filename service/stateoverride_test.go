@@ -0,0 +1,114 @@
+package service
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func TestStateOverride_Split(t *testing.T) {
+	js, so, err := splitStateOverride([]byte(`{"to":"hx1111111111111111111111111111111111111111"}`))
+	assert.NoError(t, err)
+	assert.Nil(t, so)
+	assert.JSONEq(t, `{"to":"hx1111111111111111111111111111111111111111"}`, string(js))
+
+	js, so, err = splitStateOverride([]byte(`{
+		"to":"hx1111111111111111111111111111111111111111",
+		"stateOverride":{
+			"hx1111111111111111111111111111111111111111":{"balance":"0x10"}
+		}
+	}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"to":"hx1111111111111111111111111111111111111111"}`, string(js))
+	if assert.Len(t, so, 1) {
+		o := so["hx1111111111111111111111111111111111111111"]
+		assert.EqualValues(t, 0x10, o.Balance.Int64())
+	}
+
+	_, _, err = splitStateOverride([]byte(`{"stateOverride":{"hx11":{"balance":1}}}`))
+	assert.True(t, InvalidQueryError.Equals(err))
+}
+
+func TestStateOverride_Apply(t *testing.T) {
+	addr := common.NewAddressFromString("hx1111111111111111111111111111111111111111")
+	score := common.NewAddressFromString("cx2222222222222222222222222222222222222222")
+
+	ws := state.NewWorldState(db.NewMapDB(), nil, nil)
+	ws.GetAccountState(addr.ID()).SetBalance(big.NewInt(100))
+	sas := ws.GetAccountState(score.ID())
+	_, err := sas.SetValue([]byte{1}, []byte{0x11})
+	assert.NoError(t, err)
+	_, err = sas.SetValue([]byte{2}, []byte{0x22})
+	assert.NoError(t, err)
+	wss := ws.GetSnapshot()
+
+	var so stateOverride
+	err = json.Unmarshal([]byte(`{
+		"hx1111111111111111111111111111111111111111":{"balance":"0x7"},
+		"cx2222222222222222222222222222222222222222":{
+			"storage":{"0x01":"0x33","0x02":null,"0x03":"0x44"}
+		}
+	}`), &so)
+	assert.NoError(t, err)
+
+	m := &manager{log: log.New()}
+	ctx, err := m.newContextWithOverride(wss, common.NewBlockInfo(1, 0), so)
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, 7, ctx.GetAccountState(addr.ID()).GetBalance().Int64())
+	as := ctx.GetAccountState(score.ID())
+	for k, v := range map[byte][]byte{1: {0x33}, 2: nil, 3: {0x44}} {
+		value, err := as.GetValue([]byte{k})
+		assert.NoError(t, err)
+		assert.Equal(t, v, value)
+	}
+
+	// the snapshot is kept as it was
+	assert.EqualValues(t, 100, wss.GetAccountSnapshot(addr.ID()).GetBalance().Int64())
+	value, err := wss.GetAccountSnapshot(score.ID()).GetValue([]byte{2})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x22}, value)
+
+	var so2 stateOverride
+	err = json.Unmarshal([]byte(`{
+		"cx2222222222222222222222222222222222222222":{"storage":{"xx":"0x01"}}
+	}`), &so2)
+	assert.NoError(t, err)
+	_, err = m.newContextWithOverride(wss, common.NewBlockInfo(1, 0), so2)
+	assert.True(t, InvalidQueryError.Equals(err))
+}
+
+func TestStateOverride_CodeForCall(t *testing.T) {
+	var so stateOverride
+	err := json.Unmarshal([]byte(`{
+		"hx1111111111111111111111111111111111111111":{"balance":"0x7"}
+	}`), &so)
+	assert.NoError(t, err)
+	assert.NoError(t, so.checkNoCode())
+
+	js := []byte(`{
+		"to":"cx2222222222222222222222222222222222222222",
+		"dataType":"call",
+		"data":{"method":"get"},
+		"stateOverride":{
+			"cx2222222222222222222222222222222222222222":{
+				"code":"0x504b0304","contentType":"application/zip"
+			}
+		}
+	}`)
+	_, so, err = splitStateOverride(js)
+	assert.NoError(t, err)
+	assert.True(t, InvalidQueryError.Equals(so.checkNoCode()))
+
+	// icx_call rejects it before preparing the code
+	m := &manager{log: log.New()}
+	_, err = m.Call(nil, nil, js, common.NewBlockInfo(1, 0))
+	assert.True(t, InvalidQueryError.Equals(err))
+}
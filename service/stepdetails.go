@@ -0,0 +1,113 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const keyStepDetailsStart = "service.stepDetailsStart"
+
+// stepDetailsStore keeps details of the used steps of the normal
+// transactions apart from the receipts, so they don't affect the result
+// of the blocks.
+//
+//	key   : hash of the transaction
+//	value : details of the used steps
+//
+// The height of the first recorded block is stored in the chain property.
+type stepDetailsStore struct {
+	database db.Database
+	bucket   db.Bucket
+	props    db.Bucket
+}
+
+func newStepDetailsStore(database db.Database) (*stepDetailsStore, error) {
+	bk, err := database.GetBucket(db.StepUsedDetailsByHash)
+	if err != nil {
+		return nil, err
+	}
+	props, err := database.GetBucket(db.ChainProperty)
+	if err != nil {
+		return nil, err
+	}
+	return &stepDetailsStore{
+		database: database,
+		bucket:   bk,
+		props:    props,
+	}, nil
+}
+
+// clearStepDetailsStart removes the height of the first recorded block, so
+// the recording starts again from the next block when it's enabled later.
+func clearStepDetailsStart(database db.Database) error {
+	props, err := database.GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	return props.Delete([]byte(keyStepDetailsStart))
+}
+
+// StartHeight returns the height of the first recorded block. It returns
+// false if no block has been recorded.
+func (s *stepDetailsStore) StartHeight() (int64, bool, error) {
+	bs, err := s.props.Get([]byte(keyStepDetailsStart))
+	if err != nil || bs == nil {
+		return 0, false, err
+	}
+	var height int64
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &height); err != nil {
+		return 0, false, err
+	}
+	return height, true, nil
+}
+
+// Add writes details of the used steps of the normal transactions in the
+// block of the height. details is indexed by the index of the transaction.
+func (s *stepDetailsStore) Add(height int64, txs module.TransactionList, details []txresult.StepUsedDetails) error {
+	batch := s.database.NewBatch()
+	if _, ok, err := s.StartHeight(); err != nil {
+		return err
+	} else if !ok {
+		batch.Set(db.ChainProperty, []byte(keyStepDetailsStart),
+			codec.BC.MustMarshalToBytes(height))
+	}
+	for idx, d := range details {
+		if d == nil {
+			continue
+		}
+		tx, err := txs.Get(idx)
+		if err != nil {
+			return err
+		}
+		batch.Set(db.StepUsedDetailsByHash, tx.ID(), d.Bytes())
+	}
+	return batch.Write()
+}
+
+// Get returns details of the used steps of the transaction. It returns nil
+// if there is no record for the transaction.
+func (s *stepDetailsStore) Get(id []byte) (txresult.StepUsedDetails, error) {
+	bs, err := s.bucket.Get(id)
+	if err != nil || bs == nil {
+		return nil, err
+	}
+	return txresult.StepUsedDetailsFromBytes(bs)
+}
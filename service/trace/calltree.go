@@ -0,0 +1,171 @@
+package trace
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+type Event struct {
+	Address module.Address
+	Indexed [][]byte
+	Data    [][]byte
+}
+
+// Frame is a call frame in the call tree. Events are the ones emitted in
+// the frame, and they are discarded from the receipt if the frame or one
+// of its parents fails.
+type Frame struct {
+	module.TraceFrame
+	StepUsed *big.Int
+	Status   error
+	Address  module.Address
+	Events   []*Event
+	Calls    []*Frame
+
+	parent *Frame
+}
+
+func (f *Frame) ToJSON(version module.JSONVersion) (interface{}, error) {
+	jso := map[string]interface{}{
+		"type": f.Type,
+	}
+	if f.From != nil {
+		jso["from"] = f.From
+	}
+	if f.To != nil {
+		jso["to"] = f.To
+	}
+	if f.Value != nil {
+		jso["value"] = intconv.FormatBigInt(f.Value)
+	}
+	if f.Method != "" {
+		jso["method"] = f.Method
+	}
+	if f.StepLimit != nil {
+		jso["stepLimit"] = intconv.FormatBigInt(f.StepLimit)
+	}
+	if f.StepUsed != nil {
+		jso["stepUsed"] = intconv.FormatBigInt(f.StepUsed)
+	}
+	if f.Status == nil {
+		jso["status"] = "0x1"
+	} else {
+		jso["status"] = "0x0"
+		status, _ := scoreresult.StatusOf(f.Status)
+		jso["failure"] = map[string]interface{}{
+			"code":    status,
+			"message": f.Status.Error(),
+		}
+	}
+	if f.Address != nil {
+		jso["scoreAddress"] = f.Address
+	}
+	if len(f.Events) > 0 {
+		events := make([]interface{}, 0, len(f.Events))
+		for _, ev := range f.Events {
+			ejso, err := txresult.EventLogToJSON(
+				txresult.NewEventLog(ev.Address, ev.Indexed, ev.Data), version)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, ejso)
+		}
+		jso["eventLogs"] = events
+	}
+	if len(f.Calls) > 0 {
+		calls := make([]interface{}, 0, len(f.Calls))
+		for _, call := range f.Calls {
+			cjso, err := call.ToJSON(version)
+			if err != nil {
+				return nil, err
+			}
+			calls = append(calls, cjso)
+		}
+		jso["calls"] = calls
+	}
+	return jso, nil
+}
+
+// Transaction is the trace of the transaction. Frame is nil if the
+// transaction fails before the call.
+type Transaction struct {
+	Group module.TransactionGroup
+	Index int
+	Frame *Frame
+}
+
+// CallTree builds trees of call frames of the transactions with
+// module.TraceFrameCallback.
+type CallTree struct {
+	lock    sync.Mutex
+	txs     []*Transaction
+	current *Frame
+}
+
+func (t *CallTree) OnTransactionStart(group module.TransactionGroup, index int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.txs = append(t.txs, &Transaction{
+		Group: group,
+		Index: index,
+	})
+	t.current = nil
+}
+
+func (t *CallTree) OnFrameEnter(tf *module.TraceFrame) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	frame := &Frame{
+		TraceFrame: *tf,
+		parent:     t.current,
+	}
+	if t.current != nil {
+		t.current.Calls = append(t.current.Calls, frame)
+	} else if len(t.txs) > 0 {
+		t.txs[len(t.txs)-1].Frame = frame
+	}
+	t.current = frame
+}
+
+func (t *CallTree) OnFrameEvent(addr module.Address, indexed, data [][]byte) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.current == nil {
+		return
+	}
+	t.current.Events = append(t.current.Events, &Event{
+		Address: addr,
+		Indexed: indexed,
+		Data:    data,
+	})
+}
+
+func (t *CallTree) OnFrameExit(stepUsed *big.Int, status error, addr module.Address) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.current == nil {
+		return
+	}
+	t.current.StepUsed = new(big.Int).Set(stepUsed)
+	t.current.Status = status
+	t.current.Address = addr
+	t.current = t.current.parent
+}
+
+// Transactions returns traces of the transactions in the order of the
+// execution.
+func (t *CallTree) Transactions() []*Transaction {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.txs
+}
@@ -285,6 +285,10 @@ func (g *genesisV3) Nonce() *big.Int {
 	return nil
 }
 
+func (g *genesisV3) StepLimit() *big.Int {
+	return nil
+}
+
 func (g *genesisV3) To() module.Address {
 	return common.NewContractAddress(state.SystemID)
 }
@@ -159,6 +159,10 @@ func (tx *transactionV2) Nonce() *big.Int {
 	return nil
 }
 
+func (tx *transactionV2) StepLimit() *big.Int {
+	return version2StepUsed
+}
+
 func (tx *transactionV2) To() module.Address {
 	return &tx.transactionV3Data.To
 }
@@ -149,7 +149,7 @@ func (tx *transactionV3) Version() int {
 }
 
 func (tx *transactionV3) isDeployType(cType string) bool {
-	if cType == state.CTAppZip || cType == state.CTAppJava {
+	if cType == state.CTAppZip || cType == state.CTAppJava || cType == state.CTAppGo {
 		return true
 	}
 	return false
@@ -198,6 +198,13 @@ func (tx *transactionV3) Verify() error {
 			if _, err := contract.ParsePatchData(tx.Data); err != nil {
 				return InvalidTxValue.Wrap(err, "TxData is invalid")
 			}
+		case DataTypeDeposit:
+			if tx.Data == nil {
+				return InvalidTxValue.New("TxData for deposit is NIL")
+			}
+			if _, err := contract.ParseDepositData(tx.Data); err != nil {
+				return InvalidTxValue.Wrap(err, "TxData is invalid")
+			}
 		}
 	}
 
@@ -217,20 +224,40 @@ func (tx *transactionV3) ValidateNetwork(nid int) bool {
 }
 
 func (tx *transactionV3) PreValidate(wc state.WorldContext, update bool) error {
+	// content type of the SCORE is allowed in the revision
+	if tx.DataType != nil && *tx.DataType == DataTypeDeploy {
+		deploy, err := contract.ParseDeployData(tx.Data)
+		if err != nil {
+			return InvalidTxValue.Wrap(err, "TxData is invalid")
+		}
+		if err := contract.CheckContentType(wc.Revision(), deploy.ContentType); err != nil {
+			return InvalidTxValue.Wrap(err, "TxData is invalid")
+		}
+	}
+
 	// stepLimit >= default step + input steps
 	cnt, err := MeasureBytesOfData(wc.Revision(), tx.Data)
 	if err != nil {
 		return err
 	}
 	minStep := big.NewInt(wc.StepsFor(state.StepTypeDefault, 1) + wc.StepsFor(state.StepTypeInput, cnt))
-	if tx.StepLimit.Cmp(minStep) < 0 {
-		return NotEnoughStepError.Errorf("NotEnoughStep(txStepLimit:%s, minStep:%s)", tx.StepLimit, minStep)
+	if tx.transactionV3Data.StepLimit.Cmp(minStep) < 0 {
+		return NotEnoughStepError.Errorf("NotEnoughStep(txStepLimit:%s, minStep:%s)", tx.transactionV3Data.StepLimit, minStep)
 	}
 
 	// balance >= (fee + value)
 	stepPrice := wc.StepPrice()
 
-	trans := new(big.Int).Mul(&tx.StepLimit.Int, stepPrice)
+	// the SCORE pays the part of the fee if its deposit is enough for the
+	// part of the step limit.
+	var payer state.AccountState
+	payerSteps := new(big.Int)
+	if isFeeSharing(tx.To(), tx.DataType) {
+		payer, payerSteps = feePayerOf(wc, tx.To(), &tx.transactionV3Data.StepLimit.Int, stepPrice)
+	}
+	senderSteps := new(big.Int).Sub(&tx.transactionV3Data.StepLimit.Int, payerSteps)
+
+	trans := new(big.Int).Mul(senderSteps, stepPrice)
 	if tx.Value != nil {
 		trans.Add(trans, &tx.Value.Int)
 	}
@@ -249,6 +276,10 @@ func (tx *transactionV3) PreValidate(wc state.WorldContext, update bool) error {
 			balance2 := as2.GetBalance()
 			as2.SetBalance(new(big.Int).Add(balance2, &tx.Value.Int))
 		}
+		if payer != nil {
+			payerFee := new(big.Int).Mul(payerSteps, stepPrice)
+			payer.SetDeposit(new(big.Int).Sub(payer.GetDeposit(), payerFee))
+		}
 	}
 	return nil
 }
@@ -264,7 +295,7 @@ func (tx *transactionV3) GetHandler(cm contract.ContractManager) (Handler, error
 		tx.From(),
 		tx.To(),
 		value,
-		&tx.StepLimit.Int,
+		&tx.transactionV3Data.StepLimit.Int,
 		tx.DataType,
 		tx.Data)
 }
@@ -313,6 +344,10 @@ func (tx *transactionV3) Nonce() *big.Int {
 	return nil
 }
 
+func (tx *transactionV3) StepLimit() *big.Int {
+	return &tx.transactionV3Data.StepLimit.Int
+}
+
 func (tx *transactionV3) To() module.Address {
 	return &tx.transactionV3Data.To
 }
@@ -20,6 +20,7 @@ const (
 	DataTypeCall    = "call"
 	DataTypeDeploy  = "deploy"
 	DataTypePatch   = "patch"
+	DataTypeDeposit = "deposit"
 )
 
 type Handler interface {
@@ -35,6 +36,9 @@ type transactionHandler struct {
 	stepLimit *big.Int
 	data      []byte
 
+	// feeSharing is true if the SCORE may pay the fee with its deposit.
+	feeSharing bool
+
 	chandler contract.ContractHandler
 
 	// Assigned at Execute()
@@ -64,11 +68,15 @@ func NewHandler(cm contract.ContractManager, from, to module.Address,
 			ctype = contract.CTypeCall
 		case DataTypePatch:
 			ctype = contract.CTypePatch
+		case DataTypeDeposit:
+			ctype = contract.CTypeDeposit
 		default:
 			return nil, InvalidFormat.Errorf("IllegalDataType(type=%s)", *dataType)
 		}
 	}
 
+	th.feeSharing = isFeeSharing(to, dataType)
+
 	if handler, err := cm.GetHandler(from, to, value, ctype, data); err != nil {
 		return nil, errors.InvalidStateError.Wrap(err, "NoSuitableHandler")
 	} else {
@@ -143,34 +151,98 @@ func (th *transactionHandler) Execute(ctx contract.Context, estimate bool) (txre
 	}
 	fee := new(big.Int).Mul(stepUsed, stepPrice)
 
+	// The SCORE pays the part of the fee only if the call succeeds.
+	var payer state.AccountState
+	payerSteps := new(big.Int)
+	if status == nil && th.feeSharing {
+		payer, payerSteps = feePayerOf(ctx, th.to, stepUsed, stepPrice)
+	}
+	senderFee := new(big.Int).Mul(payerSteps, stepPrice)
+	senderFee.Sub(fee, senderFee)
+
 	as := ctx.GetAccountState(th.from.ID())
 	bal := as.GetBalance()
-	for bal.Cmp(fee) < 0 {
+	for bal.Cmp(senderFee) < 0 {
 		if status == nil {
 			// rollback all changes
 			status = scoreresult.ErrOutOfBalance
 			ctx.Reset(wcs)
 			bal = as.GetBalance()
+			payer = nil
+			payerSteps.SetInt64(0)
+			senderFee.Set(fee)
 		} else {
 			stepPrice.SetInt64(0)
 			fee.SetInt64(0)
+			senderFee.SetInt64(0)
 		}
 	}
-	as.SetBalance(new(big.Int).Sub(bal, fee))
+	as.SetBalance(new(big.Int).Sub(bal, senderFee))
+	if payer != nil {
+		payerFee := new(big.Int).Sub(fee, senderFee)
+		payer.SetDeposit(new(big.Int).Sub(payer.GetDeposit(), payerFee))
+		logger.TSystemf("FEE shared payer=%s steps=%s fee=%s",
+			th.to, payerSteps, payerFee)
+	}
 
 	// Make a receipt
 	receipt := txresult.NewReceipt(ctx.Database(), ctx.Revision(), th.to)
 	s, _ := scoreresult.StatusOf(status)
 	if status == nil {
 		cc.GetEventLogs(receipt)
+		cc.GetInternalTransfers(receipt)
 	}
 	receipt.SetResult(s, stepUsed, stepPrice, addr)
+	if payer != nil {
+		receipt.SetFeePayment(th.to, payerSteps)
+	}
+	receipt.SetStepUsedDetails(stepUsedDetailsOf(cc, stepUsed))
 
 	logger.TSystemf("TRANSACTION done status=%s steps=%s price=%s", s, stepUsed, stepPrice)
 
 	return receipt, nil
 }
 
+// isFeeSharing returns true if the SCORE may pay the part of the fee of the
+// transaction with its deposit.
+func isFeeSharing(to module.Address, dataType *string) bool {
+	if !to.IsContract() {
+		return false
+	}
+	return dataType == nil || *dataType == DataTypeMessage || *dataType == DataTypeCall
+}
+
+// feePayerOf returns the account of the SCORE paying the part of the fee with
+// its deposit, and the steps for the part. It returns nil if the SCORE doesn't
+// share the fee or the deposit isn't enough for the part.
+func feePayerOf(wc state.WorldContext, to module.Address, steps, stepPrice *big.Int) (state.AccountState, *big.Int) {
+	if wc.Revision() < module.Revision9 || stepPrice.Sign() == 0 {
+		return nil, new(big.Int)
+	}
+	as := wc.GetAccountState(to.ID())
+	proportion := as.GetFeeProportion()
+	if !as.IsContract() || proportion == 0 {
+		return nil, new(big.Int)
+	}
+	payerSteps := new(big.Int).Mul(steps, big.NewInt(int64(proportion)))
+	payerSteps.Div(payerSteps, big.NewInt(state.FeeProportionMax))
+	fee := new(big.Int).Mul(payerSteps, stepPrice)
+	if payerSteps.Sign() == 0 || as.GetDeposit().Cmp(fee) < 0 {
+		return nil, new(big.Int)
+	}
+	return as, payerSteps
+}
+
+// stepUsedDetailsOf returns the details of the used steps. Steps which are
+// not applied for a specific type are regarded as ones for the execution.
+func stepUsedDetailsOf(cc contract.CallContext, stepUsed *big.Int) txresult.StepUsedDetails {
+	applied := make(map[string]*big.Int)
+	for t, steps := range cc.StepUsedDetails() {
+		applied[string(t)] = steps
+	}
+	return txresult.NewStepUsedDetails(applied, stepUsed)
+}
+
 func (th *transactionHandler) Dispose() {
 	// Actually it is called after calling Execute(), so cc can't be nil.
 	if th.cc != nil {
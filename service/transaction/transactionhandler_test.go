@@ -0,0 +1,230 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const (
+	feeTestStepPrice  = 10
+	feeTestStepCost   = 100
+	feeTestStepUsed   = 1000
+	feeTestBalance    = 100000
+	feeTestProportion = 30
+	// the SCORE pays 30% of the fee of the transaction with feeTestStepUsed
+	feeTestPayerSteps = feeTestStepUsed * feeTestProportion / state.FeeProportionMax
+	feeTestPayerFee   = feeTestPayerSteps * feeTestStepPrice
+	feeTestFee        = feeTestStepUsed * feeTestStepPrice
+)
+
+var (
+	feeTestSender = common.NewAddressFromString("hx1111111111111111111111111111111111111111")
+	feeTestScore  = common.NewAddressFromString("cx2222222222222222222222222222222222222222")
+)
+
+// feeTestHandler uses the steps to make feeTestStepUsed with the default
+// step, and returns the status.
+type feeTestHandler struct {
+	status error
+}
+
+func (h *feeTestHandler) Prepare(ctx contract.Context) (state.WorldContext, error) {
+	return ctx, nil
+}
+
+func (h *feeTestHandler) ResetLogger(logger log.Logger) {}
+
+func (h *feeTestHandler) ExecuteSync(cc contract.CallContext) (error, *codec.TypedObj, module.Address) {
+	cc.DeductSteps(big.NewInt(feeTestStepUsed - feeTestStepCost))
+	return h.status, nil, nil
+}
+
+// newFeeTestContext returns the context on the state where the SCORE has
+// the deposit with feeTestProportion.
+func newFeeTestContext(t *testing.T, rev int, deposit int64) contract.Context {
+	ws := state.NewWorldState(db.NewMapDB(), nil, nil)
+	sys := ws.GetAccountState(state.SystemID)
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarRevision).Set(rev))
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarStepPrice).Set(feeTestStepPrice))
+	assert.NoError(t, scoredb.NewArrayDB(sys, state.VarStepTypes).Put(state.StepTypeDefault))
+	assert.NoError(t, scoredb.NewDictDB(sys, state.VarStepCosts, 1).Set(state.StepTypeDefault, feeTestStepCost))
+	assert.NoError(t, scoredb.NewArrayDB(sys, state.VarStepLimitTypes).Put(LimitTypeInvoke))
+	assert.NoError(t, scoredb.NewDictDB(sys, state.VarStepLimit, 1).Set(LimitTypeInvoke, feeTestStepUsed*10))
+
+	ws.GetAccountState(feeTestSender.ID()).SetBalance(big.NewInt(feeTestBalance))
+	as := ws.GetAccountState(feeTestScore.ID())
+	as.InitContractAccount(feeTestSender)
+	as.SetDeposit(big.NewInt(deposit))
+	as.SetFeeProportion(feeTestProportion)
+
+	ctx := contract.NewContext(state.NewWorldContext(ws, common.NewBlockInfo(1, 0)),
+		nil, nil, nil, log.New(), nil)
+	ctx.SetTransactionInfo(&state.TransactionInfo{
+		Group: module.TransactionGroupNormal,
+		Hash:  []byte("tx"),
+		From:  feeTestSender,
+	})
+	return ctx
+}
+
+func executeFeeTest(t *testing.T, ctx contract.Context, status error) txresult.Receipt {
+	th := &transactionHandler{
+		from:       feeTestSender,
+		to:         feeTestScore,
+		value:      new(big.Int),
+		stepLimit:  big.NewInt(feeTestStepUsed * 10),
+		feeSharing: true,
+		chandler:   &feeTestHandler{status: status},
+	}
+	defer th.Dispose()
+	rct, err := th.Execute(ctx, false)
+	assert.NoError(t, err)
+	assert.EqualValues(t, feeTestStepUsed, rct.StepUsed().Int64())
+	assert.EqualValues(t, feeTestStepPrice, rct.StepPrice().Int64())
+	return rct
+}
+
+func assertFeeTestBalances(t *testing.T, ctx contract.Context, senderFee, deposit int64) {
+	assert.EqualValues(t, feeTestBalance-senderFee,
+		ctx.GetAccountState(feeTestSender.ID()).GetBalance().Int64())
+	assert.EqualValues(t, deposit,
+		ctx.GetAccountState(feeTestScore.ID()).GetDeposit().Int64())
+}
+
+// feePayerOfReceipt returns feePayer in the JSON of the receipt.
+func feePayerOfReceipt(t *testing.T, rct module.Receipt) map[string]interface{} {
+	jso, err := rct.ToJSON(module.JSONVersionLast)
+	assert.NoError(t, err)
+	bs, err := json.Marshal(jso)
+	assert.NoError(t, err)
+	var rjso struct {
+		FeePayer map[string]interface{} `json:"feePayer"`
+	}
+	assert.NoError(t, json.Unmarshal(bs, &rjso))
+	return rjso.FeePayer
+}
+
+func TestTransactionHandler_FeeSharing(t *testing.T) {
+	ctx := newFeeTestContext(t, module.Revision9, feeTestPayerFee+5)
+	rct := executeFeeTest(t, ctx, nil)
+	assert.Equal(t, module.StatusSuccess, rct.Status())
+	assertFeeTestBalances(t, ctx, feeTestFee-feeTestPayerFee, 5)
+
+	payer, steps := rct.FeePayment()
+	assert.True(t, feeTestScore.Equal(payer))
+	assert.EqualValues(t, feeTestPayerSteps, steps.Int64())
+	assert.Equal(t, map[string]interface{}{
+		"address":  feeTestScore.String(),
+		"stepUsed": fmt.Sprintf("%#x", feeTestPayerSteps),
+	}, feePayerOfReceipt(t, rct))
+}
+
+func TestTransactionHandler_FeeSharingNotEnoughDeposit(t *testing.T) {
+	// the sender pays all of the fee
+	ctx := newFeeTestContext(t, module.Revision9, feeTestPayerFee-1)
+	rct := executeFeeTest(t, ctx, nil)
+	assert.Equal(t, module.StatusSuccess, rct.Status())
+	assertFeeTestBalances(t, ctx, feeTestFee, feeTestPayerFee-1)
+	assert.Nil(t, feePayerOfReceipt(t, rct))
+
+	// the deposit isn't used before the revision
+	ctx = newFeeTestContext(t, module.Revision8, feeTestPayerFee)
+	rct = executeFeeTest(t, ctx, nil)
+	assertFeeTestBalances(t, ctx, feeTestFee, feeTestPayerFee)
+	assert.Nil(t, feePayerOfReceipt(t, rct))
+}
+
+func TestTransactionHandler_FeeSharingFailure(t *testing.T) {
+	// the sender pays all of the fee if the call fails
+	ctx := newFeeTestContext(t, module.Revision9, feeTestPayerFee)
+	rct := executeFeeTest(t, ctx, scoreresult.New(module.StatusReverted, "Failure"))
+	assert.Equal(t, module.StatusReverted, rct.Status())
+	assertFeeTestBalances(t, ctx, feeTestFee, feeTestPayerFee)
+	assert.Nil(t, feePayerOfReceipt(t, rct))
+}
+
+func TestTransactionV3_PreValidateFeeSharing(t *testing.T) {
+	const stepLimit = feeTestStepUsed
+	newTx := func(to module.Address, value int64) Transaction {
+		tx, err := NewTransactionFromJSON([]byte(fmt.Sprintf(`{
+			"version":"0x3",
+			"from":"%s",
+			"to":"%s",
+			"value":"%#x",
+			"stepLimit":"%#x",
+			"timestamp":"0x1",
+			"nid":"0x1"
+		}`, feeTestSender, to, value, stepLimit)))
+		assert.NoError(t, err)
+		return tx
+	}
+
+	// the sender has the balance only for its part of the step limit
+	value := int64(feeTestBalance - (feeTestFee - feeTestPayerFee))
+	ctx := newFeeTestContext(t, module.Revision9, feeTestPayerFee)
+	assert.NoError(t, newTx(feeTestScore, value).PreValidate(ctx, false))
+	assert.True(t, NotEnoughBalanceError.Equals(
+		newTx(feeTestScore, value+1).PreValidate(ctx, false)))
+
+	// the deposit is used for the cumulative check
+	assert.NoError(t, newTx(feeTestScore, value).PreValidate(ctx, true))
+	assertFeeTestBalances(t, ctx, feeTestBalance, 0)
+	ctx.GetAccountState(feeTestSender.ID()).SetBalance(big.NewInt(feeTestBalance))
+	assert.True(t, NotEnoughBalanceError.Equals(
+		newTx(feeTestScore, value).PreValidate(ctx, false)))
+
+	// the sender pays all without enough deposit or before the revision
+	for _, ctx := range []contract.Context{
+		newFeeTestContext(t, module.Revision9, feeTestPayerFee-1),
+		newFeeTestContext(t, module.Revision8, feeTestPayerFee),
+	} {
+		assert.True(t, NotEnoughBalanceError.Equals(
+			newTx(feeTestScore, value).PreValidate(ctx, false)))
+		assert.NoError(t, newTx(feeTestScore, value-feeTestPayerFee).PreValidate(ctx, false))
+	}
+
+	// transfers to EOAs are not shared
+	ctx = newFeeTestContext(t, module.Revision9, feeTestPayerFee)
+	assert.True(t, NotEnoughBalanceError.Equals(
+		newTx(common.NewAddressFromString("hx3333333333333333333333333333333333333333"), value).PreValidate(ctx, false)))
+}
+
+func TestTransactionV3_PreValidateGoScore(t *testing.T) {
+	newTx := func(contentType string) Transaction {
+		tx, err := NewTransactionFromJSON([]byte(fmt.Sprintf(`{
+			"version":"0x3",
+			"from":"%s",
+			"to":"%s",
+			"stepLimit":"0x1000",
+			"timestamp":"0x1",
+			"nid":"0x1",
+			"dataType":"deploy",
+			"data":{"contentType":"%s","content":"0x73636f7265"}
+		}`, feeTestSender, state.SystemAddress, contentType)))
+		assert.NoError(t, err)
+		return tx
+	}
+
+	// SCOREs written in Go are allowed from Revision10
+	ctx := newFeeTestContext(t, module.Revision9, 0)
+	assert.True(t, InvalidTxValue.Equals(newTx(state.CTAppGo).PreValidate(ctx, false)))
+	assert.NoError(t, newTx(state.CTAppZip).PreValidate(ctx, false))
+
+	ctx = newFeeTestContext(t, module.Revision10, 0)
+	assert.NoError(t, newTx(state.CTAppGo).PreValidate(ctx, false))
+}
@@ -16,6 +16,7 @@ func indexAndBucketKeyFromKey(k string) (int, string) {
 }
 
 type transactionList struct {
+	order     txOrder
 	size      int
 	listFront *txElement
 	listBack  *txElement
@@ -113,9 +114,16 @@ func (b *txBloomElement) GetBloom() *TxBloom {
 }
 
 func (l *transactionList) Add(tx transaction.Transaction, ts bool) error {
+	_, err := l.add(tx, ts)
+	return err
+}
+
+// add adds the transaction to the list and returns the position where
+// it's placed.
+func (l *transactionList) add(tx transaction.Transaction, ts bool) (int, error) {
 	tidBk, tidSlot := indexAndBucketKeyFromKey(string(tx.ID()))
 	if _, ok := l.idMap[tidBk][tidSlot]; ok {
-		return ErrDuplicateTransaction
+		return -1, ErrDuplicateTransaction
 	}
 
 	e := &txElement{
@@ -128,54 +136,72 @@ func (l *transactionList) Add(tx transaction.Transaction, ts bool) error {
 
 	l.idMap[tidBk][tidSlot] = e
 
+	// transactions of the sender are ordered by their timestamps.
 	uidBk, uidSlot := indexAndBucketKeyFromKey(string(tx.From().ID()))
-	t2, ok := l.srcMapToLast[uidBk][uidSlot]
-
-	var insertPos *txElement
-	if ok {
-		ts := tx.Timestamp()
-		if t2.value.Timestamp() > ts {
-			insertPos = t2
-			for t2 = t2.srcPrev; t2 != nil; t2 = t2.srcPrev {
-				if t2.value.Timestamp() > ts {
-					insertPos = t2
-				} else {
-					break
-				}
-			}
-			if insertPos.srcPrev != nil {
-				e.srcPrev = insertPos.srcPrev
-				e.srcPrev.srcNext = e
-			}
-			e.srcNext = insertPos
-			insertPos.srcPrev = e
-		} else {
-			l.srcMapToLast[uidBk][uidSlot] = e
-		}
+	var srcNext *txElement
+	srcPrev := l.srcMapToLast[uidBk][uidSlot]
+	for srcPrev != nil && srcPrev.value.Timestamp() > tx.Timestamp() {
+		srcNext = srcPrev
+		srcPrev = srcPrev.srcPrev
+	}
+	e.srcPrev = srcPrev
+	e.srcNext = srcNext
+	if srcPrev != nil {
+		srcPrev.srcNext = e
+	}
+	if srcNext != nil {
+		srcNext.srcPrev = e
 	} else {
 		l.srcMapToLast[uidBk][uidSlot] = e
 	}
 
-	if insertPos != nil {
-		if insertPos.listPrev != nil {
-			e.listPrev = insertPos.listPrev
-			e.listPrev.listNext = e
-		} else {
-			l.listFront = e
+	// it should be placed between the previous and the next transaction
+	// of the sender, then it moves forward over the transactions of lower
+	// priority.
+	pos := l.size
+	cur := l.listBack
+	var listNext *txElement
+	if srcNext != nil {
+		for ; cur != srcNext; cur = cur.listPrev {
+			pos -= 1
 		}
-		e.listNext = insertPos
-		insertPos.listPrev = e
+		pos -= 1
+		listNext = srcNext
+		cur = srcNext.listPrev
+	}
+	for cur != nil && cur != srcPrev && l.order.Prior(tx, cur.value) {
+		listNext = cur
+		cur = cur.listPrev
+		pos -= 1
+	}
+
+	if listNext != nil {
+		e.listPrev = listNext.listPrev
+		e.listNext = listNext
+		listNext.listPrev = e
 	} else {
-		if l.listBack != nil {
-			e.listPrev = l.listBack
-			e.listPrev.listNext = e
-		} else {
-			l.listFront = e
-		}
+		e.listPrev = l.listBack
 		l.listBack = e
 	}
+	if e.listPrev != nil {
+		e.listPrev.listNext = e
+	} else {
+		l.listFront = e
+	}
 	e.updateBloom()
 	l.size += 1
+	return pos, nil
+}
+
+// FindConflict returns the transaction of the same sender, which can't be
+// in the list with the transaction.
+func (l *transactionList) FindConflict(tx transaction.Transaction) *txElement {
+	uidBk, uidSlot := indexAndBucketKeyFromKey(string(tx.From().ID()))
+	for e := l.srcMapToLast[uidBk][uidSlot]; e != nil; e = e.srcPrev {
+		if l.order.Conflict(tx, e.value) {
+			return e
+		}
+	}
 	return nil
 }
 
@@ -237,6 +263,10 @@ func (l *transactionList) Front() *txElement {
 	return l.listFront
 }
 
+func (l *transactionList) Back() *txElement {
+	return l.listBack
+}
+
 func (l *transactionList) Len() int {
 	return l.size
 }
@@ -255,7 +285,12 @@ func (l *transactionList) GetBloom() *TxBloom {
 }
 
 func newTransactionList() *transactionList {
+	return newTransactionListWithOrder(fifoOrder{})
+}
+
+func newTransactionListWithOrder(order txOrder) *transactionList {
 	l := new(transactionList)
+	l.order = order
 
 	l.idMap = make([]map[string]*txElement, txBucketCount)
 	l.srcMapToLast = make([]map[string]*txElement, txBucketCount)
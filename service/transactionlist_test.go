@@ -15,7 +15,10 @@ type mockTransaction struct {
 	NID       int
 	id        []byte
 	from      module.Address
+	to        module.Address
 	timeStamp int64
+	nonce     *big.Int
+	stepLimit *big.Int
 }
 
 func (*mockTransaction) Group() module.TransactionGroup {
@@ -62,12 +65,16 @@ func (t *mockTransaction) Timestamp() int64 {
 	return t.timeStamp
 }
 
-func (*mockTransaction) Nonce() *big.Int {
-	panic("implement me")
+func (t *mockTransaction) Nonce() *big.Int {
+	return t.nonce
+}
+
+func (t *mockTransaction) StepLimit() *big.Int {
+	return t.stepLimit
 }
 
 func (t *mockTransaction) To() module.Address {
-	panic("implement me")
+	return t.to
 }
 
 func (t *mockTransaction) ValidateNetwork(nid int) bool {
@@ -208,3 +215,69 @@ func TestTransactionList_TestSort(t *testing.T) {
 		t.Errorf("First item should be tx4 but tx=%x", tx.ID())
 	}
 }
+
+func TestTransactionList_PriorityOrder(t *testing.T) {
+	from1 := common.NewAddressFromString("hx0000000000000000000000000000000000000001")
+	from2 := common.NewAddressFromString("hx0000000000000000000000000000000000000002")
+	tx1 := newMockTransaction([]byte{0x00, 0x00, 0x00, 0x01}, from1, 1)
+	tx1.stepLimit = big.NewInt(100)
+	tx2 := newMockTransaction([]byte{0x00, 0x00, 0x00, 0x02}, from1, 2)
+	tx2.stepLimit = big.NewInt(300)
+	tx3 := newMockTransaction([]byte{0x00, 0x00, 0x00, 0x03}, from2, 1)
+	tx3.stepLimit = big.NewInt(200)
+	tx4 := newMockTransaction([]byte{0x00, 0x00, 0x00, 0x04}, from2, 0)
+	tx4.stepLimit = big.NewInt(50)
+
+	l := newTransactionListWithOrder(priorityOrder{})
+	if pos, err := l.add(tx1, false); err != nil || pos != 0 {
+		t.Errorf("Fail to add tx1 pos=%d err=%+v", pos, err)
+	}
+	if pos, err := l.add(tx3, false); err != nil || pos != 0 {
+		t.Errorf("tx3 should be placed at front pos=%d err=%+v", pos, err)
+	}
+	// tx2 can't go over tx1 which is from the same sender
+	if pos, err := l.add(tx2, false); err != nil || pos != 2 {
+		t.Errorf("tx2 should be placed after tx1 pos=%d err=%+v", pos, err)
+	}
+	// tx4 should be in front of tx3 which has later timestamp
+	if pos, err := l.add(tx4, false); err != nil || pos != 0 {
+		t.Errorf("tx4 should be placed before tx3 pos=%d err=%+v", pos, err)
+	}
+
+	expected := []*mockTransaction{tx4, tx3, tx1, tx2}
+	e := l.Front()
+	for i, tx := range expected {
+		if e == nil || e.Value() != tx {
+			t.Errorf("Invalid transaction at %d", i)
+			return
+		}
+		e = e.Next()
+	}
+	if l.Back().Value() != tx2 {
+		t.Error("tx2 should be the last one")
+	}
+}
+
+func TestTransactionList_FindConflict(t *testing.T) {
+	from1 := common.NewAddressFromString("hx0000000000000000000000000000000000000001")
+	tx1 := newMockTransaction([]byte{0x00, 0x00, 0x00, 0x01}, from1, 1)
+	tx1.nonce = big.NewInt(1)
+	tx2 := newMockTransaction([]byte{0x00, 0x00, 0x00, 0x02}, from1, 2)
+	tx2.nonce = big.NewInt(1)
+
+	l := newTransactionList()
+	l.Add(tx1, false)
+	if e := l.FindConflict(tx2); e != nil {
+		t.Error("FIFO order shouldn't have conflicts")
+	}
+
+	l = newTransactionListWithOrder(priorityOrder{})
+	l.Add(tx1, false)
+	if e := l.FindConflict(tx2); e == nil || e.Value() != tx1 {
+		t.Error("tx2 should conflict with tx1")
+	}
+	tx2.nonce = big.NewInt(2)
+	if e := l.FindConflict(tx2); e != nil {
+		t.Error("tx2 shouldn't conflict with tx1")
+	}
+}
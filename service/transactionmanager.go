@@ -32,6 +32,18 @@ type TransactionManager struct {
 	callback func()
 
 	txWaiters map[hashValue][]chan<- interface{}
+
+	txSubscribers map[*txSubscriber]struct{}
+}
+
+// txSubscriber queues events of the pool for the subscriber. Events are
+// queued without limit, so the pools don't block or drop subscribers.
+// The subscriber receives them at its own pace.
+type txSubscriber struct {
+	ch     chan *module.TransactionPoolEvent
+	events []*module.TransactionPoolEvent
+	signal chan struct{}
+	done   chan struct{}
 }
 
 func (m *TransactionManager) getTxPool(g module.TransactionGroup) *TransactionPool {
@@ -59,7 +71,50 @@ func (m *TransactionManager) HasTx(id []byte) bool {
 func (m *TransactionManager) RemoveTxs(
 	g module.TransactionGroup, l module.TransactionList,
 ) {
-	m.getTxPool(g).RemoveList(l)
+	removed := m.getTxPool(g).RemoveList(l)
+	if len(removed) == 0 {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, tx := range removed {
+		m.notifyInLock(&module.TransactionPoolEvent{
+			Type: module.TransactionRemoved,
+			ID:   tx.ID(),
+		})
+	}
+}
+
+// Transactions returns transactions in the pools. Transactions are filtered
+// by from and to if they are not nil.
+func (m *TransactionManager) Transactions(from, to module.Address, limit int) []module.Transaction {
+	var filter func(tx transaction.Transaction) bool
+	if from != nil || to != nil {
+		filter = func(tx transaction.Transaction) bool {
+			if from != nil && !from.Equal(tx.From()) {
+				return false
+			}
+			if to != nil && !to.Equal(tx.To()) {
+				return false
+			}
+			return true
+		}
+	}
+	txs := m.patchTxPool.Transactions(filter, limit)
+	if limit > 0 {
+		if limit -= len(txs); limit <= 0 {
+			return txs
+		}
+	}
+	return append(txs, m.normalTxPool.Transactions(filter, limit)...)
+}
+
+func (m *TransactionManager) Status() []*module.TransactionPoolStatus {
+	return []*module.TransactionPoolStatus{
+		m.patchTxPool.Status(),
+		m.normalTxPool.Status(),
+	}
 }
 
 func (m *TransactionManager) Candidate(
@@ -143,6 +198,11 @@ func (m *TransactionManager) OnTxDrops(drops []TxDrop) {
 	defer m.lock.Unlock()
 
 	for _, drop := range drops {
+		m.notifyInLock(&module.TransactionPoolEvent{
+			Type: module.TransactionDropped,
+			ID:   drop.ID,
+			Err:  drop.Err,
+		})
 		ws := m.removeWaitersInLock(drop.ID)
 		for _, c := range ws {
 			c <- drop.Err
@@ -212,6 +272,11 @@ func (m *TransactionManager) addInLock(tx transaction.Transaction, direct bool)
 	if err := pool.Add(tx, direct); err != nil {
 		return err
 	}
+	m.notifyInLock(&module.TransactionPoolEvent{
+		Type:        module.TransactionAdded,
+		ID:          tx.ID(),
+		Transaction: tx,
+	})
 	if m.callback != nil {
 		cb := m.callback
 		m.callback = nil
@@ -220,6 +285,65 @@ func (m *TransactionManager) addInLock(tx transaction.Transaction, direct bool)
 	return nil
 }
 
+// Subscribe returns the channel receiving events of transactions entering
+// and leaving the pools, and the function cancelling the subscription.
+// Events are delivered in order without loss. The channel is closed after
+// the subscription is cancelled.
+func (m *TransactionManager) Subscribe(size int) (<-chan *module.TransactionPoolEvent, func()) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	s := &txSubscriber{
+		ch:     make(chan *module.TransactionPoolEvent, size),
+		signal: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	m.txSubscribers[s] = struct{}{}
+	go m.deliver(s)
+	return s.ch, func() {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+
+		if _, ok := m.txSubscribers[s]; ok {
+			delete(m.txSubscribers, s)
+			close(s.done)
+		}
+	}
+}
+
+func (m *TransactionManager) deliver(s *txSubscriber) {
+	defer close(s.ch)
+	for {
+		m.lock.Lock()
+		events := s.events
+		s.events = nil
+		m.lock.Unlock()
+
+		for _, ev := range events {
+			select {
+			case s.ch <- ev:
+			case <-s.done:
+				return
+			}
+		}
+		select {
+		case <-s.signal:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (m *TransactionManager) notifyInLock(ev *module.TransactionPoolEvent) {
+	for s := range m.txSubscribers {
+		s.events = append(s.events, ev)
+		select {
+		case s.signal <- struct{}{}:
+		default:
+		}
+	}
+}
+
 func (m *TransactionManager) Wait(wc state.WorldContext, cb func()) bool {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -258,6 +382,8 @@ func NewTransactionManager(nid int, tsc *TxTimestampChecker, ptp *TransactionPoo
 		txBucket:     bk,
 		log:          logger,
 		txWaiters:    map[hashValue][]chan<- interface{}{},
+
+		txSubscribers: map[*txSubscriber]struct{}{},
 	}
 	ptp.SetTxManager(txm)
 	ntp.SetTxManager(txm)
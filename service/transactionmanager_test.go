@@ -0,0 +1,63 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+)
+
+func notifyForTest(m *TransactionManager, ev *module.TransactionPoolEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.notifyInLock(ev)
+}
+
+func TestTransactionManager_Subscribe(t *testing.T) {
+	m := &TransactionManager{
+		txSubscribers: map[*txSubscriber]struct{}{},
+	}
+	ch1, cancel1 := m.Subscribe(1)
+	ch2, cancel2 := m.Subscribe(1)
+
+	// events are queued for slow subscribers without blocking
+	const n = 100
+	for i := 0; i < n; i++ {
+		notifyForTest(m, &module.TransactionPoolEvent{
+			Type: module.TransactionAdded,
+			ID:   []byte(fmt.Sprint(i)),
+		})
+	}
+	for _, ch := range []<-chan *module.TransactionPoolEvent{ch1, ch2} {
+		for i := 0; i < n; i++ {
+			select {
+			case ev, ok := <-ch:
+				assert.True(t, ok)
+				assert.Equal(t, []byte(fmt.Sprint(i)), ev.ID)
+			case <-time.After(time.Second):
+				assert.FailNow(t, "event isn't delivered", "index=%d", i)
+			}
+		}
+	}
+
+	// the channel is closed after cancelling the subscription
+	cancel1()
+	cancel1()
+	notifyForTest(m, &module.TransactionPoolEvent{Type: module.TransactionDropped})
+	select {
+	case _, ok := <-ch1:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		assert.Fail(t, "channel isn't closed")
+	}
+	ev := <-ch2
+	assert.Equal(t, module.TransactionDropped, ev.Type)
+
+	cancel2()
+	_, ok := <-ch2
+	assert.False(t, ok)
+	assert.Len(t, m.txSubscribers, 0)
+}
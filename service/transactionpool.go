@@ -39,6 +39,8 @@ type Monitor interface {
 	OnAddTx(n int, user bool)
 	OnRemoveTx(n int, user bool)
 	OnCommit(id []byte, ts time.Time, d time.Duration)
+	OnPositionTx(pos int)
+	OnEvictTx(n int, replaced bool)
 }
 
 type TxWaiterManager interface {
@@ -61,13 +63,39 @@ func (m dummyPoolCapacityMonitor) OnPoolCapacityUpdated(group module.Transaction
 	// do nothing
 }
 
+const (
+	DropReasonExpired   = "expired"
+	DropReasonReplaced  = "replaced"
+	DropReasonEvicted   = "evicted"
+	DropReasonProcessed = "processed"
+	DropReasonInvalid   = "invalid"
+)
+
+// DropReasonOf returns the reason of the error for dropping the transaction.
+func DropReasonOf(err error) string {
+	switch {
+	case ExpiredTransactionError.Equals(err):
+		return DropReasonExpired
+	case DuplicateTransactionError.Equals(err):
+		return DropReasonReplaced
+	case TransactionPoolOverflowError.Equals(err):
+		return DropReasonEvicted
+	case errors.InvalidStateError.Equals(err):
+		return DropReasonProcessed
+	default:
+		return DropReasonInvalid
+	}
+}
+
 type TransactionPool struct {
 	group module.TransactionGroup
 
 	size int
 	txdb db.Bucket
 
-	list *transactionList
+	order txOrder
+	list  *transactionList
+	drops map[string]int
 
 	mutex sync.Mutex
 
@@ -77,12 +105,15 @@ type TransactionPool struct {
 	log     log.Logger
 }
 
-func NewTransactionPool(group module.TransactionGroup, size int, txdb db.Bucket, m Monitor, log log.Logger) *TransactionPool {
+func NewTransactionPool(group module.TransactionGroup, size int, order string, txdb db.Bucket, m Monitor, log log.Logger) *TransactionPool {
+	txo := newTxOrder(order)
 	pool := &TransactionPool{
 		group:   group,
 		size:    size,
 		txdb:    txdb,
-		list:    newTransactionList(),
+		order:   txo,
+		list:    newTransactionListWithOrder(txo),
+		drops:   make(map[string]int),
 		txm:     dummyTxWaiterManager{},
 		monitor: m,
 		pcm:     dummyPoolCapacityMonitor{},
@@ -111,6 +142,7 @@ func (tp *TransactionPool) DropOldTXs(bts int64) {
 			}
 			tp.log.Debugf("DROP TX: id=0x%x reason=%v", tx.ID(), iter.err)
 			drops = append(drops, TxDrop{tx.ID(), iter.err})
+			tp.drops[DropReasonOf(iter.err)]++
 			tp.monitor.OnDropTx(len(tx.Bytes()), direct)
 		}
 		iter = next
@@ -237,6 +269,8 @@ func (tp *TransactionPool) CheckTxs(wc state.WorldContext) bool {
 /*
 	return nil if tx is nil or tx is added to pool
 	return ErrTransactionPoolOverFlow if pool is full
+	If the ordering policy allows, it replaces the conflicting transaction
+	or evicts the last one to make a room for the transaction.
 */
 func (tp *TransactionPool) Add(tx transaction.Transaction, direct bool) error {
 	if tx == nil {
@@ -245,30 +279,65 @@ func (tp *TransactionPool) Add(tx transaction.Transaction, direct bool) error {
 	tp.mutex.Lock()
 	defer tp.mutex.Unlock()
 
-	if tp.list.Len() >= tp.size {
-		return ErrTransactionPoolOverFlow
+	if tp.list.HasTx(tx.ID()) {
+		return ErrDuplicateTransaction
 	}
 
-	err := tp.list.Add(tx, direct)
-	if err == nil {
-		tp.monitor.OnAddTx(len(tx.Bytes()), direct)
-		tp.pcm.OnPoolCapacityUpdated(tp.group, tp.size, tp.list.Len())
+	var victim *txElement
+	replaced := false
+	if e := tp.list.FindConflict(tx); e != nil {
+		if !tp.order.Prior(tx, e.Value()) {
+			return DuplicateTransactionError.Errorf(
+				"ConflictTransaction(id=%#x,nonce=%s)", e.Value().ID(), tx.Nonce())
+		}
+		e.err = DuplicateTransactionError.Errorf(
+			"ReplacedTransaction(by=%#x)", tx.ID())
+		victim = e
+		replaced = true
+	} else if tp.list.Len() >= tp.size {
+		e := tp.list.Back()
+		if e == nil || !tp.order.Prior(tx, e.Value()) {
+			return ErrTransactionPoolOverFlow
+		}
+		e.err = TransactionPoolOverflowError.Errorf(
+			"EvictedTransaction(by=%#x)", tx.ID())
+		victim = e
+	}
+
+	pos, err := tp.list.add(tx, direct)
+	if err != nil {
+		return err
+	}
+	tp.monitor.OnAddTx(len(tx.Bytes()), direct)
+	tp.monitor.OnPositionTx(pos)
+
+	if victim != nil && tp.list.Remove(victim) {
+		vtx := victim.Value()
+		tp.log.Debugf("DROP TX: id=0x%x reason=%v", vtx.ID(), victim.err)
+		tp.drops[DropReasonOf(victim.err)]++
+		tp.monitor.OnDropTx(len(vtx.Bytes()), victim.ts != 0)
+		tp.monitor.OnEvictTx(len(vtx.Bytes()), replaced)
+		// it's called with the lock of TransactionManager.
+		go tp.txm.OnTxDrops([]TxDrop{{vtx.ID(), victim.err}})
 	}
-	return err
+	tp.pcm.OnPoolCapacityUpdated(tp.group, tp.size, tp.list.Len())
+	return nil
 }
 
-// removeList remove transactions when transactions are finalized.
-func (tp *TransactionPool) RemoveList(txs module.TransactionList) {
+// RemoveList remove transactions when transactions are finalized.
+// It returns the transactions removed from the pool.
+func (tp *TransactionPool) RemoveList(txs module.TransactionList) []module.Transaction {
 	tp.mutex.Lock()
 	defer tp.mutex.Unlock()
 
 	if tp.list.Len() == 0 {
-		return
+		return nil
 	}
 
 	now := time.Now()
 	var duration time.Duration
 	var count int
+	var removed []module.Transaction
 
 	for i := txs.Iterator(); i.Has(); i.Next() {
 		t, _, err := i.Get()
@@ -277,6 +346,7 @@ func (tp *TransactionPool) RemoveList(txs module.TransactionList) {
 			continue
 		}
 		if ok, ts := tp.list.RemoveTx(t); ok {
+			removed = append(removed, t)
 			if ts != 0 {
 				duration += now.Sub(time.Unix(0, ts))
 				count += 1
@@ -289,6 +359,7 @@ func (tp *TransactionPool) RemoveList(txs module.TransactionList) {
 		tp.pcm.OnPoolCapacityUpdated(tp.group, tp.size, tp.list.Len())
 		tp.monitor.OnCommit(txs.Hash(), now, duration/time.Duration(count))
 	}
+	return removed
 }
 
 func (tp *TransactionPool) HasTx(tid []byte) bool {
@@ -309,6 +380,41 @@ func (tp *TransactionPool) Used() int {
 	return tp.list.Len()
 }
 
+// Transactions returns transactions in the pool in the order of the pool.
+// Only the transactions accepted by the filter are returned if filter is
+// not nil. It returns all of them for a non-positive max.
+func (tp *TransactionPool) Transactions(filter func(tx transaction.Transaction) bool, max int) []module.Transaction {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	var txs []module.Transaction
+	for e := tp.list.Front(); e != nil && (max <= 0 || len(txs) < max); e = e.Next() {
+		tx := e.Value()
+		if filter == nil || filter(tx) {
+			txs = append(txs, tx)
+		}
+	}
+	return txs
+}
+
+// Status returns the capacity and the number of dropped transactions for
+// each reason of the pool.
+func (tp *TransactionPool) Status() *module.TransactionPoolStatus {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	drops := make(map[string]int, len(tp.drops))
+	for k, v := range tp.drops {
+		drops[k] = v
+	}
+	return &module.TransactionPoolStatus{
+		Group: tp.group,
+		Size:  tp.size,
+		Used:  tp.list.Len(),
+		Drops: drops,
+	}
+}
+
 func (tp *TransactionPool) SetTxManager(txm TxWaiterManager) {
 	tp.mutex.Lock()
 	defer tp.mutex.Unlock()
@@ -345,6 +451,7 @@ func (tp *TransactionPool) dropTransactions(txs []*txElement) {
 			}
 			tp.log.Debugf("DROP TX: id=0x%x reason=%v", tx.ID(), e.err)
 			drops = append(drops, TxDrop{tx.ID(), e.err})
+			tp.drops[DropReasonOf(e.err)]++
 			tp.monitor.OnDropTx(len(tx.Bytes()), direct)
 		}
 	}
@@ -1,13 +1,17 @@
 package service
 
 import (
+	"math/big"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/transaction"
 )
 
 type mockMonitor struct {
@@ -29,10 +33,18 @@ func (m *mockMonitor) OnCommit(id []byte, ts time.Time, d time.Duration) {
 	// do nothing
 }
 
+func (m *mockMonitor) OnPositionTx(pos int) {
+	// do nothing
+}
+
+func (m *mockMonitor) OnEvictTx(n int, replaced bool) {
+	// do nothing
+}
+
 func TestTransactionPool_Add(t *testing.T) {
 	dbase := db.NewMapDB()
 	bk, _ := dbase.GetBucket(db.TransactionLocatorByHash)
-	pool := NewTransactionPool(module.TransactionGroupNormal, 5000, bk, &mockMonitor{}, log.New())
+	pool := NewTransactionPool(module.TransactionGroupNormal, 5000, TxOrderFIFO, bk, &mockMonitor{}, log.New())
 
 	addr := common.NewAddressFromString("hx1111111111111111111111111111111111111111")
 	tx1 := newMockTransaction([]byte("tx1"), addr, 1)
@@ -42,3 +54,96 @@ func TestTransactionPool_Add(t *testing.T) {
 		t.Error("Fail to add transaction with valid network ID")
 	}
 }
+
+func TestTransactionPool_PriorityEviction(t *testing.T) {
+	dbase := db.NewMapDB()
+	bk, _ := dbase.GetBucket(db.TransactionLocatorByHash)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 2, TxOrderPriority, bk, &mockMonitor{}, log.New())
+
+	addr1 := common.NewAddressFromString("hx1111111111111111111111111111111111111111")
+	addr2 := common.NewAddressFromString("hx2222222222222222222222222222222222222222")
+	addr3 := common.NewAddressFromString("hx3333333333333333333333333333333333333333")
+	tx1 := newMockTransaction([]byte("tx1"), addr1, 1)
+	tx1.stepLimit = big.NewInt(100)
+	tx2 := newMockTransaction([]byte("tx2"), addr2, 1)
+	tx2.stepLimit = big.NewInt(200)
+	tx3 := newMockTransaction([]byte("tx3"), addr3, 1)
+	tx3.stepLimit = big.NewInt(50)
+	tx4 := newMockTransaction([]byte("tx4"), addr3, 2)
+	tx4.stepLimit = big.NewInt(300)
+
+	assert.NoError(t, pool.Add(tx1, true))
+	assert.NoError(t, pool.Add(tx2, true))
+	assert.Error(t, pool.Add(tx3, true))
+	assert.NoError(t, pool.Add(tx4, true))
+
+	assert.Equal(t, 2, pool.Used())
+	assert.False(t, pool.HasTx(tx1.ID()))
+	assert.True(t, pool.HasTx(tx2.ID()))
+	assert.True(t, pool.HasTx(tx4.ID()))
+}
+
+func TestTransactionPool_PriorityReplacement(t *testing.T) {
+	dbase := db.NewMapDB()
+	bk, _ := dbase.GetBucket(db.TransactionLocatorByHash)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 10, TxOrderPriority, bk, &mockMonitor{}, log.New())
+
+	addr := common.NewAddressFromString("hx1111111111111111111111111111111111111111")
+	tx1 := newMockTransaction([]byte("tx1"), addr, 1)
+	tx1.nonce = big.NewInt(7)
+	tx1.stepLimit = big.NewInt(100)
+	tx2 := newMockTransaction([]byte("tx2"), addr, 2)
+	tx2.nonce = big.NewInt(7)
+	tx2.stepLimit = big.NewInt(100)
+	tx3 := newMockTransaction([]byte("tx3"), addr, 3)
+	tx3.nonce = big.NewInt(7)
+	tx3.stepLimit = big.NewInt(200)
+
+	assert.NoError(t, pool.Add(tx1, true))
+	assert.Error(t, pool.Add(tx2, true))
+	assert.NoError(t, pool.Add(tx3, true))
+
+	assert.Equal(t, 1, pool.Used())
+	assert.False(t, pool.HasTx(tx1.ID()))
+	assert.True(t, pool.HasTx(tx3.ID()))
+}
+
+func TestTransactionPool_TransactionsAndStatus(t *testing.T) {
+	dbase := db.NewMapDB()
+	bk, _ := dbase.GetBucket(db.TransactionLocatorByHash)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 2, TxOrderPriority, bk, &mockMonitor{}, log.New())
+
+	addr1 := common.NewAddressFromString("hx1111111111111111111111111111111111111111")
+	addr2 := common.NewAddressFromString("hx2222222222222222222222222222222222222222")
+	tx1 := newMockTransaction([]byte("tx1"), addr1, 1)
+	tx1.nonce = big.NewInt(7)
+	tx1.stepLimit = big.NewInt(100)
+	tx2 := newMockTransaction([]byte("tx2"), addr1, 2)
+	tx2.nonce = big.NewInt(7)
+	tx2.stepLimit = big.NewInt(200)
+	tx3 := newMockTransaction([]byte("tx3"), addr2, 3)
+	tx3.stepLimit = big.NewInt(50)
+	tx4 := newMockTransaction([]byte("tx4"), addr2, 4)
+	tx4.stepLimit = big.NewInt(300)
+
+	assert.NoError(t, pool.Add(tx1, true))
+	assert.NoError(t, pool.Add(tx2, true))
+	assert.NoError(t, pool.Add(tx3, true))
+	assert.NoError(t, pool.Add(tx4, true))
+
+	txs := pool.Transactions(nil, 0)
+	assert.Equal(t, 2, len(txs))
+	txs = pool.Transactions(func(tx transaction.Transaction) bool {
+		return addr2.Equal(tx.From())
+	}, 0)
+	assert.Equal(t, 1, len(txs))
+	assert.Equal(t, tx4.ID(), txs[0].ID())
+	assert.Equal(t, 1, len(pool.Transactions(nil, 1)))
+
+	status := pool.Status()
+	assert.Equal(t, module.TransactionGroupNormal, status.Group)
+	assert.Equal(t, 2, status.Size)
+	assert.Equal(t, 2, status.Used)
+	assert.Equal(t, 1, status.Drops[DropReasonReplaced])
+	assert.Equal(t, 1, status.Drops[DropReasonEvicted])
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/service/scoredb"
 	"github.com/icon-project/goloop/service/transaction"
+	"github.com/icon-project/goloop/service/transitionresult"
 
 	"github.com/icon-project/goloop/service/contract"
 	"github.com/icon-project/goloop/service/state"
@@ -105,6 +106,14 @@ type transition struct {
 	normalReceipts module.ReceiptList
 	logsBloom      txresult.LogsBloom
 
+	// internalTxs are internal transactions of the normal transactions.
+	// It's collected only if recording of them is enabled.
+	internalTxs [][]module.InternalTransaction
+
+	// stepDetails are details of the used steps of the normal transactions.
+	// It's collected only if recording of them is enabled.
+	stepDetails []txresult.StepUsedDetails
+
 	transactionCount int
 	executeDuration  time.Duration
 	flushDuration    time.Duration
@@ -115,27 +124,10 @@ type transition struct {
 	ti *module.TraceInfo
 }
 
-type transitionResult struct {
-	StateHash         []byte
-	PatchReceiptHash  []byte
-	NormalReceiptHash []byte
-}
+type transitionResult = transitionresult.Result
 
 func newTransitionResultFromBytes(bs []byte) (*transitionResult, error) {
-	tresult := new(transitionResult)
-	if _, err := codec.UnmarshalFromBytes(bs, tresult); err != nil {
-		return nil, err
-	}
-	return tresult, nil
-}
-
-func (tr *transitionResult) Bytes() []byte {
-	if bs, err := codec.MarshalToBytes(tr); err != nil {
-		log.Debug("Fail to marshal transitionResult")
-		return nil
-	} else {
-		return bs
-	}
+	return transitionresult.NewFromBytes(bs)
 }
 
 func patchTransition(t *transition, patchTXs module.TransactionList) *transition {
@@ -286,18 +278,20 @@ func (t *transition) ExecuteForTrace(ti module.TraceInfo) (canceler func() bool,
 	if ti.Callback == nil {
 		return nil, errors.IllegalArgumentError.New("TraceCallbackIsNil")
 	}
+	var txs module.TransactionList
 	switch ti.Group {
 	case module.TransactionGroupNormal:
-		if _, err := t.normalTransactions.Get(ti.Index); err != nil {
-			return nil, errors.IllegalArgumentError.Errorf("InvalidTransactionIndex(n=%d)", ti.Index)
-		}
+		txs = t.normalTransactions
 	case module.TransactionGroupPatch:
-		if _, err := t.patchTransactions.Get(ti.Index); err != nil {
-			return nil, errors.IllegalArgumentError.Errorf("InvalidTransactionIndex(n=%d)", ti.Index)
-		}
+		txs = t.patchTransactions
 	default:
 		return nil, errors.IllegalArgumentError.Errorf("UnknownTransactionGroup(%d)", ti.Group)
 	}
+	if ti.Index >= 0 {
+		if _, err := txs.Get(ti.Index); err != nil {
+			return nil, errors.IllegalArgumentError.Errorf("InvalidTransactionIndex(n=%d)", ti.Index)
+		}
+	}
 
 	return t.startExecution(func() error {
 		if t.syncer != nil {
@@ -439,9 +433,9 @@ func (t *transition) doForceSync() {
 	t.normalReceipts = sr.NormalReceipts
 	t.worldSnapshot = sr.Wss
 	tresult := transitionResult{
-		t.worldSnapshot.StateHash(),
-		t.patchReceipts.Hash(),
-		t.normalReceipts.Hash(),
+		StateHash:         t.worldSnapshot.StateHash(),
+		PatchReceiptHash:  t.patchReceipts.Hash(),
+		NormalReceiptHash: t.normalReceipts.Hash(),
 	}
 	t.result = tresult.Bytes()
 	t.reportExecution(nil)
@@ -526,6 +520,18 @@ func (t *transition) doExecute(alreadyValidated bool) {
 	}
 	t.patchReceipts = txresult.NewReceiptListFromSlice(t.db, patchReceipts)
 	t.normalReceipts = txresult.NewReceiptListFromSlice(t.db, normalReceipts)
+	if t.chain.InternalTx() {
+		t.internalTxs = make([][]module.InternalTransaction, len(normalReceipts))
+		for i, r := range normalReceipts {
+			t.internalTxs[i] = r.InternalTransactions()
+		}
+	}
+	if t.chain.StepDetails() {
+		t.stepDetails = make([]txresult.StepUsedDetails, len(normalReceipts))
+		for i, r := range normalReceipts {
+			t.stepDetails[i] = r.StepUsedDetails()
+		}
+	}
 
 	// save gathered fee to treasury
 	tr := ctx.GetAccountState(ctx.Treasury().ID())
@@ -546,9 +552,9 @@ func (t *transition) doExecute(alreadyValidated bool) {
 		float64(txCount)/elapsedMS*1000)
 
 	tresult := transitionResult{
-		t.worldSnapshot.StateHash(),
-		t.patchReceipts.Hash(),
-		t.normalReceipts.Hash(),
+		StateHash:         t.worldSnapshot.StateHash(),
+		PatchReceiptHash:  t.patchReceipts.Hash(),
+		NormalReceiptHash: t.normalReceipts.Hash(),
 	}
 	t.result = tresult.Bytes()
 
@@ -605,7 +611,11 @@ func (t *transition) executeTxs(l module.TransactionList, ctx contract.Context,
 		}
 		return nil
 	}
-	if cc := t.chain.ConcurrencyLevel(); cc > 1 {
+	// traced transactions are executed in order for the tracer.
+	if cc := t.chain.ConcurrencyLevel(); cc > 1 && t.ti == nil {
+		if t.chain.ParallelCheck() {
+			return t.executeTxsWithCheck(cc, l, ctx, rctBuf)
+		}
 		return t.executeTxsConcurrent(cc, l, ctx, rctBuf)
 	}
 	return t.executeTxsSequential(l, ctx, rctBuf)
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bytes"
 	"sync"
 
 	"github.com/icon-project/goloop/common/errors"
@@ -11,118 +12,209 @@ import (
 	"github.com/icon-project/goloop/service/txresult"
 )
 
-type executionContext struct {
-	waiter    chan struct{}
-	lastError error
-	lock      sync.Mutex
+// speculation is the result of the speculative execution of a transaction.
+type speculation struct {
+	done chan struct{}
+	wss  state.WorldSpeculativeState
+	rct  txresult.Receipt
+	err  error
 }
 
-func (c *executionContext) Done() {
-	c.waiter <- struct{}{}
-}
-
-func (c *executionContext) Ready() {
-	<-c.waiter
+func (t *transition) newSpeculativeContext(ctx contract.Context, base state.WorldSnapshot) (contract.Context, state.WorldSpeculativeState, error) {
+	wss, err := state.NewWorldSpeculativeState(base)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ctx.NodeCacheEnabled() {
+		wss.EnableNodeCache()
+	}
+	wc := state.NewWorldContext(wss, t.bi)
+	return contract.NewContext(wc, t.cm, t.eem, t.chain, t.log, t.ti), wss, nil
 }
 
-func (c *executionContext) Error() error {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	return c.lastError
-}
+// speculate executes the transaction on the snapshot. It doesn't retry on
+// failure, because the transaction is executed again with the current
+// state if it fails.
+func (t *transition) speculate(ctx contract.Context, base state.WorldSnapshot, txo transaction.Transaction, idx int, s *speculation) {
+	defer close(s.done)
 
-func (c *executionContext) Report(e error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if c.lastError != nil {
-		c.lastError = e
+	sctx, wss, err := t.newSpeculativeContext(ctx, base)
+	if err != nil {
+		s.err = err
+		return
+	}
+	txh, err := txo.GetHandler(t.cm)
+	if err != nil {
+		s.err = err
+		return
 	}
+	sctx.SetTransactionInfo(&state.TransactionInfo{
+		Group:     txo.Group(),
+		Index:     int32(idx),
+		Timestamp: txo.Timestamp(),
+		Nonce:     txo.Nonce(),
+		Hash:      txo.ID(),
+		From:      txo.From(),
+	})
+	sctx.UpdateSystemInfo()
+	s.rct, s.err = txh.Execute(sctx, false)
+	txh.Dispose()
+	s.wss = wss
 }
 
-func newExecutionContext(n int) *executionContext {
-	ch := make(chan struct{}, n)
-	for i := 0; i < n; i++ {
-		ch <- struct{}{}
+func hasConflict(reads []string, written map[string]bool) bool {
+	for _, id := range reads {
+		if written[id] {
+			return true
+		}
 	}
-	return &executionContext{waiter: ch}
+	return false
 }
 
+// executeTxsConcurrent executes transactions speculatively in parallel on
+// the snapshot of the world before them. Then it applies the results in
+// order of the transactions. If a transaction accessed any account written
+// by the preceding transactions after the snapshot, or its speculative
+// execution failed, then it's executed again with the current state.
+// So, results are same as the sequential execution.
 func (t *transition) executeTxsConcurrent(level int, l module.TransactionList, ctx contract.Context, rctBuf []txresult.Receipt) error {
-	ec := newExecutionContext(level)
-
-	cnt := 0
+	txs := make([]transaction.Transaction, 0, len(rctBuf))
 	for i := l.Iterator(); i.Has(); i.Next() {
-		if err := ec.Error(); err != nil {
+		txi, _, err := i.Get()
+		if err != nil {
+			t.log.Errorf("Fail to iterate transaction list err=%+v", err)
 			return err
 		}
+		txs = append(txs, txi.(transaction.Transaction))
+	}
 
+	base := ctx.GetSnapshot()
+	specs := make([]*speculation, len(txs))
+	jobs := make(chan int, len(txs))
+	for idx := range txs {
+		specs[idx] = &speculation{done: make(chan struct{})}
+		jobs <- idx
+	}
+	close(jobs)
+
+	// Workers check quit only between jobs, so it waits for speculations
+	// in progress before returning not to use the context after that.
+	quit := make(chan struct{})
+	var wg sync.WaitGroup
+	defer func() {
+		close(quit)
+		wg.Wait()
+	}()
+	for i := 0; i < level; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				select {
+				case <-quit:
+					return
+				default:
+				}
+				t.speculate(ctx, base, txs[idx], idx, specs[idx])
+			}
+		}()
+	}
+
+	var conflicts int
+	written := make(map[string]bool)
+	for idx, txo := range txs {
 		if t.step == stepCanceled {
 			return ErrTransitionInterrupted
 		}
-
-		txi, _, err := i.Get()
-		if err != nil {
-			t.log.Errorf("Fail to iterate transaction list err=%+v", err)
-			return err
+		s := specs[idx]
+		<-s.done
+		if s.err != nil || hasConflict(s.wss.ReadSet(), written) {
+			if s.err != nil {
+				t.log.Debugf("RE-EXECUTE TX <%#x> for err=%+v", txo.ID(), s.err)
+			} else {
+				t.log.Tracef("RE-EXECUTE TX <%#x> for conflict", txo.ID())
+			}
+			conflicts++
+			sctx, wss, err := t.newSpeculativeContext(ctx, ctx.GetSnapshot())
+			if err != nil {
+				return err
+			}
+			rct, err := t.executeTx(sctx, txo, idx)
+			if err != nil {
+				return err
+			}
+			s.wss, s.rct = wss, rct
 		}
-		txo := txi.(transaction.Transaction)
-		txh, err := txo.GetHandler(t.cm)
-		if err != nil {
-			t.log.Debugf("Fail to handle transaction for %+v", err)
+		if err := s.wss.Apply(ctx); err != nil {
 			return err
 		}
-		wc, err2 := txh.Prepare(ctx)
-		ctx = contract.NewContext(wc, t.cm, t.eem, t.chain, t.log, t.ti)
-		if err2 != nil {
-			t.log.Debugf("Fail to prepare for %+v", err2)
-			return err2
+		for _, id := range s.wss.WriteSet() {
+			written[id] = true
 		}
+		rctBuf[idx] = s.rct
+	}
+	t.log.Debugf("Parallel execution txs=%d re-executed=%d", len(txs), conflicts)
+	return nil
+}
 
-		ec.Ready()
-		go func(ctx contract.Context, wc state.WorldContext, txo transaction.Transaction, cnt int, rb *txresult.Receipt) {
-			ctx.SetTransactionInfo(&state.TransactionInfo{
-				Group:     txo.Group(),
-				Index:     int32(cnt),
-				Timestamp: txo.Timestamp(),
-				Nonce:     txo.Nonce(),
-				Hash:      txo.ID(),
-				From:      txo.From(),
-			})
-			ctx.UpdateSystemInfo()
-			wvs := ctx.WorldVirtualState()
-			for trials := RetryCount + 1; trials > 0; trials -= 1 {
-				rct, err := txh.Execute(ctx, false)
-				txh.Dispose()
-
-				if err == nil {
-					*rb = rct
-					break
-				}
-
-				if !errors.ExecutionFailError.Equals(err) || trials <= 1 {
-					t.log.Debugf("Fail to execute transaction err=%+v", err)
-					ec.Report(err)
-					break
-				}
-
-				t.log.Warnf("RETRY TX <%#x> for err=%+v", txo.ID(), err)
-
-				txh, err = txo.GetHandler(t.cm)
-				if err != nil {
-					t.log.Debugf("Fail to get handler err=%+v", err)
-					ec.Report(err)
-					break
-				}
-				ctx = contract.NewContext(wc, t.cm, t.eem, t.chain, t.log, t.ti)
+// executeTxsWithCheck executes transactions in parallel, then it executes
+// them again sequentially on the same state to compare the results. If they
+// are different, it reports the difference and uses the results of the
+// sequential execution.
+func (t *transition) executeTxsWithCheck(level int, l module.TransactionList, ctx contract.Context, rctBuf []txresult.Receipt) error {
+	ws, err := state.WorldStateFromSnapshot(ctx.GetSnapshot())
+	if err != nil {
+		return err
+	}
+	if ctx.NodeCacheEnabled() {
+		ws.EnableNodeCache()
+	}
+	seqCtx := contract.NewContext(ctx.WorldStateChanged(ws), t.cm, t.eem, t.chain, t.log, t.ti)
+	seqRcts := make([]txresult.Receipt, len(rctBuf))
+
+	perr := t.executeTxsConcurrent(level, l, ctx, rctBuf)
+	serr := t.executeTxsSequential(l, seqCtx, seqRcts)
+	if err := checkParallelResult(ctx, rctBuf, perr, seqCtx, seqRcts, serr); err != nil {
+		t.log.Errorf("Parallel execution mismatch height=%d err=%+v",
+			t.bi.Height(), err)
+		if serr == nil {
+			if err := ctx.Reset(seqCtx.GetSnapshot()); err != nil {
+				return err
 			}
-			wvs.Commit()
-			ec.Done()
-		}(ctx, wc, txo, cnt, &rctBuf[cnt])
+			copy(rctBuf, seqRcts)
+		}
+	}
+	return serr
+}
 
-		cnt++
+func checkParallelResult(
+	ctx contract.Context, rcts []txresult.Receipt, perr error,
+	seqCtx contract.Context, seqRcts []txresult.Receipt, serr error,
+) error {
+	if perr != nil || serr != nil {
+		if (perr == nil) != (serr == nil) {
+			return errors.InvalidStateError.Errorf(
+				"DifferentError(parallel=%v,sequential=%v)", perr, serr)
+		}
+		return nil
+	}
+	for idx, rct := range rcts {
+		if !bytes.Equal(rct.Bytes(), seqRcts[idx].Bytes()) {
+			return errors.InvalidStateError.Errorf(
+				"DifferentReceipt(idx=%d,parallel=%x,sequential=%x)",
+				idx, rct.Bytes(), seqRcts[idx].Bytes())
+		}
+	}
+	wss, seqWss := ctx.GetSnapshot(), seqCtx.GetSnapshot()
+	if !bytes.Equal(wss.StateHash(), seqWss.StateHash()) {
+		return errors.InvalidStateError.Errorf(
+			"DifferentStateHash(parallel=%x,sequential=%x)",
+			wss.StateHash(), seqWss.StateHash())
 	}
-	if wvs := ctx.WorldVirtualState(); wvs != nil {
-		wvs.Realize()
+	vh, seqVh := wss.GetValidatorSnapshot().Hash(), seqWss.GetValidatorSnapshot().Hash()
+	if !bytes.Equal(vh, seqVh) {
+		return errors.InvalidStateError.Errorf(
+			"DifferentValidators(parallel=%x,sequential=%x)", vh, seqVh)
 	}
 	return nil
 }
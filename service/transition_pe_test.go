@@ -0,0 +1,147 @@
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+var (
+	parallelTestGov       = common.NewAddressFromString("hx0000000000000000000000000000000000000100")
+	parallelTestScore     = common.NewAddressFromString("cx0000000000000000000000000000000000000200")
+	parallelTestValidator = common.NewAddressFromString("hx0000000000000000000000000000000000000300")
+)
+
+func parallelTestAddress(i int) module.Address {
+	return common.NewAddressFromString(fmt.Sprintf("hx%040x", i))
+}
+
+// newParallelTestSnapshot returns the snapshot of the world with the chain
+// SCORE, the SCORE accepting deposits, and the accounts having balances.
+func newParallelTestSnapshot(t *testing.T, cm contract.ContractManager, bi module.BlockInfo) state.WorldSnapshot {
+	ws := state.NewWorldState(db.NewMapDB(), nil, nil)
+	ctx := contract.NewContext(state.NewWorldContext(ws, bi), cm, nil, nil, log.New(), nil)
+	cc := contract.NewCallContext(ctx, big.NewInt(0), false)
+	err := contract.InstallChainSCORE(state.SystemID, contract.CID_CHAIN,
+		state.SystemAddress, []byte(fmt.Sprintf(`{
+			"revision":"0x6",
+			"fee":{
+				"stepPrice":"0xa",
+				"stepLimit":{"invoke":"0x100000"},
+				"stepCosts":{"default":"0x64","contractCall":"0x32"}
+			},
+			"validatorList":["%s"]
+		}`, parallelTestAddress(1))), cc, nil)
+	cc.Dispose()
+	assert.NoError(t, err)
+
+	sys := ws.GetAccountState(state.SystemID)
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarRevision).Set(module.Revision9))
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarGovernance).Set(parallelTestGov))
+
+	as := ws.GetAccountState(parallelTestScore.ID())
+	as.InitContractAccount(parallelTestGov)
+	_, err = as.DeployContract([]byte("code"), state.PythonEE, state.CTAppZip, nil, []byte("tx"))
+	assert.NoError(t, err)
+	assert.NoError(t, as.AcceptContract([]byte("tx"), nil))
+
+	ws.GetAccountState(parallelTestGov.ID()).SetBalance(big.NewInt(1000000))
+	for i := 1; i <= 8; i++ {
+		ws.GetAccountState(parallelTestAddress(i).ID()).SetBalance(big.NewInt(1000000))
+	}
+	return ws.GetSnapshot()
+}
+
+func newParallelTestTx(t *testing.T, from, to module.Address, value int64, data string) module.Transaction {
+	js := fmt.Sprintf(`{
+		"version":"0x3",
+		"from":"%s",
+		"to":"%s",
+		"value":"%#x",
+		"stepLimit":"0x100000",
+		"timestamp":"0x1",
+		"nid":"0x1"%s
+	}`, from, to, value, data)
+	tx, err := transaction.NewTransactionFromJSON([]byte(js))
+	assert.NoError(t, err)
+	return tx
+}
+
+func TestTransition_ExecuteTxsConcurrent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "parallel")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	logger := log.New()
+	cm, err := contract.NewContractManager(db.NewMapDB(), dir, logger)
+	assert.NoError(t, err)
+	bi := common.NewBlockInfo(2, 0)
+	base := newParallelTestSnapshot(t, cm, bi)
+
+	deposit := `,"dataType":"deposit","data":{"action":"add"}`
+	grant := func(addr module.Address) string {
+		return fmt.Sprintf(`,"dataType":"call","data":{"method":"grantValidator","params":{"address":"%s"}}`, addr)
+	}
+	addr := parallelTestAddress
+	txs := []module.Transaction{
+		// the same sender
+		newParallelTestTx(t, addr(1), addr(2), 1000, ""),
+		newParallelTestTx(t, addr(1), addr(3), 500, ""),
+		// it uses the balance transferred by the preceding one for the fee
+		newParallelTestTx(t, addr(3), addr(4), 999500, ""),
+		// the same SCORE
+		newParallelTestTx(t, addr(5), parallelTestScore, 100, deposit),
+		newParallelTestTx(t, addr(6), parallelTestScore, 200, deposit),
+		// changes of the validators
+		newParallelTestTx(t, parallelTestGov, state.SystemAddress, 0, grant(parallelTestValidator)),
+		newParallelTestTx(t, parallelTestGov, state.SystemAddress, 0, grant(addr(7))),
+		// the independent one
+		newParallelTestTx(t, addr(8), addr(4), 10, ""),
+	}
+
+	for _, level := range []int{2, 4, len(txs)} {
+		tr := &transition{cm: cm, bi: bi, log: logger}
+		l := transaction.NewTransactionListFromSlice(db.NewMapDB(), txs)
+
+		ws, err := state.WorldStateFromSnapshot(base)
+		assert.NoError(t, err)
+		ctx := contract.NewContext(state.NewWorldContext(ws, bi), cm, nil, nil, logger, nil)
+		rcts := make([]txresult.Receipt, len(txs))
+		perr := tr.executeTxsConcurrent(level, l, ctx, rcts)
+		assert.NoError(t, perr)
+
+		seqWs, err := state.WorldStateFromSnapshot(base)
+		assert.NoError(t, err)
+		seqCtx := contract.NewContext(state.NewWorldContext(seqWs, bi), cm, nil, nil, logger, nil)
+		seqRcts := make([]txresult.Receipt, len(txs))
+		serr := tr.executeTxsSequential(l, seqCtx, seqRcts)
+		assert.NoError(t, serr)
+
+		assert.NoError(t, checkParallelResult(ctx, rcts, perr, seqCtx, seqRcts, serr), "level=%d", level)
+
+		// all of them succeed, so the check isn't for the same failures
+		for idx, rct := range rcts {
+			assert.Equal(t, module.StatusSuccess, rct.Status(), "level=%d idx=%d", level, idx)
+		}
+		assert.EqualValues(t, 1000000+999500+10, ctx.GetAccountState(addr(4).ID()).GetBalance().Int64())
+		assert.EqualValues(t, 300, ctx.GetAccountState(parallelTestScore.ID()).GetDeposit().Int64())
+		vs := ctx.GetValidatorState()
+		assert.Equal(t, 3, vs.Len())
+		assert.True(t, vs.IndexOf(parallelTestValidator) >= 0)
+		assert.True(t, vs.IndexOf(addr(7)) >= 0)
+	}
+}
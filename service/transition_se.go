@@ -22,39 +22,46 @@ func (t *transition) executeTxsSequential(l module.TransactionList, ctx contract
 		}
 		txo := txi.(transaction.Transaction)
 		t.log.Tracef("START TX <0x%x>", txo.ID())
-		for trial := 0; ; trial++ {
-			txh, err := txo.GetHandler(t.cm)
-			if err != nil {
-				t.log.Errorf("Fail to GetHandler err=%+v", err)
-				return err
-			}
-			ctx.SetTransactionInfo(&state.TransactionInfo{
-				Group:     txo.Group(),
-				Index:     int32(cnt),
-				Timestamp: txo.Timestamp(),
-				Nonce:     txo.Nonce(),
-				Hash:      txo.ID(),
-				From:      txo.From(),
-			})
-			ctx.UpdateSystemInfo()
-			rct, err := txh.Execute(ctx, false)
-			txh.Dispose()
-			if err == nil {
-				rctBuf[cnt] = rct
-				break
-			}
-			if !errors.ExecutionFailError.Equals(err) {
-				t.log.Warnf("Fail to execute transaction err=%+v", err)
-				return err
-			}
-			if trial == RetryCount {
-				t.log.Warnf("Fail to execute transaction retry=%d err=%+v", trial, err)
-				return err
-			}
-			t.log.Warnf("RETRY TX <%#x> for err=%+v", txo.ID(), err)
+		rct, err := t.executeTx(ctx, txo, cnt)
+		if err != nil {
+			return err
 		}
+		rctBuf[cnt] = rct
 		t.log.Tracef("END   TX <0x%x>", txo.ID())
 		cnt++
 	}
 	return nil
 }
+
+func (t *transition) executeTx(ctx contract.Context, txo transaction.Transaction, idx int) (txresult.Receipt, error) {
+	for trial := 0; ; trial++ {
+		txh, err := txo.GetHandler(t.cm)
+		if err != nil {
+			t.log.Errorf("Fail to GetHandler err=%+v", err)
+			return nil, err
+		}
+		ctx.SetTransactionInfo(&state.TransactionInfo{
+			Group:     txo.Group(),
+			Index:     int32(idx),
+			Timestamp: txo.Timestamp(),
+			Nonce:     txo.Nonce(),
+			Hash:      txo.ID(),
+			From:      txo.From(),
+		})
+		ctx.UpdateSystemInfo()
+		rct, err := txh.Execute(ctx, false)
+		txh.Dispose()
+		if err == nil {
+			return rct, nil
+		}
+		if !errors.ExecutionFailError.Equals(err) {
+			t.log.Warnf("Fail to execute transaction err=%+v", err)
+			return nil, err
+		}
+		if trial == RetryCount {
+			t.log.Warnf("Fail to execute transaction retry=%d err=%+v", trial, err)
+			return nil, err
+		}
+		t.log.Warnf("RETRY TX <%#x> for err=%+v", txo.ID(), err)
+	}
+}
@@ -0,0 +1,62 @@
+// Package transitionresult decodes the result of the transition stored in
+// the blocks. It has few dependencies, so the light clients and the tools
+// inspecting blocks can use it without the service manager.
+package transitionresult
+
+import (
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+// Result is the result of the transition, which has the hashes of the world
+// state and the receipt lists.
+type Result struct {
+	StateHash         []byte
+	PatchReceiptHash  []byte
+	NormalReceiptHash []byte
+}
+
+func NewFromBytes(bs []byte) (*Result, error) {
+	tresult := new(Result)
+	if _, err := codec.UnmarshalFromBytes(bs, tresult); err != nil {
+		return nil, err
+	}
+	return tresult, nil
+}
+
+func (tr *Result) Bytes() []byte {
+	if bs, err := codec.MarshalToBytes(tr); err != nil {
+		log.Debug("Fail to marshal transition result")
+		return nil
+	} else {
+		return bs
+	}
+}
+
+// ReceiptHash returns the hash of the receipt list of the group.
+func (tr *Result) ReceiptHash(group module.TransactionGroup) []byte {
+	if group == module.TransactionGroupPatch {
+		return tr.PatchReceiptHash
+	}
+	return tr.NormalReceiptHash
+}
+
+// StateHashOf returns the hash of the world state in the result.
+func StateHashOf(result []byte) ([]byte, error) {
+	tr, err := NewFromBytes(result)
+	if err != nil {
+		return nil, err
+	}
+	return tr.StateHash, nil
+}
+
+// ReceiptHashOf returns the hash of the receipt list of the group in the
+// result.
+func ReceiptHashOf(result []byte, group module.TransactionGroup) ([]byte, error) {
+	tr, err := NewFromBytes(result)
+	if err != nil {
+		return nil, err
+	}
+	return tr.ReceiptHash(group), nil
+}
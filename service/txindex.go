@@ -0,0 +1,231 @@
+/*
+ * Copyright 2020 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+const (
+	keyTxIndexStart = "service.txIndexStart"
+
+	txIndexAddressSize  = 21
+	txIndexLocationSize = 12
+
+	// txIndexPruneChunk is number of blocks to prune at once.
+	txIndexPruneChunk = 100
+)
+
+// addressesOfTx returns addresses sending or receiving the transaction.
+func addressesOfTx(tx module.Transaction) []module.Address {
+	var addrs []module.Address
+	if from := tx.From(); from != nil {
+		addrs = append(addrs, from)
+	}
+	if ttx, ok := tx.(transaction.Transaction); ok {
+		if to := ttx.To(); to != nil && (len(addrs) == 0 || !to.Equal(addrs[0])) {
+			addrs = append(addrs, to)
+		}
+	}
+	return addrs
+}
+
+// txIndexKey returns the key for the transaction. Height and index are
+// inverted, so the entries of an address are sorted from the latest one.
+func txIndexKey(addr module.Address, height int64, idx int) []byte {
+	key := make([]byte, txIndexAddressSize+txIndexLocationSize)
+	copy(key, addr.Bytes())
+	loc := key[txIndexAddressSize:]
+	binary.BigEndian.PutUint64(loc[0:8], uint64(math.MaxInt64-height))
+	binary.BigEndian.PutUint32(loc[8:12], uint32(math.MaxInt32-idx))
+	return key
+}
+
+func txLocationFromKey(key []byte) (int64, int) {
+	loc := key[txIndexAddressSize:]
+	height := math.MaxInt64 - int64(binary.BigEndian.Uint64(loc[0:8]))
+	idx := math.MaxInt32 - int(binary.BigEndian.Uint32(loc[8:12]))
+	return height, idx
+}
+
+// txIndex maps addresses to the normal transactions sent from or to them.
+//
+//	key   : address(21) | ^height(8) | ^txIndex(4)
+//	value : hash of the transaction
+//
+// The height of the first indexed block is stored in the chain property.
+// Entries below the height are ignored, and removed on pruning.
+type txIndex struct {
+	database db.Database
+	bucket   db.Bucket
+	props    db.Bucket
+}
+
+func newTxIndex(database db.Database) (*txIndex, error) {
+	bk, err := database.GetBucket(db.TransactionLocatorByAddress)
+	if err != nil {
+		return nil, err
+	}
+	props, err := database.GetBucket(db.ChainProperty)
+	if err != nil {
+		return nil, err
+	}
+	return &txIndex{
+		database: database,
+		bucket:   bk,
+		props:    props,
+	}, nil
+}
+
+// clearTxIndexStart removes the height of the first indexed block, so the
+// index starts again from the next block when it's enabled later.
+func clearTxIndexStart(database db.Database) error {
+	props, err := database.GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	return props.Delete([]byte(keyTxIndexStart))
+}
+
+// StartHeight returns the height of the first indexed block. It returns
+// false if no block has been indexed.
+func (ti *txIndex) StartHeight() (int64, bool, error) {
+	bs, err := ti.props.Get([]byte(keyTxIndexStart))
+	if err != nil || bs == nil {
+		return 0, false, err
+	}
+	var height int64
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &height); err != nil {
+		return 0, false, err
+	}
+	return height, true, nil
+}
+
+// Add writes entries for the normal transactions in the block of the height.
+func (ti *txIndex) Add(height int64, txs module.TransactionList) error {
+	batch := ti.database.NewBatch()
+	if _, ok, err := ti.StartHeight(); err != nil {
+		return err
+	} else if !ok {
+		batch.Set(db.ChainProperty, []byte(keyTxIndexStart),
+			codec.BC.MustMarshalToBytes(height))
+	}
+	if txs != nil {
+		for it := txs.Iterator(); it.Has(); it.Next() {
+			tx, idx, err := it.Get()
+			if err != nil {
+				return err
+			}
+			for _, addr := range addressesOfTx(tx) {
+				batch.Set(db.TransactionLocatorByAddress,
+					txIndexKey(addr, height, idx), tx.ID())
+			}
+		}
+	}
+	return batch.Write()
+}
+
+// Find returns locations of the transactions of the address from the latest
+// one. It returns up to limit locations before the location if before isn't
+// nil.
+func (ti *txIndex) Find(addr module.Address, before *module.TransactionLocation, limit int) ([]module.TransactionLocation, error) {
+	start, ok, err := ti.StartHeight()
+	if err != nil || !ok {
+		return nil, err
+	}
+	prefix := addr.Bytes()
+	var from []byte
+	if before != nil {
+		from = txIndexKey(addr, before.Height, before.TxIndex)
+	}
+	iter := ti.bucket.Iterator(prefix, from)
+	defer iter.Release()
+
+	var locs []module.TransactionLocation
+	for len(locs) < limit && iter.Next() {
+		key := iter.Key()
+		if len(key) != txIndexAddressSize+txIndexLocationSize {
+			continue
+		}
+		height, idx := txLocationFromKey(key)
+		if before != nil && height == before.Height && idx == before.TxIndex {
+			continue
+		}
+		if height < start {
+			break
+		}
+		locs = append(locs, module.TransactionLocation{
+			Height:  height,
+			TxIndex: idx,
+			ID:      iter.Value(),
+		})
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return locs, nil
+}
+
+// Prune removes entries for the blocks below the height. It reads the
+// transactions of the blocks to get keys of the entries, and updates the
+// start height for every chunk, so it can be resumed after interruption.
+func (ti *txIndex) Prune(height int64, bm module.BlockManager) error {
+	start, ok, err := ti.StartHeight()
+	if err != nil || !ok {
+		return err
+	}
+	for start < height {
+		end := start + txIndexPruneChunk
+		if end > height {
+			end = height
+		}
+		batch := ti.database.NewBatch()
+		for h := start; h < end; h++ {
+			blk, err := bm.GetBlockByHeight(h)
+			if err != nil {
+				if errors.NotFoundError.Equals(err) {
+					continue
+				}
+				return err
+			}
+			for it := blk.NormalTransactions().Iterator(); it.Has(); it.Next() {
+				tx, idx, err := it.Get()
+				if err != nil {
+					return err
+				}
+				for _, addr := range addressesOfTx(tx) {
+					batch.Delete(db.TransactionLocatorByAddress,
+						txIndexKey(addr, h, idx))
+				}
+			}
+		}
+		batch.Set(db.ChainProperty, []byte(keyTxIndexStart),
+			codec.BC.MustMarshalToBytes(end))
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+)
+
+type mockTransactionList []module.Transaction
+
+func (l mockTransactionList) Get(i int) (module.Transaction, error) {
+	return l[i], nil
+}
+
+func (l mockTransactionList) Iterator() module.TransactionIterator {
+	return &mockTransactionIterator{list: l}
+}
+
+func (l mockTransactionList) Hash() []byte {
+	panic("implement me")
+}
+
+func (l mockTransactionList) Equal(module.TransactionList) bool {
+	panic("implement me")
+}
+
+func (l mockTransactionList) Flush() error {
+	return nil
+}
+
+type mockTransactionIterator struct {
+	list mockTransactionList
+	idx  int
+}
+
+func (i *mockTransactionIterator) Has() bool {
+	return i.idx < len(i.list)
+}
+
+func (i *mockTransactionIterator) Next() error {
+	i.idx++
+	return nil
+}
+
+func (i *mockTransactionIterator) Get() (module.Transaction, int, error) {
+	return i.list[i.idx], i.idx, nil
+}
+
+func TestTxIndex_AddAndFind(t *testing.T) {
+	database := db.NewMapDB()
+	ti, err := newTxIndex(database)
+	assert.NoError(t, err)
+
+	addr1 := common.NewAddressFromString("hx1111111111111111111111111111111111111111")
+	addr2 := common.NewAddressFromString("hx2222222222222222222222222222222222222222")
+	score := common.NewAddressFromString("cx3333333333333333333333333333333333333333")
+
+	for height := int64(5); height < 8; height++ {
+		tx1 := newMockTransaction([]byte{byte(height), 1}, addr1, 0)
+		tx1.to = addr2
+		tx2 := newMockTransaction([]byte{byte(height), 2}, addr2, 0)
+		tx2.to = score
+		tx3 := newMockTransaction([]byte{byte(height), 3}, addr1, 0)
+		tx3.to = addr1
+		txs := mockTransactionList{tx1, tx2, tx3}
+		assert.NoError(t, ti.Add(height, txs))
+	}
+
+	start, ok, err := ti.StartHeight()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 5, start)
+
+	// latest one comes first, and self transfer is indexed once
+	locs, err := ti.Find(addr1, nil, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []module.TransactionLocation{
+		{Height: 7, TxIndex: 2, ID: []byte{7, 3}},
+		{Height: 7, TxIndex: 0, ID: []byte{7, 1}},
+		{Height: 6, TxIndex: 2, ID: []byte{6, 3}},
+		{Height: 6, TxIndex: 0, ID: []byte{6, 1}},
+		{Height: 5, TxIndex: 2, ID: []byte{5, 3}},
+		{Height: 5, TxIndex: 0, ID: []byte{5, 1}},
+	}, locs)
+
+	// pages with the limit
+	locs, err = ti.Find(score, nil, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []module.TransactionLocation{
+		{Height: 7, TxIndex: 1, ID: []byte{7, 2}},
+		{Height: 6, TxIndex: 1, ID: []byte{6, 2}},
+	}, locs)
+	locs, err = ti.Find(score, &locs[1], 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []module.TransactionLocation{
+		{Height: 5, TxIndex: 1, ID: []byte{5, 2}},
+	}, locs)
+
+	// both of sender and receiver
+	locs, err = ti.Find(addr2, nil, 100)
+	assert.NoError(t, err)
+	assert.Len(t, locs, 6)
+}
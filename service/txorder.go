@@ -0,0 +1,80 @@
+package service
+
+import (
+	"math/big"
+
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+const (
+	TxOrderFIFO     = "fifo"
+	TxOrderPriority = "priority"
+	TxOrderDefault  = TxOrderFIFO
+)
+
+var TxOrderOptions = [...]string{
+	TxOrderFIFO, TxOrderPriority,
+}
+
+func IsTxOrderOption(s string) bool {
+	for _, k := range TxOrderOptions {
+		if k == s {
+			return true
+		}
+	}
+	return false
+}
+
+// txOrder is the ordering policy of transactions in the pool.
+// Regardless of the policy, transactions of a sender are kept in
+// the order of their timestamps.
+type txOrder interface {
+	// Prior returns whether tx1 should be placed in front of tx2.
+	// It's also used to decide whether tx1 can replace or evict tx2.
+	Prior(tx1, tx2 transaction.Transaction) bool
+
+	// Conflict returns whether tx1 and tx2 of the same sender can't be
+	// in the pool together.
+	Conflict(tx1, tx2 transaction.Transaction) bool
+}
+
+// fifoOrder keeps transactions in the order of arrival.
+type fifoOrder struct{}
+
+func (fifoOrder) Prior(tx1, tx2 transaction.Transaction) bool {
+	return false
+}
+
+func (fifoOrder) Conflict(tx1, tx2 transaction.Transaction) bool {
+	return false
+}
+
+// priorityOrder puts transactions with higher step limit in front.
+// A transaction with the same nonce of a pending one from the same sender
+// replaces it if it has higher step limit.
+type priorityOrder struct{}
+
+func stepLimitOf(tx transaction.Transaction) *big.Int {
+	if v := tx.StepLimit(); v != nil {
+		return v
+	}
+	return new(big.Int)
+}
+
+func (priorityOrder) Prior(tx1, tx2 transaction.Transaction) bool {
+	return stepLimitOf(tx1).Cmp(stepLimitOf(tx2)) > 0
+}
+
+func (priorityOrder) Conflict(tx1, tx2 transaction.Transaction) bool {
+	n1, n2 := tx1.Nonce(), tx2.Nonce()
+	return n1 != nil && n2 != nil && n1.Cmp(n2) == 0
+}
+
+func newTxOrder(name string) txOrder {
+	switch name {
+	case TxOrderPriority:
+		return priorityOrder{}
+	default:
+		return fifoOrder{}
+	}
+}
@@ -0,0 +1,34 @@
+package txresult
+
+import (
+	"github.com/icon-project/goloop/common"
+)
+
+// feePayment is the part of the fee of the transaction paid by the payer
+// instead of the sender.
+type feePayment struct {
+	Payer    common.Address
+	StepUsed common.HexInt
+}
+
+type feePaymentJSON struct {
+	Address  common.Address `json:"address"`
+	StepUsed common.HexInt  `json:"stepUsed"`
+}
+
+func (p *feePayment) Equal(p2 *feePayment) bool {
+	if p == p2 {
+		return true
+	}
+	if p == nil || p2 == nil {
+		return false
+	}
+	return p.Payer.Equal(&p2.Payer) && p.StepUsed.Cmp(&p2.StepUsed.Int) == 0
+}
+
+func (p *feePayment) ToJSON() *feePaymentJSON {
+	return &feePaymentJSON{
+		Address:  p.Payer,
+		StepUsed: p.StepUsed,
+	}
+}
@@ -0,0 +1,82 @@
+package txresult
+
+import (
+	"math/big"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+)
+
+type internalTxData struct {
+	From  common.Address
+	To    common.Address
+	Value common.HexInt
+	Depth int
+}
+
+type internalTransaction struct {
+	internalTxData
+}
+
+func NewInternalTransaction(from, to module.Address, value *big.Int, depth int) module.InternalTransaction {
+	itx := new(internalTransaction)
+	itx.internalTxData.From.SetBytes(from.Bytes())
+	itx.internalTxData.To.SetBytes(to.Bytes())
+	itx.internalTxData.Value.Set(value)
+	itx.internalTxData.Depth = depth
+	return itx
+}
+
+func (itx *internalTransaction) From() module.Address {
+	return &itx.internalTxData.From
+}
+
+func (itx *internalTransaction) To() module.Address {
+	return &itx.internalTxData.To
+}
+
+func (itx *internalTransaction) Value() *big.Int {
+	return new(big.Int).Set(&itx.internalTxData.Value.Int)
+}
+
+func (itx *internalTransaction) Depth() int {
+	return itx.internalTxData.Depth
+}
+
+func (itx *internalTransaction) ToJSON(version module.JSONVersion) (interface{}, error) {
+	return map[string]interface{}{
+		"from":  &itx.internalTxData.From,
+		"to":    &itx.internalTxData.To,
+		"value": &itx.internalTxData.Value,
+		"depth": intconv.FormatInt(int64(itx.internalTxData.Depth)),
+	}, nil
+}
+
+// InternalTransactionsToBytes returns bytes for storing internal
+// transactions of a transaction.
+func InternalTransactionsToBytes(itxs []module.InternalTransaction) []byte {
+	data := make([]*internalTxData, len(itxs))
+	for i, itx := range itxs {
+		obj, ok := itx.(*internalTransaction)
+		if !ok {
+			obj = NewInternalTransaction(itx.From(), itx.To(), itx.Value(),
+				itx.Depth()).(*internalTransaction)
+		}
+		data[i] = &obj.internalTxData
+	}
+	return codec.BC.MustMarshalToBytes(data)
+}
+
+func InternalTransactionsFromBytes(bs []byte) ([]module.InternalTransaction, error) {
+	var data []*internalTxData
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &data); err != nil {
+		return nil, err
+	}
+	itxs := make([]module.InternalTransaction, len(data))
+	for i, d := range data {
+		itxs[i] = &internalTransaction{*d}
+	}
+	return itxs, nil
+}
@@ -115,16 +115,46 @@ func (log *eventLog) ToJSON(module.JSONVersion) (*eventLogJSON, error) {
 	return eljson, nil
 }
 
+// NewEventLog returns the event log of the values. It's used for the events
+// not in the receipt yet.
+func NewEventLog(addr module.Address, indexed, data [][]byte) module.EventLog {
+	el := new(eventLog)
+	el.eventLogData.Addr.SetBytes(addr.Bytes())
+	el.eventLogData.Indexed = indexed
+	el.eventLogData.Data = data
+	return el
+}
+
+// EventLogToJSON returns the JSON object of the event log, which has same
+// fields as the one in the eventLogs of the receipt.
+func EventLogToJSON(ev module.EventLog, version module.JSONVersion) (map[string]interface{}, error) {
+	el, ok := ev.(*eventLog)
+	if !ok {
+		return nil, errors.InvalidStateError.Errorf("UnknownEventLog(type=%T)", ev)
+	}
+	js, err := el.ToJSON(version)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"scoreAddress": js.Addr,
+		"indexed":      js.Indexed,
+		"data":         js.Data,
+	}, nil
+}
+
 type Version int
 
 const (
 	Version1 Version = iota
 	Version2
-	LastVersion = Version2
+	Version3
+	LastVersion = Version3
 )
 const (
 	listItemsForVersion1 = 8
 	listItemsForVersion2 = 9
+	listItemsForVersion3 = 10
 )
 
 type receiptData struct {
@@ -154,9 +184,21 @@ type receipt struct {
 	db        db.Database
 	data      receiptData
 	eventLogs trie.ImmutableForObject
+
+	// feePayment is the part of the fee paid by the deposit of the SCORE.
+	// It's stored only in receipts of Version3.
+	feePayment *feePayment
+
+	// internalTxs and stepDetails are kept only in memory, and they are
+	// not a part of the receipt data.
+	internalTxs []module.InternalTransaction
+	stepDetails StepUsedDetails
 }
 
 func (r *receipt) SCOREAddress() module.Address {
+	if r.data.SCOREAddress == nil {
+		return nil
+	}
 	return r.data.SCOREAddress
 }
 
@@ -179,7 +221,7 @@ func (r *receipt) Reset(s db.Database, k []byte) error {
 }
 
 func (r *receipt) Flush() error {
-	if r.version == Version2 {
+	if r.version >= Version2 {
 		if ss, ok := r.eventLogs.(trie.SnapshotForObject); ok {
 			return ss.Flush()
 		}
@@ -197,7 +239,7 @@ func (r *receipt) Equal(o trie.Object) bool {
 }
 
 func (r *receipt) ClearCache() {
-	if r.version == Version2 {
+	if r.version >= Version2 {
 		r.eventLogs.ClearCache()
 	}
 }
@@ -205,7 +247,7 @@ func (r *receipt) ClearCache() {
 func (r *receipt) RLPEncodeSelf(e codec.Encoder) error {
 	if r.version == Version1 {
 		return e.Encode(&r.data)
-	} else {
+	} else if r.version == Version2 {
 		hash := r.eventLogs.Hash()
 		return e.EncodeListOf(
 			r.data.Status,
@@ -217,6 +259,19 @@ func (r *receipt) RLPEncodeSelf(e codec.Encoder) error {
 			r.data.EventLogs,
 			r.data.SCOREAddress,
 			hash)
+	} else {
+		hash := r.eventLogs.Hash()
+		return e.EncodeListOf(
+			r.data.Status,
+			&r.data.To,
+			&r.data.CumulativeStepUsed,
+			&r.data.StepUsed,
+			&r.data.StepPrice,
+			&r.data.LogsBloom,
+			r.data.EventLogs,
+			r.data.SCOREAddress,
+			hash,
+			r.feePayment)
 	}
 }
 
@@ -235,12 +290,17 @@ func (r *receipt) RLPDecodeSelf(d codec.Decoder) error {
 		&r.data.LogsBloom,
 		&r.data.EventLogs,
 		&r.data.SCOREAddress,
-		&hash); err == nil || err == io.EOF {
+		&hash,
+		&r.feePayment); err == nil || err == io.EOF {
 		if cnt == listItemsForVersion1 {
 			r.version = Version1
 			r.eventLogs = nil
-		} else if cnt == listItemsForVersion2 {
-			r.version = Version2
+		} else if cnt == listItemsForVersion2 || cnt == listItemsForVersion3 {
+			if cnt == listItemsForVersion2 {
+				r.version = Version2
+			} else {
+				r.version = Version3
+			}
 			r.eventLogs = trie_manager.NewImmutableForObject(r.db, hash,
 				reflect.TypeOf((*eventLog)(nil)))
 		} else {
@@ -253,7 +313,7 @@ func (r *receipt) RLPDecodeSelf(d codec.Decoder) error {
 }
 
 func (r *receipt) Resolve(bd merkle.Builder) error {
-	if r.version == Version2 {
+	if r.version >= Version2 {
 		r.eventLogs.Resolve(bd)
 	}
 	return nil
@@ -264,14 +324,14 @@ func (r *receipt) LogsBloom() module.LogsBloom {
 }
 
 func (r *receipt) EventLogIterator() module.EventLogIterator {
-	if r.version == Version2 {
+	if r.version >= Version2 {
 		return &eventLogIteratorV2{r.eventLogs.Iterator()}
 	}
 	return &eventLogIterator{r.data.EventLogs, 0}
 }
 
 func (r *receipt) GetProofOfEvent(i int) ([][]byte, error) {
-	if r.version != Version2 {
+	if r.version < Version2 {
 		return nil, errors.ErrInvalidState
 	}
 	k := codec.BC.MustMarshalToBytes(uint(i))
@@ -282,6 +342,26 @@ func (r *receipt) GetProofOfEvent(i int) ([][]byte, error) {
 	return proof, nil
 }
 
+// ProveEvent verifies the proof of the event at the index in the receipt,
+// and returns the event. Only receipts of Version2 or later have proofs of
+// events.
+func ProveEvent(r module.Receipt, i int, proof [][]byte) (module.EventLog, error) {
+	rct, ok := r.(*receipt)
+	if !ok || rct.version < Version2 {
+		return nil, errors.InvalidStateError.New("NoEventProofForReceipt")
+	}
+	k := codec.BC.MustMarshalToBytes(uint(i))
+	obj, err := rct.eventLogs.Prove(k, proof)
+	if err != nil {
+		return nil, err
+	}
+	if ev, ok := obj.(module.EventLog); !ok {
+		return nil, errors.NotFoundError.Errorf("EventNotFound(idx=%d)", i)
+	} else {
+		return ev, nil
+	}
+}
+
 type eventLogIteratorV2 struct {
 	trie.IteratorForObject
 }
@@ -343,6 +423,12 @@ type Receipt interface {
 	AddLog(addr module.Address, indexed, data [][]byte)
 	SetCumulativeStepUsed(cumulativeUsed *big.Int)
 	SetResult(status module.Status, used, price *big.Int, addr module.Address)
+	AddInternalTransaction(from, to module.Address, value *big.Int, depth int)
+	InternalTransactions() []module.InternalTransaction
+	SetFeePayment(payer module.Address, steps *big.Int)
+	FeePayment() (module.Address, *big.Int)
+	SetStepUsedDetails(details StepUsedDetails)
+	StepUsedDetails() StepUsedDetails
 }
 
 type receiptJSON struct {
@@ -355,6 +441,7 @@ type receiptJSON struct {
 	EventLogs          []*eventLogJSON  `json:"eventLogs"`
 	LogsBloom          LogsBloom        `json:"logsBloom"`
 	Status             common.HexUint16 `json:"status"`
+	FeePayer           *feePaymentJSON  `json:"feePayer,omitempty"`
 }
 
 func (r *receipt) ToJSON(version module.JSONVersion) (interface{}, error) {
@@ -389,6 +476,9 @@ func (r *receipt) ToJSON(version module.JSONVersion) (interface{}, error) {
 		jso["status"] = "0x0"
 		jso["failure"] = failureReasonByCode(r.data.Status)
 	}
+	if r.feePayment != nil {
+		jso["feePayer"] = r.feePayment.ToJSON()
+	}
 	return jso, nil
 }
 
@@ -430,6 +520,9 @@ func (r *receipt) UnmarshalJSON(bs []byte) error {
 	if r.version >= Version2 {
 		r.buildMerkleListOfLogs()
 	}
+	if rjson.FeePayer != nil && r.version >= Version3 {
+		r.SetFeePayment(&rjson.FeePayer.Address, &rjson.FeePayer.StepUsed.Int)
+	}
 	return nil
 }
 
@@ -443,6 +536,40 @@ func (r *receipt) AddLog(addr module.Address, indexed, data [][]byte) {
 	r.data.LogsBloom.AddLog(&log.eventLogData.Addr, log.eventLogData.Indexed)
 }
 
+func (r *receipt) AddInternalTransaction(from, to module.Address, value *big.Int, depth int) {
+	r.internalTxs = append(r.internalTxs,
+		NewInternalTransaction(from, to, value, depth))
+}
+
+func (r *receipt) InternalTransactions() []module.InternalTransaction {
+	return r.internalTxs
+}
+
+func (r *receipt) SetFeePayment(payer module.Address, steps *big.Int) {
+	if r.version < Version3 {
+		log.Panicf("FeePaymentIsNotSupported(version=%d)", r.version)
+	}
+	fp := new(feePayment)
+	fp.Payer.SetBytes(payer.Bytes())
+	fp.StepUsed.Set(steps)
+	r.feePayment = fp
+}
+
+func (r *receipt) FeePayment() (module.Address, *big.Int) {
+	if r.feePayment == nil {
+		return nil, nil
+	}
+	return &r.feePayment.Payer, new(big.Int).Set(&r.feePayment.StepUsed.Int)
+}
+
+func (r *receipt) SetStepUsedDetails(details StepUsedDetails) {
+	r.stepDetails = details
+}
+
+func (r *receipt) StepUsedDetails() StepUsedDetails {
+	return r.stepDetails
+}
+
 func (r *receipt) SetCumulativeStepUsed(cumulativeUsed *big.Int) {
 	r.data.CumulativeStepUsed.Set(cumulativeUsed)
 }
@@ -467,7 +594,7 @@ func (r *receipt) SetResult(status module.Status, used, price *big.Int, addr mod
 	}
 	r.data.StepUsed.Set(used)
 	r.data.StepPrice.Set(price)
-	if r.version == Version2 {
+	if r.version >= Version2 {
 		r.buildMerkleListOfLogs()
 	}
 }
@@ -505,7 +632,10 @@ func (r *receipt) Check(r2 module.Receipt) error {
 	if r.version != rct2.version {
 		return errors.InvalidStateError.New("VersionMismatch")
 	}
-	if r.version == Version2 {
+	if !r.feePayment.Equal(rct2.feePayment) {
+		return errors.InvalidStateError.New("DifferentFeePayment")
+	}
+	if r.version >= Version2 {
 		if !r.eventLogs.Equal(rct2.eventLogs, true) {
 			return errors.InvalidStateError.New("DifferentEventLogs")
 		}
@@ -518,7 +648,9 @@ func (r *receipt) Check(r2 module.Receipt) error {
 }
 
 func versionForRevision(revision int) Version {
-	if revision >= module.Revision7 {
+	if revision >= module.Revision9 {
+		return Version3
+	} else if revision >= module.Revision7 {
 		return Version2
 	} else {
 		return Version1
@@ -55,6 +55,45 @@ func testReceiptJSONByRev(t *testing.T, rev int) {
 	assert.Equal(t, r3.Bytes(), r2.Bytes())
 }
 
+func TestReceipt_FeePayment(t *testing.T) {
+	database := db.NewMapDB()
+	addr := common.NewAddressFromString("cx0000000000000000000000000000000000000001")
+
+	r := NewReceipt(database, module.Revision9, addr)
+	r.SetFeePayment(addr, big.NewInt(40))
+	r.SetResult(module.StatusSuccess, big.NewInt(100), big.NewInt(1000), nil)
+	r.SetCumulativeStepUsed(big.NewInt(100))
+
+	payer, steps := r.FeePayment()
+	assert.True(t, addr.Equal(payer))
+	assert.EqualValues(t, 40, steps.Int64())
+
+	r2 := new(receipt)
+	assert.NoError(t, r2.Reset(database, r.Bytes()))
+	assert.Equal(t, Version3, r2.version)
+	assert.NoError(t, r.Check(r2))
+	payer, steps = r2.FeePayment()
+	assert.True(t, addr.Equal(payer))
+	assert.EqualValues(t, 40, steps.Int64())
+
+	jso, err := r.ToJSON(module.JSONVersionLast)
+	assert.NoError(t, err)
+	jb, err := json.Marshal(jso)
+	assert.NoError(t, err)
+	r3, err := NewReceiptFromJSON(database, module.Revision9, jb)
+	assert.NoError(t, err)
+	assert.Equal(t, r.Bytes(), r3.Bytes())
+
+	// without fee payment
+	r4 := NewReceipt(database, module.Revision9, addr)
+	r4.SetResult(module.StatusSuccess, big.NewInt(100), big.NewInt(1000), nil)
+	r4.SetCumulativeStepUsed(big.NewInt(100))
+	assert.Error(t, r.Check(r4))
+	payer, steps = r4.FeePayment()
+	assert.Nil(t, payer)
+	assert.Nil(t, steps)
+}
+
 func Test_EventLog_BytesEncoding(t *testing.T) {
 	var ev eventLog
 
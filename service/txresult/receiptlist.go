@@ -96,6 +96,26 @@ func NewReceiptListFromHash(database db.Database, h []byte) module.ReceiptList {
 	return &receiptList{immutable}
 }
 
+// ProveReceipt verifies the proof of the receipt at the index in the receipt
+// list of the hash, and returns the receipt. Nodes of the proof are stored
+// in the database, so events of the receipt can be proven with them.
+func ProveReceipt(database db.Database, h []byte, n int, proof [][]byte) (module.Receipt, error) {
+	k, err := codec.BC.MarshalToBytes(uint(n))
+	if err != nil {
+		return nil, err
+	}
+	immutable := trie_manager.NewImmutableForObject(database, h, receiptType)
+	obj, err := immutable.Prove(k, proof)
+	if err != nil {
+		return nil, err
+	}
+	if rct, ok := obj.(module.Receipt); !ok {
+		return nil, errors.NotFoundError.Errorf("ReceiptNotFound(idx=%d)", n)
+	} else {
+		return rct, nil
+	}
+}
+
 func NewReceiptListWithBuilder(builder merkle.Builder, h []byte) module.ReceiptList {
 	database := builder.Database()
 	snapshot := trie_manager.NewImmutableForObject(database, h, receiptType)
@@ -7,6 +7,8 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/module"
@@ -65,3 +67,44 @@ func testReceiptListByRev(t *testing.T, rev int) {
 		idx++
 	}
 }
+
+func TestProveReceipt(t *testing.T) {
+	mdb := db.NewMapDB()
+	addr := common.NewAddressFromString("cx0003737589788888888888888888888888888888")
+
+	var used, price big.Int
+	rslice := make([]Receipt, 0)
+	for i := 0; i < 3; i++ {
+		r := NewReceipt(mdb, module.Revision7, addr)
+		r.AddLog(addr, [][]byte{[]byte("Event(int)")}, [][]byte{{byte(i)}})
+		r.AddLog(addr, [][]byte{[]byte("Event(int)")}, [][]byte{{byte(i + 1)}})
+		used.SetInt64(int64(i * 100))
+		price.SetInt64(10)
+		r.SetResult(module.StatusSuccess, &used, &price, nil)
+		rslice = append(rslice, r)
+	}
+	rl := NewReceiptListFromSlice(mdb, rslice)
+	rl.Flush()
+
+	proof, err := rl.GetProof(1)
+	assert.NoError(t, err)
+	r0, err := rl.Get(1)
+	assert.NoError(t, err)
+	eproof, err := r0.GetProofOfEvent(1)
+	assert.NoError(t, err)
+
+	pdb := db.NewMapDB()
+	rct, err := ProveReceipt(pdb, rl.Hash(), 1, proof)
+	assert.NoError(t, err)
+	assert.Equal(t, rslice[1].Bytes(), rct.Bytes())
+
+	ev, err := ProveEvent(rct, 1, eproof)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{{2}}, ev.Data())
+
+	bad := append([][]byte{}, proof...)
+	bad[0] = append([]byte{}, proof[0]...)
+	bad[0][len(bad[0])-1] ^= 0x01
+	_, err = ProveReceipt(db.NewMapDB(), rl.Hash(), 1, bad)
+	assert.Error(t, err)
+}
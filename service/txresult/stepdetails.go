@@ -0,0 +1,78 @@
+package txresult
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/intconv"
+)
+
+// StepTypeExecution is the name for the steps which are not charged for
+// a specific step type, like the ones reported by the execution environment
+// for running the code of the SCORE.
+const StepTypeExecution = "execution"
+
+// StepUsedDetails maps names of step types to the amount of steps used for
+// them in a transaction.
+type StepUsedDetails map[string]*big.Int
+
+type stepUsedEntry struct {
+	Type  string
+	Steps common.HexInt
+}
+
+// NewStepUsedDetails returns details of the steps applied for the types.
+// The rest of the total steps is added as StepTypeExecution.
+func NewStepUsedDetails(applied map[string]*big.Int, total *big.Int) StepUsedDetails {
+	details := make(StepUsedDetails, len(applied)+1)
+	rest := new(big.Int).Set(total)
+	for t, steps := range applied {
+		details[t] = new(big.Int).Set(steps)
+		rest.Sub(rest, steps)
+	}
+	if rest.Sign() > 0 {
+		details[StepTypeExecution] = rest
+	}
+	return details
+}
+
+func (d StepUsedDetails) types() []string {
+	types := make([]string, 0, len(d))
+	for t := range d {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func (d StepUsedDetails) Bytes() []byte {
+	entries := make([]*stepUsedEntry, 0, len(d))
+	for _, t := range d.types() {
+		entry := &stepUsedEntry{Type: t}
+		entry.Steps.Set(d[t])
+		entries = append(entries, entry)
+	}
+	return codec.BC.MustMarshalToBytes(entries)
+}
+
+func StepUsedDetailsFromBytes(bs []byte) (StepUsedDetails, error) {
+	var entries []*stepUsedEntry
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &entries); err != nil {
+		return nil, err
+	}
+	details := make(StepUsedDetails, len(entries))
+	for _, entry := range entries {
+		details[entry.Type] = new(big.Int).Set(&entry.Steps.Int)
+	}
+	return details, nil
+}
+
+func (d StepUsedDetails) ToJSON() map[string]interface{} {
+	jso := make(map[string]interface{}, len(d))
+	for t, steps := range d {
+		jso[t] = intconv.FormatBigInt(steps)
+	}
+	return jso
+}
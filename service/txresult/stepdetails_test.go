@@ -0,0 +1,45 @@
+package txresult
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepUsedDetails_Basic(t *testing.T) {
+	applied := map[string]*big.Int{
+		"default":      big.NewInt(100000),
+		"input":        big.NewInt(1020),
+		"contractCall": big.NewInt(25000),
+	}
+	details := NewStepUsedDetails(applied, big.NewInt(145000))
+	assert.Len(t, details, 4)
+	assert.EqualValues(t, 18980, details[StepTypeExecution].Int64())
+
+	// applied values are copied
+	applied["default"].SetInt64(0)
+	assert.EqualValues(t, 100000, details["default"].Int64())
+
+	details2, err := StepUsedDetailsFromBytes(details.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, details.ToJSON(), details2.ToJSON())
+	assert.Equal(t, details.Bytes(), details2.Bytes())
+
+	assert.Equal(t, map[string]interface{}{
+		"default":      "0x186a0",
+		"input":        "0x3fc",
+		"contractCall": "0x61a8",
+		"execution":    "0x4a24",
+	}, details.ToJSON())
+}
+
+func TestStepUsedDetails_NoExecution(t *testing.T) {
+	applied := map[string]*big.Int{
+		"default": big.NewInt(100000),
+	}
+	details := NewStepUsedDetails(applied, big.NewInt(100000))
+	assert.Len(t, details, 1)
+	_, ok := details[StepTypeExecution]
+	assert.False(t, ok)
+}
@@ -52,6 +52,34 @@ func (_r *ChainBase) MaxBlockTxBytes() int {
 	panic("not implemented")
 }
 
+func (_r *ChainBase) TxPoolOrder() string {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) EventIndex() bool {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) TxIndex() bool {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) InternalTx() bool {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) StepDetails() bool {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) ParallelCheck() bool {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) QueryCacheSize() int {
+	panic("not implemented")
+}
+
 func (_r *ChainBase) DefaultWaitTimeout() time.Duration {
 	panic("not implemented")
 }
@@ -116,7 +144,19 @@ func (_r *ChainBase) Prune(gs string, dbt string, height int64) error {
 	panic("not implemented")
 }
 
-func (_r *ChainBase) Backup(file string, extra []string) error {
+func (_r *ChainBase) Backup(file string, base string, extra []string) error {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) StartGC(keep int64) error {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) PauseGC() error {
+	panic("not implemented")
+}
+
+func (_r *ChainBase) ResumeGC() error {
 	panic("not implemented")
 }
 
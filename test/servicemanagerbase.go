@@ -94,6 +94,14 @@ func (_r *ServiceManagerBase) GetTotalSupply(result []byte) (*big.Int, error) {
 	panic("not implemented")
 }
 
+func (_r *ServiceManagerBase) GetProofForAccount(result []byte, addr module.Address) ([]byte, [][]byte, error) {
+	panic("not implemented")
+}
+
+func (_r *ServiceManagerBase) GetProofForStorage(result []byte, addr module.Address, key []byte) ([]byte, [][]byte, error) {
+	panic("not implemented")
+}
+
 func (_r *ServiceManagerBase) GetNetworkID(result []byte) (int64, error) {
 	panic("not implemented")
 }
@@ -126,6 +134,50 @@ func (_r *ServiceManagerBase) SendTransactionAndWait(tx interface{}) ([]byte, <-
 	panic("not implemented")
 }
 
+func (_r *ServiceManagerBase) SubscribeTransactionPool(size int) (<-chan *module.TransactionPoolEvent, func()) {
+	panic("not implemented")
+}
+
+func (_r *ServiceManagerBase) GetPendingTransactions(from, to module.Address, limit int) []module.Transaction {
+	panic("not implemented")
+}
+
+func (_r *ServiceManagerBase) GetTransactionPoolStatus() []*module.TransactionPoolStatus {
+	panic("not implemented")
+}
+
+func (_r *ServiceManagerBase) GetEventIndexStart() (int64, error) {
+	panic("not implemented")
+}
+
+func (_r *ServiceManagerBase) FindEvents(term []byte, from, to int64, after *module.EventLocation, limit int) ([]module.EventLocation, error) {
+	panic("not implemented")
+}
+
+func (_r *ServiceManagerBase) GetTransactionIndexStart() (int64, error) {
+	panic("not implemented")
+}
+
+func (_r *ServiceManagerBase) FindTransactions(addr module.Address, before *module.TransactionLocation, limit int) ([]module.TransactionLocation, error) {
+	panic("not implemented")
+}
+
+func (_r *ServiceManagerBase) PruneTransactionIndex(height int64) error {
+	panic("not implemented")
+}
+
+func (_r *ServiceManagerBase) PruneEventIndex(height int64) error {
+	panic("not implemented")
+}
+
+func (_r *ServiceManagerBase) GetInternalTransactions(height int64, id []byte) ([]module.InternalTransaction, error) {
+	panic("not implemented")
+}
+
+func (_r *ServiceManagerBase) GetStepUsedDetails(height int64, id []byte) (map[string]*big.Int, error) {
+	panic("not implemented")
+}
+
 func (_r *ServiceManagerBase) WaitTransactionResult(id []byte) (<-chan interface{}, error) {
 	panic("not implemented")
 }
@@ -141,3 +193,7 @@ func (_r *ServiceManagerBase) ImportResult(result []byte, vh []byte, src db.Data
 func (_r *ServiceManagerBase) ExecuteTransaction(result []byte, vh []byte, js []byte, bi module.BlockInfo) (module.Receipt, error) {
 	panic("not implemented")
 }
+
+func (_r *ServiceManagerBase) SimulateTransactions(result []byte, vh []byte, txs [][]byte, bi module.BlockInfo) (*module.SimulationResult, error) {
+	panic("not implemented")
+}